@@ -0,0 +1,64 @@
+package cleed
+
+import (
+	"github.com/radulucut/cleed/internal/storage"
+	"github.com/radulucut/cleed/internal/utils"
+	"github.com/spf13/cobra"
+)
+
+func (r *Root) initArchive() {
+	cmd := &cobra.Command{
+		Use:   "archive",
+		Short: "Show or prune the item archive",
+		Long: `Show or prune the item archive
+
+Every item seen in a feed is archived, so searching and --since queries
+include items that are no longer published by the feed.
+
+Examples:
+  # Show the number of archived items per feed
+  cleed archive
+
+  # Prune the archive using the configured retention
+  cleed archive --prune
+
+  # Remove archived items older than 30 days
+  cleed archive --prune --older-than 30d
+
+  # Keep only the 100 newest archived items per feed
+  cleed archive --prune --keep 100
+`,
+
+		RunE: r.RunArchive,
+	}
+
+	flags := cmd.Flags()
+	flags.Bool("prune", false, "prune the archive")
+	flags.String("older-than", "", "remove archived items older than the duration (e.g. 30d)")
+	flags.Uint("keep", 0, "number of archived items to keep per feed")
+
+	r.Cmd.AddCommand(cmd)
+}
+
+func (r *Root) RunArchive(cmd *cobra.Command, args []string) error {
+	if !cmd.Flag("prune").Changed {
+		return r.feed.ShowArchive()
+	}
+	if !cmd.Flag("older-than").Changed && !cmd.Flag("keep").Changed {
+		return r.feed.PruneArchive(nil)
+	}
+	retention := &storage.ArchiveRetention{}
+	if cmd.Flag("older-than").Changed {
+		d, err := utils.ParseDuration(cmd.Flag("older-than").Value.String())
+		if err != nil {
+			return err
+		}
+		retention.MaxAge = d
+	}
+	keep, err := cmd.Flags().GetUint("keep")
+	if err != nil {
+		return err
+	}
+	retention.MaxCount = int(keep)
+	return r.feed.PruneArchive(retention)
+}
@@ -0,0 +1,154 @@
+package cleed
+
+import (
+	"bytes"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path"
+	"testing"
+	"time"
+
+	"github.com/radulucut/cleed/internal"
+	_storage "github.com/radulucut/cleed/internal/storage"
+	"github.com/radulucut/cleed/mocks"
+	"github.com/stretchr/testify/assert"
+	"go.uber.org/mock/gomock"
+)
+
+func Test_Archive(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	timeMock := mocks.NewMockTime(ctrl)
+	timeMock.EXPECT().Now().Return(defaultCurrentTime).AnyTimes()
+
+	out := new(bytes.Buffer)
+	printer := internal.NewPrinter(nil, out, out)
+	storage := _storage.NewLocalStorage("cleed_test", timeMock)
+	defer localStorageCleanup(t, storage)
+
+	configDir, err := os.UserConfigDir()
+	if err != nil {
+		t.Fatal(err)
+	}
+	listsDir := path.Join(configDir, "cleed_test", "lists")
+	err = os.MkdirAll(listsDir, 0700)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	rss := createRSS([]*FeedItem{
+		{
+			Title:     "Old item",
+			Link:      "https://rss-feed.com/old/",
+			Published: defaultCurrentTime.Add(-48 * time.Hour).Format(time.RFC1123Z),
+		},
+		{
+			Title:     "Item 1",
+			Link:      "https://rss-feed.com/item-1/",
+			Published: defaultCurrentTime.Add(-24 * time.Hour).Format(time.RFC1123Z),
+		},
+	})
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Write([]byte(rss))
+	}))
+	defer server.Close()
+
+	err = os.WriteFile(path.Join(listsDir, "default"),
+		fmt.Appendf(nil, "%d %s\n", defaultCurrentTime.Unix(), server.URL), 0600)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	feed := internal.NewTerminalFeed(timeMock, printer, storage)
+	root, err := NewRoot("0.1.0", timeMock, printer, storage, feed)
+	assert.NoError(t, err)
+
+	os.Args = []string{"cleed"}
+	err = root.Cmd.Execute()
+	assert.NoError(t, err)
+
+	// the feed no longer publishes the old item
+	rss = createRSS([]*FeedItem{
+		{
+			Title:     "Item 1",
+			Link:      "https://rss-feed.com/item-1/",
+			Published: defaultCurrentTime.Add(-24 * time.Hour).Format(time.RFC1123Z),
+		},
+	})
+	err = storage.SaveCacheInfo(map[string]*_storage.CacheInfoItem{
+		server.URL: {
+			URL:        server.URL,
+			LastFetch:  defaultCurrentTime,
+			FetchAfter: time.Unix(0, 0),
+		},
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	out.Reset()
+	os.Args = []string{"cleed", "--search", "old"}
+	err = root.Cmd.Execute()
+	assert.NoError(t, err)
+	assert.Equal(t, `RSS Feed    Old item
+2 days ago  https://rss-feed.com/old/
+
+`, out.String())
+
+	// archived items are not cached with the feed
+	root, err = NewRoot("0.1.0", timeMock, printer, storage, feed)
+	assert.NoError(t, err)
+
+	out.Reset()
+	os.Args = []string{"cleed", "-C"}
+	err = root.Cmd.Execute()
+	assert.NoError(t, err)
+	assert.Equal(t, `RSS Feed   Item 1
+1 day ago  https://rss-feed.com/item-1/
+
+`, out.String())
+
+	out.Reset()
+	os.Args = []string{"cleed", "archive"}
+	err = root.Cmd.Execute()
+	assert.NoError(t, err)
+	assert.Equal(t, fmt.Sprintf(`URL%s  Items  Oldest item
+%s  2      %s
+Total: 2 items from 1 feed
+`, string(bytes.Repeat([]byte(" "), len(server.URL)-3)), server.URL, defaultCurrentTime.Add(-48*time.Hour).Format("2006-01-02 15:04:05")), out.String())
+
+	out.Reset()
+	os.Args = []string{"cleed", "archive", "--prune", "--older-than", "1d12h"}
+	err = root.Cmd.Execute()
+	assert.NoError(t, err)
+	assert.Equal(t, "pruned 1 item from 1 feed\n", out.String())
+
+	archive, err := storage.LoadArchive(server.URL)
+	assert.NoError(t, err)
+	assert.Len(t, archive, 1)
+	assert.Equal(t, "Item 1", archive[0].Item.Title)
+}
+
+func Test_Archive_Prune_No_Retention(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	timeMock := mocks.NewMockTime(ctrl)
+	timeMock.EXPECT().Now().Return(defaultCurrentTime).AnyTimes()
+
+	out := new(bytes.Buffer)
+	printer := internal.NewPrinter(nil, out, out)
+	storage := _storage.NewLocalStorage("cleed_test", timeMock)
+	defer localStorageCleanup(t, storage)
+
+	feed := internal.NewTerminalFeed(timeMock, printer, storage)
+	root, err := NewRoot("0.1.0", timeMock, printer, storage, feed)
+	assert.NoError(t, err)
+
+	os.Args = []string{"cleed", "archive", "--prune"}
+	err = root.Cmd.Execute()
+	assert.EqualError(t, err, "no retention set. Use --older-than or --keep, or configure the archive retention")
+}
@@ -1,6 +1,10 @@
 package cleed
 
 import (
+	"strings"
+
+	"github.com/radulucut/cleed/internal"
+	"github.com/radulucut/cleed/internal/utils"
 	"github.com/spf13/cobra"
 )
 
@@ -20,6 +24,12 @@ Examples:
   # Disable styling
   cleed config --styling=2
 
+  # Always print titles as clickable hyperlinks (OSC 8)
+  cleed config --hyperlinks=1
+
+  # Use the solarized theme
+  cleed config --theme=solarized
+
   # Map color 0 to 230 and color 1 to 213
   cleed config --map-colors=0:230,1:213
 
@@ -35,6 +45,61 @@ Examples:
   # Enable run summary
   cleed config --summary=1
 
+  # Show items that appear in several feeds only once
+  cleed config --dedupe=1
+
+  # Display items on a single line
+  cleed config --layout=compact
+
+  # Add a template and use it as the layout
+  cleed config --template='short={{.Relative}}: {{.Title}} ({{.Feed}})'
+  cleed config --layout=short
+
+  # Add a template that numbers items with the index used by cleed open
+  cleed config --template='numbered={{.Index}}. {{.Title}} ({{.Relative}})'
+
+  # Remove a template
+  cleed config --template=short=
+
+  # Display long output with a specific pager
+  cleed config --pager="less -RS"
+
+  # Never use a pager
+  cleed config --pager=-
+
+  # Use $PAGER or less (default)
+  cleed config --pager=
+
+  # Open links in a specific browser. %s is replaced with the link
+  cleed config --open-command="firefox --new-tab %s"
+
+  # Download podcast episodes into a specific directory
+  cleed config --download-dir=~/Podcasts
+
+  # Download the 3 newest episodes of a followed feed when fetching it
+  cleed config --auto-download=https://example.com/podcast=3
+
+  # Stop downloading episodes of a feed
+  cleed config --auto-download=https://example.com/podcast=
+
+  # Play episodes with a specific media player
+  cleed config --player-command="mpv --no-video"
+
+  # Store the cache in an embedded database. Existing cache is migrated
+  cleed config --cache-backend=bolt
+
+  # Keep archived items for 90 days and at most 500 items per feed
+  cleed config --archive-max-age=90 --archive-max-count=500
+
+  # Stem English words and ignore stop words when searching
+  cleed config --search-language=english
+
+  # Rank search results from a feed higher (-5 to 5)
+  cleed config --feed-priority=https://example.com/feed=2
+
+  # Remove the priority of a feed
+  cleed config --feed-priority=https://example.com/feed=
+
   # Set the miniflux token
   cleed config --miniflux-token="your_token_here"
 `,
@@ -43,13 +108,28 @@ Examples:
 
 	flags := cmd.Flags()
 	flags.Uint8("styling", 0, "disable or enable styling (0: default, 1: enable, 2: disable)")
+	flags.Uint8("hyperlinks", 0, "detect, enable or disable clickable titles (0: detect, 1: enable, 2: disable)")
 	flags.Uint8("summary", 0, "disable or enable summary (0: disable, 1: enable)")
+	flags.String("theme", "default", "set the color theme ("+strings.Join(internal.Themes, ", ")+")")
 	flags.String("map-colors", "", "map colors to other colors, e.g. 0:230,1:213. Use --color-range to check available colors")
 	flags.Bool("color-range", false, "display color range. Useful for finding colors to map")
 	flags.String("user-agent", "", "set the user agent. Setting the value to '-' will not send the user agent")
 	flags.Uint("batch-size", 100, "set the batch (queue) size for fetching feeds")
-	flags.Uint("timeout", 30, "set the timeout in seconds for fetching feeds")
+	flags.Uint("timeout", 30, "set the timeout in seconds for fetching feeds, and for downloads that stop receiving data")
+	flags.String("cache-backend", "file", "set the cache backend (file, bolt)")
+	flags.Uint("archive-max-age", 0, "set the number of days to keep archived items (0: unlimited)")
+	flags.Uint("archive-max-count", 0, "set the number of archived items to keep per feed (0: unlimited)")
+	flags.String("search-language", "none", "set the language used to stem search terms (none, "+strings.Join(utils.Languages(), ", ")+")")
+	flags.String("feed-priority", "", "rank search results from a feed higher or lower, e.g. https://example.com/feed=2")
 	flags.Uint8("future-items", 1, "show or hide future items (0: hide, 1: show)")
+	flags.String("layout", "default", "set the layout of items ("+strings.Join(internal.Layouts, ", ")+" or a template name)")
+	flags.String("template", "", "add an item template, e.g. 'short={{.Title}} {{.Link}}'. An empty template removes it")
+	flags.Uint8("dedupe", 0, "show items that appear in several feeds only once (0: disable, 1: enable)")
+	flags.String("pager", "", "set the pager for long output. Empty uses $PAGER or less -R, '-' disables it")
+	flags.String("open-command", "", "set the command used to open links. Empty uses $BROWSER or the system opener")
+	flags.String("download-dir", "", "set the directory for downloaded enclosures. Empty uses ~/Downloads/cleed")
+	flags.String("auto-download", "", "download the newest enclosures of a feed when fetching it, e.g. https://example.com/podcast=3")
+	flags.String("player-command", "", "set the command used to play enclosures. Empty uses the system opener")
 	flags.String("miniflux-token", "", "set the miniflux token")
 
 	r.Cmd.AddCommand(cmd)
@@ -63,6 +143,28 @@ func (r *Root) RunConfig(cmd *cobra.Command, args []string) error {
 		}
 		return r.feed.SetStyling(styling)
 	}
+	if cmd.Flag("hyperlinks").Changed {
+		hyperlinks, err := cmd.Flags().GetUint8("hyperlinks")
+		if err != nil {
+			return err
+		}
+		return r.feed.SetHyperlinks(hyperlinks)
+	}
+	if cmd.Flag("pager").Changed {
+		return r.feed.SetPager(cmd.Flag("pager").Value.String())
+	}
+	if cmd.Flag("open-command").Changed {
+		return r.feed.SetOpenCommand(cmd.Flag("open-command").Value.String())
+	}
+	if cmd.Flag("download-dir").Changed {
+		return r.feed.SetDownloadDir(cmd.Flag("download-dir").Value.String())
+	}
+	if cmd.Flag("auto-download").Changed {
+		return r.feed.UpdateAutoDownload(cmd.Flag("auto-download").Value.String())
+	}
+	if cmd.Flag("player-command").Changed {
+		return r.feed.SetPlayerCommand(cmd.Flag("player-command").Value.String())
+	}
 	if cmd.Flag("summary").Changed {
 		summary, err := cmd.Flags().GetUint8("summary")
 		if err != nil {
@@ -70,6 +172,9 @@ func (r *Root) RunConfig(cmd *cobra.Command, args []string) error {
 		}
 		return r.feed.SetSummary(summary)
 	}
+	if cmd.Flag("theme").Changed {
+		return r.feed.SetTheme(cmd.Flag("theme").Value.String())
+	}
 	if cmd.Flag("map-colors").Changed {
 		return r.feed.UpdateColorMap(cmd.Flag("map-colors").Value.String())
 	}
@@ -94,6 +199,33 @@ func (r *Root) RunConfig(cmd *cobra.Command, args []string) error {
 		}
 		return r.feed.SetTimeout(timeout)
 	}
+	if cmd.Flag("cache-backend").Changed {
+		return r.feed.SetCacheBackend(cmd.Flag("cache-backend").Value.String())
+	}
+	if cmd.Flag("archive-max-age").Changed || cmd.Flag("archive-max-count").Changed {
+		var maxAge, maxCount *uint
+		if cmd.Flag("archive-max-age").Changed {
+			v, err := cmd.Flags().GetUint("archive-max-age")
+			if err != nil {
+				return err
+			}
+			maxAge = &v
+		}
+		if cmd.Flag("archive-max-count").Changed {
+			v, err := cmd.Flags().GetUint("archive-max-count")
+			if err != nil {
+				return err
+			}
+			maxCount = &v
+		}
+		return r.feed.SetArchiveRetention(maxAge, maxCount)
+	}
+	if cmd.Flag("search-language").Changed {
+		return r.feed.SetSearchLanguage(cmd.Flag("search-language").Value.String())
+	}
+	if cmd.Flag("feed-priority").Changed {
+		return r.feed.UpdateFeedPriority(cmd.Flag("feed-priority").Value.String())
+	}
 	if cmd.Flag("future-items").Changed {
 		value, err := cmd.Flags().GetUint8("future-items")
 		if err != nil {
@@ -101,6 +233,19 @@ func (r *Root) RunConfig(cmd *cobra.Command, args []string) error {
 		}
 		return r.feed.UpdateFutureItems(value)
 	}
+	if cmd.Flag("template").Changed {
+		return r.feed.UpdateTemplates(cmd.Flag("template").Value.String())
+	}
+	if cmd.Flag("layout").Changed {
+		return r.feed.SetLayout(cmd.Flag("layout").Value.String())
+	}
+	if cmd.Flag("dedupe").Changed {
+		value, err := cmd.Flags().GetUint8("dedupe")
+		if err != nil {
+			return err
+		}
+		return r.feed.SetDedupe(value)
+	}
 	if cmd.Flag("miniflux-token").Changed {
 		token := cmd.Flag("miniflux-token").Value.String()
 		return r.feed.SetMinifluxToken(token)
@@ -38,10 +38,25 @@ func Test_Config(t *testing.T) {
 	assert.Equal(t, `User-Agent: cleed/v0.1.0 (github.com/radulucut/cleed)
 Timeout: 30
 Batch size: 100
+Cache backend: file
 Styling: enabled
+Hyperlinks: detect
+Theme: default
 Color map:
 Summary: disabled
 Future items: show
+Dedupe: disabled
+Layout: default
+Templates:
+Pager: default
+Open command: default
+Download directory: default
+Auto-download:
+Player command: default
+Archive max age: unlimited
+Archive max count: unlimited
+Search language: none
+Feed priority:
 Miniflux token:
 `, out.String())
 
@@ -372,6 +387,322 @@ func Test_Config_FutureItems(t *testing.T) {
 	assert.Equal(t, expectedConfig, config)
 }
 
+func Test_Config_Dedupe(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	timeMock := mocks.NewMockTime(ctrl)
+	timeMock.EXPECT().Now().Return(defaultCurrentTime).AnyTimes()
+
+	out := new(bytes.Buffer)
+	printer := internal.NewPrinter(nil, out, out)
+	storage := _storage.NewLocalStorage("cleed_test", timeMock)
+	defer localStorageCleanup(t, storage)
+
+	feed := internal.NewTerminalFeed(timeMock, printer, storage)
+
+	root, err := NewRoot("0.1.0", timeMock, printer, storage, feed)
+	assert.NoError(t, err)
+
+	os.Args = []string{"cleed", "config", "--dedupe", "1"}
+
+	err = root.Cmd.Execute()
+	assert.NoError(t, err)
+	assert.Equal(t, "dedupe was updated\n", out.String())
+
+	config, err := storage.LoadConfig()
+	assert.NoError(t, err)
+	assert.True(t, config.Dedupe)
+
+	root, err = NewRoot("0.1.0", timeMock, printer, storage, feed)
+	assert.NoError(t, err)
+
+	os.Args = []string{"cleed", "config", "--dedupe", "2"}
+
+	err = root.Cmd.Execute()
+	assert.EqualError(t, err, "invalid value for dedupe")
+}
+
+func Test_Config_Hyperlinks(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	timeMock := mocks.NewMockTime(ctrl)
+	timeMock.EXPECT().Now().Return(defaultCurrentTime).AnyTimes()
+
+	out := new(bytes.Buffer)
+	printer := internal.NewPrinter(nil, out, out)
+	storage := _storage.NewLocalStorage("cleed_test", timeMock)
+	defer localStorageCleanup(t, storage)
+
+	feed := internal.NewTerminalFeed(timeMock, printer, storage)
+
+	root, err := NewRoot("0.1.0", timeMock, printer, storage, feed)
+	assert.NoError(t, err)
+
+	os.Args = []string{"cleed", "config", "--hyperlinks", "1"}
+
+	err = root.Cmd.Execute()
+	assert.NoError(t, err)
+	assert.Equal(t, "hyperlinks were updated\n", out.String())
+
+	config, err := storage.LoadConfig()
+	assert.NoError(t, err)
+	assert.Equal(t, uint8(1), config.Hyperlinks)
+
+	root, err = NewRoot("0.1.0", timeMock, printer, storage, feed)
+	assert.NoError(t, err)
+
+	os.Args = []string{"cleed", "config", "--hyperlinks", "3"}
+
+	err = root.Cmd.Execute()
+	assert.EqualError(t, err, "invalid value for hyperlinks")
+}
+
+func Test_Config_Pager(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	timeMock := mocks.NewMockTime(ctrl)
+	timeMock.EXPECT().Now().Return(defaultCurrentTime).AnyTimes()
+
+	out := new(bytes.Buffer)
+	printer := internal.NewPrinter(nil, out, out)
+	storage := _storage.NewLocalStorage("cleed_test", timeMock)
+	defer localStorageCleanup(t, storage)
+
+	feed := internal.NewTerminalFeed(timeMock, printer, storage)
+
+	root, err := NewRoot("0.1.0", timeMock, printer, storage, feed)
+	assert.NoError(t, err)
+
+	os.Args = []string{"cleed", "config", "--pager", "less -RS"}
+
+	err = root.Cmd.Execute()
+	assert.NoError(t, err)
+	assert.Equal(t, "pager was updated\n", out.String())
+
+	config, err := storage.LoadConfig()
+	assert.NoError(t, err)
+	assert.Equal(t, "less -RS", config.Pager)
+	assert.Equal(t, []string{"less", "-RS"}, internal.PagerCommand(config.Pager))
+
+	root, err = NewRoot("0.1.0", timeMock, printer, storage, feed)
+	assert.NoError(t, err)
+
+	out.Reset()
+	os.Args = []string{"cleed", "config", "--pager", "-"}
+
+	err = root.Cmd.Execute()
+	assert.NoError(t, err)
+	assert.Equal(t, "pager was updated\n", out.String())
+	assert.Equal(t, "-", config.Pager)
+	assert.Nil(t, internal.PagerCommand(config.Pager))
+
+	t.Setenv("PAGER", "more")
+	assert.Equal(t, []string{"more"}, internal.PagerCommand(""))
+	t.Setenv("PAGER", "")
+	assert.Equal(t, []string{"less", "-R"}, internal.PagerCommand(""))
+}
+
+func Test_Config_Theme(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	timeMock := mocks.NewMockTime(ctrl)
+	timeMock.EXPECT().Now().Return(defaultCurrentTime).AnyTimes()
+
+	out := new(bytes.Buffer)
+	printer := internal.NewPrinter(nil, out, out)
+	storage := _storage.NewLocalStorage("cleed_test", timeMock)
+	defer localStorageCleanup(t, storage)
+
+	feed := internal.NewTerminalFeed(timeMock, printer, storage)
+
+	root, err := NewRoot("0.1.0", timeMock, printer, storage, feed)
+	assert.NoError(t, err)
+
+	os.Args = []string{"cleed", "config", "--theme", "high-contrast"}
+
+	err = root.Cmd.Execute()
+	assert.NoError(t, err)
+	assert.Equal(t, "theme was updated\n", out.String())
+
+	config, err := storage.LoadConfig()
+	assert.NoError(t, err)
+	assert.Equal(t, "high-contrast", config.Theme)
+
+	root, err = NewRoot("0.1.0", timeMock, printer, storage, feed)
+	assert.NoError(t, err)
+
+	os.Args = []string{"cleed", "config", "--theme", "default"}
+
+	err = root.Cmd.Execute()
+	assert.NoError(t, err)
+	assert.Equal(t, "", config.Theme)
+
+	root, err = NewRoot("0.1.0", timeMock, printer, storage, feed)
+	assert.NoError(t, err)
+
+	os.Args = []string{"cleed", "config", "--theme", "neon"}
+
+	err = root.Cmd.Execute()
+	assert.EqualError(t, err, "invalid theme: neon. Use default, light, dark, solarized, high-contrast, no-color")
+}
+
+func Test_Config_Layout(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	timeMock := mocks.NewMockTime(ctrl)
+	timeMock.EXPECT().Now().Return(defaultCurrentTime).AnyTimes()
+
+	out := new(bytes.Buffer)
+	printer := internal.NewPrinter(nil, out, out)
+	storage := _storage.NewLocalStorage("cleed_test", timeMock)
+	defer localStorageCleanup(t, storage)
+
+	feed := internal.NewTerminalFeed(timeMock, printer, storage)
+
+	root, err := NewRoot("0.1.0", timeMock, printer, storage, feed)
+	assert.NoError(t, err)
+
+	os.Args = []string{"cleed", "config", "--template", "short={{.Title}} {{.Link}}"}
+
+	err = root.Cmd.Execute()
+	assert.NoError(t, err)
+	assert.Equal(t, "templates were updated\n", out.String())
+
+	root, err = NewRoot("0.1.0", timeMock, printer, storage, feed)
+	assert.NoError(t, err)
+
+	out.Reset()
+	os.Args = []string{"cleed", "config", "--layout", "short"}
+
+	err = root.Cmd.Execute()
+	assert.NoError(t, err)
+	assert.Equal(t, "layout was updated\n", out.String())
+
+	config, err := storage.LoadConfig()
+	assert.NoError(t, err)
+	assert.Equal(t, "short", config.Layout)
+	assert.Equal(t, map[string]string{"short": "{{.Title}} {{.Link}}"}, config.Templates)
+
+	root, err = NewRoot("0.1.0", timeMock, printer, storage, feed)
+	assert.NoError(t, err)
+
+	os.Args = []string{"cleed", "config", "--template", "broken={{.Title"}
+
+	err = root.Cmd.Execute()
+	assert.ErrorContains(t, err, "failed to parse template: ")
+
+	root, err = NewRoot("0.1.0", timeMock, printer, storage, feed)
+	assert.NoError(t, err)
+
+	os.Args = []string{"cleed", "config", "--template", "compact={{.Title}}"}
+
+	err = root.Cmd.Execute()
+	assert.EqualError(t, err, "template name is reserved for a built-in layout: compact")
+
+	root, err = NewRoot("0.1.0", timeMock, printer, storage, feed)
+	assert.NoError(t, err)
+
+	os.Args = []string{"cleed", "config", "--layout", "missing"}
+
+	err = root.Cmd.Execute()
+	assert.EqualError(t, err, "layout not found: missing. Use compact, default, detailed or a template name")
+
+	// removing the template resets the layout
+	root, err = NewRoot("0.1.0", timeMock, printer, storage, feed)
+	assert.NoError(t, err)
+
+	out.Reset()
+	os.Args = []string{"cleed", "config", "--template", "short="}
+
+	err = root.Cmd.Execute()
+	assert.NoError(t, err)
+	assert.Equal(t, "", config.Layout)
+	assert.Empty(t, config.Templates)
+}
+
+func Test_Config_SearchLanguage(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	timeMock := mocks.NewMockTime(ctrl)
+	timeMock.EXPECT().Now().Return(defaultCurrentTime).AnyTimes()
+
+	out := new(bytes.Buffer)
+	printer := internal.NewPrinter(nil, out, out)
+	storage := _storage.NewLocalStorage("cleed_test", timeMock)
+	defer localStorageCleanup(t, storage)
+
+	feed := internal.NewTerminalFeed(timeMock, printer, storage)
+
+	root, err := NewRoot("0.1.0", timeMock, printer, storage, feed)
+	assert.NoError(t, err)
+
+	os.Args = []string{"cleed", "config", "--search-language", "English"}
+
+	err = root.Cmd.Execute()
+	assert.NoError(t, err)
+	assert.Equal(t, "search language was updated\n", out.String())
+
+	config, err := storage.LoadConfig()
+	assert.NoError(t, err)
+	assert.Equal(t, "english", config.SearchLanguage)
+
+	os.Args = []string{"cleed", "config", "--search-language", "klingon"}
+
+	err = root.Cmd.Execute()
+	assert.EqualError(t, err, "unsupported language: klingon, supported languages: english")
+
+	os.Args = []string{"cleed", "config", "--search-language", "none"}
+
+	err = root.Cmd.Execute()
+	assert.NoError(t, err)
+	assert.Equal(t, "", config.SearchLanguage)
+}
+
+func Test_Config_FeedPriority(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	timeMock := mocks.NewMockTime(ctrl)
+	timeMock.EXPECT().Now().Return(defaultCurrentTime).AnyTimes()
+
+	out := new(bytes.Buffer)
+	printer := internal.NewPrinter(nil, out, out)
+	storage := _storage.NewLocalStorage("cleed_test", timeMock)
+	defer localStorageCleanup(t, storage)
+
+	feed := internal.NewTerminalFeed(timeMock, printer, storage)
+
+	root, err := NewRoot("0.1.0", timeMock, printer, storage, feed)
+	assert.NoError(t, err)
+
+	os.Args = []string{"cleed", "config", "--feed-priority", "https://example.com/feed?a=b=2"}
+
+	err = root.Cmd.Execute()
+	assert.NoError(t, err)
+	assert.Equal(t, "feed priority was updated\n", out.String())
+
+	config, err := storage.LoadConfig()
+	assert.NoError(t, err)
+	assert.Equal(t, map[string]int{"https://example.com/feed?a=b": 2}, config.FeedPriority)
+
+	os.Args = []string{"cleed", "config", "--feed-priority", "https://example.com/feed=6"}
+
+	err = root.Cmd.Execute()
+	assert.EqualError(t, err, "invalid feed priority: 6. Use a number between -5 and 5")
+
+	os.Args = []string{"cleed", "config", "--feed-priority", "https://example.com/feed?a=b="}
+
+	err = root.Cmd.Execute()
+	assert.NoError(t, err)
+	assert.Empty(t, config.FeedPriority)
+}
+
 func Test_Config_MinifluxToken(t *testing.T) {
 	ctrl := gomock.NewController(t)
 	defer ctrl.Finish()
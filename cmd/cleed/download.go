@@ -0,0 +1,61 @@
+package cleed
+
+import (
+	"github.com/radulucut/cleed/internal"
+	"github.com/spf13/cobra"
+)
+
+func (r *Root) initDownload() {
+	cmd := &cobra.Command{
+		Use:   "download [item-id|index]...",
+		Short: "Download the enclosures of items",
+		Long: `Download the enclosures of items from the cached feeds, e.g. podcast episodes
+
+Files are saved into a directory per feed, inside the configured directory
+(cleed config --download-dir), and named after the item. Interrupted
+downloads are resumed. An index is the position of the item in the timeline,
+where 1 is the newest item.
+
+Episodes downloaded once, here or with cleed config --auto-download, are not
+downloaded again automatically, even if their files were deleted.
+
+Examples:
+  # Download the enclosure of the newest item
+  cleed download 1
+
+  # Download the enclosure of an item from a list and play it
+  cleed download 2 --list podcasts --play
+
+  # Download into a specific directory
+  cleed download https://example.com/episode-1 --dir ~/Podcasts
+`,
+
+		RunE: r.RunDownload,
+	}
+
+	flags := cmd.Flags()
+	flags.StringP("list", "L", "", "lists to download items from, separated by commas. Glob patterns are supported")
+	flags.String("since", "", "find items since the last run (last), a specific date (e.g. 2024-01-01 12:03:04) or duration (e.g. 1d)")
+	flags.String("dir", "", "directory to download into (default: configured directory)")
+	flags.Bool("play", false, "play the downloaded files with the configured player")
+
+	r.Cmd.AddCommand(cmd)
+}
+
+func (r *Root) RunDownload(cmd *cobra.Command, args []string) error {
+	since, err := r.parseSinceFlag(cmd.Flag("since").Value.String())
+	if err != nil {
+		return err
+	}
+	opts := &internal.DownloadOptions{
+		Lists:   splitLists(cmd.Flag("list").Value.String()),
+		Targets: args,
+		Since:   since,
+		Dir:     cmd.Flag("dir").Value.String(),
+	}
+	opts.Play, err = cmd.Flags().GetBool("play")
+	if err != nil {
+		return err
+	}
+	return r.feed.Download(opts)
+}
@@ -0,0 +1,356 @@
+package cleed
+
+import (
+	"bytes"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path"
+	"runtime"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/radulucut/cleed/internal"
+	_storage "github.com/radulucut/cleed/internal/storage"
+	"github.com/radulucut/cleed/mocks"
+	"github.com/stretchr/testify/assert"
+	"go.uber.org/mock/gomock"
+)
+
+func Test_Download(t *testing.T) {
+	if runtime.GOOS == "windows" {
+		t.Skip("the player command is a shell script")
+	}
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	timeMock := mocks.NewMockTime(ctrl)
+	timeMock.EXPECT().Now().Return(defaultCurrentTime).AnyTimes()
+
+	out := new(bytes.Buffer)
+	printer := internal.NewPrinter(nil, out, out)
+	storage := _storage.NewLocalStorage("cleed_test", timeMock)
+	defer localStorageCleanup(t, storage)
+
+	configDir, err := os.UserConfigDir()
+	if err != nil {
+		t.Fatal(err)
+	}
+	listsDir := path.Join(configDir, "cleed_test", "lists")
+	err = os.MkdirAll(listsDir, 0700)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	episodes := map[string][]byte{
+		"/ep1.mp3": bytes.Repeat([]byte("1"), 1536),
+		"/ep2.mp3": bytes.Repeat([]byte("2"), 2048),
+	}
+	ranges := make([]string, 0)
+	var rss string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path == "/podcast" {
+			w.Write([]byte(rss))
+			return
+		}
+		if r.Header.Get("Range") != "" {
+			ranges = append(ranges, r.URL.Path+" "+r.Header.Get("Range"))
+		}
+		http.ServeContent(w, r, r.URL.Path, time.Time{}, bytes.NewReader(episodes[r.URL.Path]))
+	}))
+	defer server.Close()
+
+	rss = `<rss version="2.0" xmlns:itunes="http://www.itunes.com/dtds/podcast-1.0.dtd"><channel>
+<title>Podcast</title>
+<link>https://podcast.com/</link>
+<item>
+	<title>Episode 2</title>
+	<link>https://podcast.com/episode-2</link>
+	<pubDate>` + defaultCurrentTime.Add(-time.Hour).Format(time.RFC1123Z) + `</pubDate>
+	<enclosure url="` + server.URL + `/ep2.mp3" length="2048" type="audio/mpeg"/>
+	<itunes:duration>45:12</itunes:duration>
+</item>
+<item>
+	<title>Episode 1</title>
+	<link>https://podcast.com/episode-1</link>
+	<pubDate>` + defaultCurrentTime.Add(-2*time.Hour).Format(time.RFC1123Z) + `</pubDate>
+	<enclosure url="` + server.URL + `/ep1.mp3" length="1536" type="audio/mpeg"/>
+	<itunes:duration>3723</itunes:duration>
+</item>
+<item>
+	<title>Notes</title>
+	<link>https://podcast.com/notes</link>
+	<pubDate>` + defaultCurrentTime.Add(-3*time.Hour).Format(time.RFC1123Z) + `</pubDate>
+</item>
+</channel></rss>`
+
+	err = os.WriteFile(path.Join(listsDir, "default"),
+		fmt.Appendf(nil, "%d %s\n", defaultCurrentTime.Unix(), server.URL+"/podcast"), 0600)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	dir := t.TempDir()
+	played := path.Join(dir, "played")
+	player := path.Join(dir, "player.sh")
+	err = os.WriteFile(player, []byte("#!/bin/sh\necho \"$@\" >> "+played+"\n"), 0700)
+	if err != nil {
+		t.Fatal(err)
+	}
+	downloads := path.Join(dir, "downloads")
+	episode1 := path.Join(downloads, "Podcast", "Episode 1 [2e5c2305].mp3")
+	episode2 := path.Join(downloads, "Podcast", "Episode 2 [2b5c1e4c].mp3")
+
+	feed := internal.NewTerminalFeed(timeMock, printer, storage)
+	for _, args := range [][]string{
+		{"--download-dir", downloads},
+		{"--auto-download", server.URL + "/podcast/=1"},
+		{"--player-command", player},
+	} {
+		root, err := NewRoot("0.1.0", timeMock, printer, storage, feed)
+		assert.NoError(t, err)
+		os.Args = append([]string{"cleed", "config"}, args...)
+		err = root.Cmd.Execute()
+		assert.NoError(t, err)
+	}
+	assert.Equal(t, "download directory was updated\nauto-download was updated\nplayer command was updated\n", out.String())
+
+	// rules are stored under the address the feed is followed with
+	config, err := storage.LoadConfig()
+	assert.NoError(t, err)
+	assert.Equal(t, map[string]int{server.URL + "/podcast": 1}, config.AutoDownload)
+
+	root, err := NewRoot("0.1.0", timeMock, printer, storage, feed)
+	assert.NoError(t, err)
+
+	os.Args = []string{"cleed", "config", "--auto-download", "https://missing.com/feed=1"}
+	err = root.Cmd.Execute()
+	assert.EqualError(t, err, "feed not found: https://missing.com/feed")
+
+	// the newest episode is downloaded when fetching the feed
+	root, err = NewRoot("0.1.0", timeMock, printer, storage, feed)
+	assert.NoError(t, err)
+
+	out.Reset()
+	os.Args = []string{"cleed"}
+	err = root.Cmd.Execute()
+	assert.NoError(t, err)
+	assert.Equal(t, `Podcast      • Notes
+3 hours ago  https://podcast.com/notes
+
+Podcast      • Episode 1
+2 hours ago  https://podcast.com/episode-1
+             [audio/mpeg, 1.5 KB, 1:02:03]
+
+Podcast      • Episode 2
+1 hour ago   https://podcast.com/episode-2
+             [audio/mpeg, 2.0 KB, 45:12]
+
+downloading `+episode2+` (1/1)
+downloaded `+episode2+` (2.0 KB)
+`, out.String())
+
+	b, err := os.ReadFile(episode2)
+	assert.NoError(t, err)
+	assert.Equal(t, episodes["/ep2.mp3"], b)
+
+	// an interrupted download is resumed
+	err = os.WriteFile(episode1+".part", episodes["/ep1.mp3"][:512], 0600)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	root, err = NewRoot("0.1.0", timeMock, printer, storage, feed)
+	assert.NoError(t, err)
+
+	out.Reset()
+	os.Args = []string{"cleed", "download", "2"}
+	err = root.Cmd.Execute()
+	assert.NoError(t, err)
+	assert.Equal(t, "downloaded "+episode1+" (1.5 KB)\n", out.String())
+	assert.Equal(t, []string{"/ep1.mp3 bytes=512-"}, ranges)
+
+	b, err = os.ReadFile(episode1)
+	assert.NoError(t, err)
+	assert.Equal(t, episodes["/ep1.mp3"], b)
+	_, err = os.Stat(episode1 + ".part")
+	assert.True(t, os.IsNotExist(err))
+
+	// play an episode that was already downloaded
+	root, err = NewRoot("0.1.0", timeMock, printer, storage, feed)
+	assert.NoError(t, err)
+
+	out.Reset()
+	os.Args = []string{"cleed", "download", "https://podcast.com/episode-2", "--play"}
+	err = root.Cmd.Execute()
+	assert.NoError(t, err)
+	assert.Equal(t, "already downloaded "+episode2+"\n", out.String())
+
+	b, err = os.ReadFile(played)
+	assert.NoError(t, err)
+	assert.Equal(t, episode2+"\n", string(b))
+
+	// download into another directory
+	other := path.Join(dir, "other")
+	root, err = NewRoot("0.1.0", timeMock, printer, storage, feed)
+	assert.NoError(t, err)
+
+	out.Reset()
+	os.Args = []string{"cleed", "download", "1", "--dir", other}
+	err = root.Cmd.Execute()
+	assert.NoError(t, err)
+	assert.True(t, strings.HasPrefix(out.String(), "downloaded "+path.Join(other, "Podcast", "Episode 2 [2b5c1e4c].mp3")))
+
+	root, err = NewRoot("0.1.0", timeMock, printer, storage, feed)
+	assert.NoError(t, err)
+
+	os.Args = []string{"cleed", "download", "3"}
+	err = root.Cmd.Execute()
+	assert.EqualError(t, err, "item has no enclosures: Notes")
+
+	// episodes are downloaded once, even if their files were deleted
+	err = os.Remove(episode2)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	root, err = NewRoot("0.1.0", timeMock, printer, storage, feed)
+	assert.NoError(t, err)
+
+	out.Reset()
+	os.Args = []string{"cleed"}
+	err = root.Cmd.Execute()
+	assert.NoError(t, err)
+	assert.NotContains(t, out.String(), "download")
+	_, err = os.Stat(episode2)
+	assert.True(t, os.IsNotExist(err))
+
+	// an episode with the same title and two enclosures
+	rss = strings.Replace(rss, "<item>", `<item>
+	<title>Episode 2</title>
+	<link>https://podcast.com/episode-2-bonus</link>
+	<pubDate>`+defaultCurrentTime.Add(-30*time.Minute).Format(time.RFC1123Z)+`</pubDate>
+	<enclosure url="`+server.URL+`/ep1.mp3" length="1536" type="audio/mpeg"/>
+	<enclosure url="`+server.URL+`/ep2.mp3" length="2048" type="audio/mpeg"/>
+</item>
+<item>`, 1)
+	// fetch the feed again
+	cacheInfo, err := storage.LoadCacheInfo()
+	if err != nil {
+		t.Fatal(err)
+	}
+	cacheInfo[server.URL+"/podcast"].FetchAfter = time.Time{}
+	err = storage.SaveCacheInfo(cacheInfo)
+	if err != nil {
+		t.Fatal(err)
+	}
+	bonus1 := path.Join(downloads, "Podcast", "Episode 2 [584b91e6].mp3")
+	bonus2 := path.Join(downloads, "Podcast", "Episode 2 [584b91e6] 2.mp3")
+	// files that are already downloaded are skipped silently
+	err = os.WriteFile(bonus1, episodes["/ep1.mp3"], 0600)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	root, err = NewRoot("0.1.0", timeMock, printer, storage, feed)
+	assert.NoError(t, err)
+
+	out.Reset()
+	os.Args = []string{"cleed"}
+	err = root.Cmd.Execute()
+	assert.NoError(t, err)
+	assert.True(t, strings.HasSuffix(out.String(), "\n\ndownloading "+bonus2+" (1/1)\ndownloaded "+bonus2+" (2.0 KB)\n"))
+
+	b, err = os.ReadFile(bonus2)
+	assert.NoError(t, err)
+	assert.Equal(t, episodes["/ep2.mp3"], b)
+}
+
+func Test_Download_Stall(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	timeMock := mocks.NewMockTime(ctrl)
+	timeMock.EXPECT().Now().Return(defaultCurrentTime).AnyTimes()
+
+	out := new(bytes.Buffer)
+	printer := internal.NewPrinter(nil, out, out)
+	storage := _storage.NewLocalStorage("cleed_test", timeMock)
+	defer localStorageCleanup(t, storage)
+
+	configDir, err := os.UserConfigDir()
+	if err != nil {
+		t.Fatal(err)
+	}
+	listsDir := path.Join(configDir, "cleed_test", "lists")
+	err = os.MkdirAll(listsDir, 0700)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	var rss string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path == "/podcast" {
+			w.Write([]byte(rss))
+			return
+		}
+		// send a part of the file and stop sending data
+		w.Header().Set("Content-Length", "2048")
+		w.Write(bytes.Repeat([]byte("1"), 512))
+		w.(http.Flusher).Flush()
+		<-r.Context().Done()
+	}))
+	defer server.Close()
+
+	rss = `<rss version="2.0"><channel>
+<title>Podcast</title>
+<item>
+	<title>Episode 1</title>
+	<link>https://podcast.com/episode-1</link>
+	<pubDate>` + defaultCurrentTime.Add(-time.Hour).Format(time.RFC1123Z) + `</pubDate>
+	<enclosure url="` + server.URL + `/ep1.mp3" length="2048" type="audio/mpeg"/>
+</item>
+</channel></rss>`
+
+	err = os.WriteFile(path.Join(listsDir, "default"),
+		fmt.Appendf(nil, "%d %s\n", defaultCurrentTime.Unix(), server.URL+"/podcast"), 0600)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	downloads := path.Join(t.TempDir(), "downloads")
+	episode1 := path.Join(downloads, "Podcast", "Episode 1 [2e5c2305].mp3")
+
+	feed := internal.NewTerminalFeed(timeMock, printer, storage)
+	for _, args := range [][]string{
+		{"--download-dir", downloads},
+		{"--timeout", "1"},
+	} {
+		root, err := NewRoot("0.1.0", timeMock, printer, storage, feed)
+		assert.NoError(t, err)
+		os.Args = append([]string{"cleed", "config"}, args...)
+		err = root.Cmd.Execute()
+		assert.NoError(t, err)
+	}
+
+	root, err := NewRoot("0.1.0", timeMock, printer, storage, feed)
+	assert.NoError(t, err)
+
+	os.Args = []string{"cleed"}
+	err = root.Cmd.Execute()
+	assert.NoError(t, err)
+
+	root, err = NewRoot("0.1.0", timeMock, printer, storage, feed)
+	assert.NoError(t, err)
+
+	os.Args = []string{"cleed", "download", "1"}
+	err = root.Cmd.Execute()
+	assert.EqualError(t, err, "failed to download "+server.URL+"/ep1.mp3: no data received for 1s")
+
+	// the received data is kept to resume the download
+	info, err := os.Stat(episode1 + ".part")
+	assert.NoError(t, err)
+	assert.Equal(t, int64(512), info.Size())
+}
@@ -27,6 +27,9 @@ Examples:
   # Search for items (title, description)
   cleed explore --search "news"
 
+  # Search for items matching all the terms, tolerating typos
+  cleed explore --search "tech podcats" --fuzzy
+
   # Import all feeds into my feeds
   cleed explore --import --limit 0
 
@@ -44,13 +47,16 @@ Examples:
 	flags.Bool("import", false, "import feeds")
 	flags.Uint("limit", 10, "limit the number of items to display from each list")
 	flags.String("search", "", "search for items (title, description)")
+	flags.Bool("fuzzy", false, "also match terms similar to the search terms")
 	flags.BoolP("update", "u", false, "fetch the latest changes")
 	flags.Bool("remove", false, "remove the repository")
+	flags.Bool("no-pager", false, "do not pipe long output through a pager")
 
 	r.Cmd.AddCommand(cmd)
 }
 
 func (r *Root) RunExplore(cmd *cobra.Command, args []string) error {
+	defer r.startPager(cmd)()
 	if cmd.Flag("remove").Changed {
 		url := ""
 		if len(args) > 0 {
@@ -78,6 +84,10 @@ func (r *Root) RunExplore(cmd *cobra.Command, args []string) error {
 		if err != nil {
 			return err
 		}
+		opts.Fuzzy, err = cmd.Flags().GetBool("fuzzy")
+		if err != nil {
+			return err
+		}
 		return r.feed.ExploreSearch(opts)
 	}
 	if cmd.Flag("import").Changed {
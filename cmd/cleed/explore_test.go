@@ -295,6 +295,32 @@ Atom 1 Feed
 Atom Feed description
 https://atom-feed.com/atom
 
+Displayed 2 out of 2 items
+`, out.String())
+
+	out.Reset()
+	os.Args = []string{"cleed", "explore", "--search", `"atom 1" OR rss -description:"rss feed"`}
+	err = root.Cmd.Execute()
+	assert.NoError(t, err)
+	assert.Equal(t, `Atom 1 Feed
+Atom Feed description
+https://atom-feed.com/atom
+
+Displayed 1 out of 1 item
+`, out.String())
+
+	out.Reset()
+	os.Args = []string{"cleed", "explore", "--search", "aton", "--fuzzy"}
+	err = root.Cmd.Execute()
+	assert.NoError(t, err)
+	assert.Equal(t, `Atom 2 Feed
+Atom Feed description
+https://atom-feed.com/atom
+
+Atom 1 Feed
+Atom Feed description
+https://atom-feed.com/atom
+
 Displayed 2 out of 2 items
 `, out.String())
 }
@@ -346,10 +372,16 @@ func Test_Explore_Import(t *testing.T) {
 	root, err := NewRoot("0.1.0", timeMock, printer, storage, feed)
 	assert.NoError(t, err)
 
+	// the same feed with a slightly different URL is not imported again
+	err = storage.AddToList([]string{"http://www.rss-feed.com/rss/"}, "test 2")
+	if err != nil {
+		t.Fatal(err)
+	}
+
 	os.Args = []string{"cleed", "explore", "--import"}
 	err = root.Cmd.Execute()
 	assert.NoError(t, err)
-	assert.Equal(t, "Imported 5 feeds from 2 lists\n", out.String())
+	assert.Equal(t, "Imported 4 feeds from 2 lists\n", out.String())
 
 	lists, err := storage.LoadLists()
 	assert.NoError(t, err)
@@ -369,7 +401,7 @@ func Test_Explore_Import(t *testing.T) {
 	feeds, err = storage.GetFeedsFromList("test 2")
 	assert.NoError(t, err)
 	expectedFeeds = []*_storage.ListItem{
-		{AddedAt: time.Unix(defaultCurrentTime.Unix(), 0), Address: "https://rss-feed.com/rss"},
+		{AddedAt: time.Unix(defaultCurrentTime.Unix(), 0), Address: "http://www.rss-feed.com/rss/"},
 		{AddedAt: time.Unix(defaultCurrentTime.Unix(), 0), Address: "https://atom-feed.com/atom"},
 	}
 	assert.Equal(t, expectedFeeds, feeds)
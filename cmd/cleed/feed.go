@@ -0,0 +1,52 @@
+package cleed
+
+import (
+	"fmt"
+
+	"github.com/spf13/cobra"
+)
+
+func (r *Root) initFeed() {
+	cmd := &cobra.Command{
+		Use:   "feed",
+		Short: "Show the status of a feed, pause or resume it",
+		Long: `Show the status of a feed, pause or resume it
+
+A paused feed is not fetched or displayed, but its cached items are kept.
+
+Examples:
+  # Show the lists of a feed and whether it is paused
+  cleed feed https://example.com/feed.xml
+
+  # Pause a feed
+  cleed feed https://example.com/feed.xml --pause
+
+  # Resume a paused feed
+  cleed feed https://example.com/feed.xml --resume
+`,
+
+		RunE: r.RunFeed,
+		Args: cobra.ExactArgs(1),
+	}
+
+	flags := cmd.Flags()
+	flags.Bool("pause", false, "pause the feed")
+	flags.Bool("resume", false, "resume the feed")
+
+	r.Cmd.AddCommand(cmd)
+}
+
+func (r *Root) RunFeed(cmd *cobra.Command, args []string) error {
+	pause := cmd.Flag("pause").Changed
+	resume := cmd.Flag("resume").Changed
+	if pause && resume {
+		return fmt.Errorf("please use either --pause or --resume")
+	}
+	if pause {
+		return r.feed.PauseFeed(args[0])
+	}
+	if resume {
+		return r.feed.ResumeFeed(args[0])
+	}
+	return r.feed.FeedStatus(args[0])
+}
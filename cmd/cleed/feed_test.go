@@ -0,0 +1,162 @@
+package cleed
+
+import (
+	"bytes"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path"
+	"testing"
+	"time"
+
+	"github.com/radulucut/cleed/internal"
+	_storage "github.com/radulucut/cleed/internal/storage"
+	"github.com/radulucut/cleed/mocks"
+	"github.com/stretchr/testify/assert"
+	"go.uber.org/mock/gomock"
+)
+
+func Test_Feed_Pause_Resume(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	timeMock := mocks.NewMockTime(ctrl)
+	timeMock.EXPECT().Now().Return(defaultCurrentTime).AnyTimes()
+
+	out := new(bytes.Buffer)
+	printer := internal.NewPrinter(nil, out, out)
+	storage := _storage.NewLocalStorage("cleed_test", timeMock)
+	defer localStorageCleanup(t, storage)
+
+	configDir, err := os.UserConfigDir()
+	if err != nil {
+		t.Fatal(err)
+	}
+	listsDir := path.Join(configDir, "cleed_test", "lists")
+	err = os.MkdirAll(listsDir, 0700)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	requests := 0
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		requests++
+		w.Write([]byte(createRSS([]*FeedItem{
+			{
+				Title:     "Item " + r.URL.Path,
+				Link:      "https://rss-feed.com" + r.URL.Path,
+				Published: defaultCurrentTime.Add(-time.Hour).Format(time.RFC1123Z),
+			},
+		})))
+	}))
+	defer server.Close()
+
+	err = os.WriteFile(path.Join(listsDir, "default"),
+		fmt.Appendf(nil, "%d %s\n%d %s\n",
+			defaultCurrentTime.Unix(), server.URL+"/a",
+			defaultCurrentTime.Unix(), server.URL+"/b",
+		), 0600)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	feed := internal.NewTerminalFeed(timeMock, printer, storage)
+	root, err := NewRoot("0.1.0", timeMock, printer, storage, feed)
+	assert.NoError(t, err)
+
+	// the URL is matched with the address of the followed feed
+	os.Args = []string{"cleed", "feed", server.URL + "/a/", "--pause"}
+	err = root.Cmd.Execute()
+	assert.NoError(t, err)
+	assert.Equal(t, fmt.Sprintf("feed %s/a/ was paused\n", server.URL), out.String())
+
+	config, err := storage.LoadConfig()
+	assert.NoError(t, err)
+	assert.Equal(t, []string{server.URL + "/a"}, config.PausedFeeds)
+
+	out.Reset()
+	os.Args = []string{"cleed"}
+	err = root.Cmd.Execute()
+	assert.NoError(t, err)
+	assert.Equal(t, `RSS Feed    • Item /b
+1 hour ago  https://rss-feed.com/b
+
+`, out.String())
+	assert.Equal(t, 1, requests)
+
+	root, err = NewRoot("0.1.0", timeMock, printer, storage, feed)
+	assert.NoError(t, err)
+
+	out.Reset()
+	os.Args = []string{"cleed", "feed", server.URL + "/a"}
+	err = root.Cmd.Execute()
+	assert.NoError(t, err)
+	assert.Equal(t, fmt.Sprintf(`URL: %s/a
+Lists: default
+Status: paused
+`, server.URL), out.String())
+
+	out.Reset()
+	os.Args = []string{"cleed", "--cache-info"}
+	err = root.Cmd.Execute()
+	assert.NoError(t, err)
+	assert.Contains(t, out.String(), server.URL+"/b  ")
+	assert.NotContains(t, out.String(), "(paused)")
+
+	err = storage.SaveCacheInfo(map[string]*_storage.CacheInfoItem{
+		server.URL + "/a": {
+			URL:        server.URL + "/a",
+			LastFetch:  time.Unix(defaultCurrentTime.Unix(), 0),
+			FetchAfter: time.Unix(defaultCurrentTime.Unix(), 0),
+		},
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	root, err = NewRoot("0.1.0", timeMock, printer, storage, feed)
+	assert.NoError(t, err)
+
+	out.Reset()
+	os.Args = []string{"cleed", "--cache-info"}
+	err = root.Cmd.Execute()
+	assert.NoError(t, err)
+	assert.Equal(t, fmt.Sprintf(`URL%[1]s  Last fetch           Fetch after
+%[2]s/a  %[3]s  %[3]s  (paused)
+`, string(bytes.Repeat([]byte(" "), len(server.URL)-1)), server.URL,
+		time.Unix(defaultCurrentTime.Unix(), 0).Format("2006-01-02 15:04:05"),
+	), out.String())
+
+	root, err = NewRoot("0.1.0", timeMock, printer, storage, feed)
+	assert.NoError(t, err)
+
+	out.Reset()
+	os.Args = []string{"cleed", "list", "default"}
+	err = root.Cmd.Execute()
+	assert.NoError(t, err)
+	assert.Equal(t, fmt.Sprintf(`%[1]s  %[2]s/a  (paused)
+%[1]s  %[2]s/b
+Total: 2 feeds
+`, time.Unix(defaultCurrentTime.Unix(), 0).Format("2006-01-02 15:04:05"), server.URL), out.String())
+
+	root, err = NewRoot("0.1.0", timeMock, printer, storage, feed)
+	assert.NoError(t, err)
+
+	out.Reset()
+	os.Args = []string{"cleed", "feed", server.URL + "/a/", "--resume"}
+	err = root.Cmd.Execute()
+	assert.NoError(t, err)
+	assert.Equal(t, fmt.Sprintf("feed %s/a/ was resumed\n", server.URL), out.String())
+
+	os.Args = []string{"cleed", "feed", server.URL + "/a", "--resume"}
+	err = root.Cmd.Execute()
+	assert.EqualError(t, err, fmt.Sprintf("feed is not paused: %s/a", server.URL))
+
+	root, err = NewRoot("0.1.0", timeMock, printer, storage, feed)
+	assert.NoError(t, err)
+
+	os.Args = []string{"cleed", "feed", "https://unknown.com", "--pause"}
+	err = root.Cmd.Execute()
+	assert.EqualError(t, err, "feed not found: https://unknown.com")
+}
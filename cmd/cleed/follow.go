@@ -16,6 +16,9 @@ Examples:
 
   # Add multiple feeds to a list
   cleed follow https://example.com/feed.xml https://example2.com/feed --list mylist
+
+  # Add a feed to a nested list
+  cleed follow https://example.com/feed.xml --list tech/go
 `,
 		RunE: r.RunFollow,
 		Args: cobra.MinimumNArgs(1),
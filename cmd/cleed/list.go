@@ -1,6 +1,11 @@
 package cleed
 
 import (
+	"cmp"
+	"strings"
+
+	"github.com/radulucut/cleed/internal"
+	"github.com/radulucut/cleed/internal/utils"
 	"github.com/spf13/cobra"
 )
 
@@ -11,28 +16,71 @@ func (r *Root) initList() {
 		Long: `Show all lists, feeds in a list or manage lists
 
 Examples:
-  # Show all lists
+  # Show all lists. Nested lists are displayed under their parent
   cleed list
 
   # Show all feeds in a list
   cleed list mylist
 
+  # Show statistics for all lists, lists with the most unread items first
+  cleed list --stats --sort unread
+
+  # Show statistics for the feeds in a list, most active feeds first
+  cleed list mylist --stats --sort frequency
+
+  # Show feeds without new items in the last 180 days, failing feeds and empty feeds
+  cleed list --stale 180d
+
+  # Move the stale feeds of a list to the archive list
+  cleed list mylist --stale 365d --archive
+
+  # Move stale feeds to a custom list
+  cleed list --stale 90d --archive --to dormant
+
+  # Show feeds that are followed more than once across all lists
+  cleed list --duplicates
+
+  # Choose which duplicate feeds to keep and remove the others
+  cleed list --duplicates --interactive
+
   # Rename a list
   cleed list mylist --rename newlist
 
+  # Rename a list and its nested lists (e.g. tech/go becomes dev/go)
+  cleed list tech --rename dev
+
   # Merge a list. Move all feeds from anotherlist to mylist and remove anotherlist
   cleed list mylist --merge anotherlist
 
   # Remove a list
   cleed list mylist --remove
 
+  # Show what removing a list would do, without removing it
+  cleed list mylist --remove --dry-run
+
+  # Move feeds to another list, keeping the date they were added
+  cleed list mylist --move https://example.com/feed.xml --to anotherlist
+
+  # Move several feeds, separated by commas or as extra arguments
+  cleed list mylist --move https://example.com/feed.xml,https://test.com/rss --to anotherlist
+  cleed list mylist --move https://example.com/feed.xml https://test.com/rss --to anotherlist
+
+  # Copy all feeds from a site to another list
+  cleed list mylist --copy "https://example.com/*" --to anotherlist
+
+  # Pause a list and its nested lists. Its feeds are not fetched or displayed
+  cleed list mylist --pause
+
+  # Resume a paused list
+  cleed list mylist --resume
+
   # Import feeds from a file
   cleed list mylist --import-from-file feeds.txt
 
   # Import feeds from an OPML file into a list
   cleed list mylist --import-from-opml feeds.opml
 
-  # Import feeds from an OPML file into multiple lists
+  # Import feeds from an OPML file into multiple lists. Nested folders become nested lists
   cleed list --import-from-opml feeds.opml
 
   # Export feeds to a file
@@ -41,7 +89,7 @@ Examples:
   # Export feeds from a list to a file
   cleed list mylist --export-to-opml feeds.opml
 
-  # Export all feeds to an OPML file grouped by lists
+  # Export all feeds to an OPML file grouped by lists. Nested lists become nested folders
   cleed list --export-to-opml feeds.opml
 
   # Export only cached feeds to an OPML file
@@ -49,23 +97,50 @@ Examples:
 `,
 
 		RunE: r.RunList,
-		Args: cobra.MaximumNArgs(1),
+		Args: func(cmd *cobra.Command, args []string) error {
+			// feeds to move or copy can follow the list name
+			if cmd.Flag("move").Changed || cmd.Flag("copy").Changed {
+				return cobra.MinimumNArgs(1)(cmd, args)
+			}
+			return cobra.MaximumNArgs(1)(cmd, args)
+		},
 	}
 
 	flags := cmd.Flags()
+	flags.Bool("stats", false, "show statistics for all lists or for the feeds in a list")
+	flags.String("sort", "", "sort statistics. Lists: "+strings.Join(internal.ListStatsSortOptions, ", ")+". Feeds: "+strings.Join(internal.FeedStatsSortOptions, ", "))
+	flags.String("stale", "", "show feeds without new items for a duration (e.g. 180d), failing feeds and empty feeds")
+	flags.Bool("archive", false, "move the feeds found with --stale to the list given with --to (default archive)")
+	flags.Bool("duplicates", false, "show feeds that are followed more than once across all lists")
+	flags.BoolP("interactive", "i", false, "choose which duplicate feeds to keep (--duplicates)")
 	flags.String("rename", "", "rename a list")
 	flags.String("merge", "", "merge a list")
 	flags.Bool("remove", false, "remove a list")
+	flags.Bool("dry-run", false, "show what --remove or --merge would do without changing anything")
+	flags.StringSlice("move", nil, "move feeds to the list given with --to. Feed URLs or patterns (e.g. https://example.com/*), separated by commas or as extra arguments")
+	flags.StringSlice("copy", nil, "copy feeds to the list given with --to. Feed URLs or patterns (e.g. https://example.com/*), separated by commas or as extra arguments")
+	flags.String("to", "", "destination list for --move, --copy and --archive")
+	flags.Bool("pause", false, "pause a list")
+	flags.Bool("resume", false, "resume a paused list")
 	flags.String("import-from-file", "", "import feeds from a file. Newline separated URLs")
 	flags.String("import-from-opml", "", "import feeds from an OPML file")
 	flags.String("export-to-file", "", "export feeds to a file. Newline separated URLs")
 	flags.String("export-to-opml", "", "export feeds to an OPML file")
 	flags.BoolP("cached-only", "C", false, "include only cached feeds (opml export)")
+	flags.Bool("no-pager", false, "do not pipe long output through a pager")
 
 	r.Cmd.AddCommand(cmd)
 }
 
 func (r *Root) RunList(cmd *cobra.Command, args []string) error {
+	interactive, err := cmd.Flags().GetBool("interactive")
+	if err != nil {
+		return err
+	}
+	// prompts are not visible while the output is buffered for the pager
+	if !interactive {
+		defer r.startPager(cmd)()
+	}
 	list := ""
 	if len(args) > 0 {
 		list = args[0]
@@ -82,6 +157,28 @@ func (r *Root) RunList(cmd *cobra.Command, args []string) error {
 		}
 		return r.feed.ExportToOPML(exportToOPML, list, cachedOnly)
 	}
+	if cmd.Flag("stats").Changed {
+		sort := cmd.Flag("sort").Value.String()
+		if list == "" {
+			return r.feed.ShowListStats(sort)
+		}
+		return r.feed.ShowFeedStats(list, sort)
+	}
+	if cmd.Flag("duplicates").Changed {
+		return r.feed.ShowDuplicateFeeds(interactive)
+	}
+	stale := cmd.Flag("stale").Value.String()
+	if stale != "" {
+		d, err := utils.ParseDuration(stale)
+		if err != nil {
+			return err
+		}
+		archive, err := cmd.Flags().GetBool("archive")
+		if err != nil {
+			return err
+		}
+		return r.feed.ShowStaleFeeds(list, d, cmp.Or(cmd.Flag("to").Value.String(), "archive"), archive)
+	}
 	if list == "" {
 		return r.feed.Lists()
 	}
@@ -89,13 +186,44 @@ func (r *Root) RunList(cmd *cobra.Command, args []string) error {
 	if rename != "" {
 		return r.feed.RenameList(list, rename)
 	}
+	dryRun, err := cmd.Flags().GetBool("dry-run")
+	if err != nil {
+		return err
+	}
 	merge := cmd.Flag("merge").Value.String()
 	if merge != "" {
+		if dryRun {
+			return r.feed.PreviewMergeLists(list, merge)
+		}
 		return r.feed.MergeLists(list, merge)
 	}
 	if cmd.Flag("remove").Changed {
+		if dryRun {
+			return r.feed.PreviewRemoveList(list)
+		}
 		return r.feed.RemoveList(list)
 	}
+	to := cmd.Flag("to").Value.String()
+	if cmd.Flag("move").Changed {
+		urls, err := cmd.Flags().GetStringSlice("move")
+		if err != nil {
+			return err
+		}
+		return r.feed.MoveFeeds(append(urls, args[1:]...), list, to)
+	}
+	if cmd.Flag("copy").Changed {
+		urls, err := cmd.Flags().GetStringSlice("copy")
+		if err != nil {
+			return err
+		}
+		return r.feed.CopyFeeds(append(urls, args[1:]...), list, to)
+	}
+	if cmd.Flag("pause").Changed {
+		return r.feed.PauseList(list)
+	}
+	if cmd.Flag("resume").Changed {
+		return r.feed.ResumeList(list)
+	}
 	importFromFile := cmd.Flag("import-from-file").Value.String()
 	if importFromFile != "" {
 		return r.feed.ImportFromFile(importFromFile, list)
@@ -3,9 +3,12 @@ package cleed
 import (
 	"bytes"
 	"fmt"
+	"net/http"
+	"net/http/httptest"
 	"net/url"
 	"os"
 	"path"
+	"strings"
 	"testing"
 	"time"
 
@@ -139,6 +142,624 @@ func Test_List_Feeds(t *testing.T) {
 	), out.String())
 }
 
+func Test_List_Nested(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	timeMock := mocks.NewMockTime(ctrl)
+	timeMock.EXPECT().Now().Return(defaultCurrentTime).AnyTimes()
+
+	out := new(bytes.Buffer)
+	printer := internal.NewPrinter(nil, out, out)
+	storage := _storage.NewLocalStorage("cleed_test", timeMock)
+	defer localStorageCleanup(t, storage)
+
+	configDir, err := os.UserConfigDir()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	listsDir := path.Join(configDir, "cleed_test", "lists")
+	err = os.MkdirAll(listsDir, 0700)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	for _, list := range []string{"tech", "tech/go", "tech-news", "news/local", "abc"} {
+		err = os.WriteFile(path.Join(listsDir, url.QueryEscape(list)),
+			[]byte(fmt.Sprintf("%d %s\n", defaultCurrentTime.Unix(), "https://example.com")), 0600)
+		if err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	feed := internal.NewTerminalFeed(timeMock, printer, storage)
+
+	root, err := NewRoot("0.1.0", timeMock, printer, storage, feed)
+	assert.NoError(t, err)
+
+	os.Args = []string{"cleed", "list"}
+
+	err = root.Cmd.Execute()
+	assert.NoError(t, err)
+	assert.Equal(t, `abc
+news
+  local
+tech
+  go
+tech-news
+`, out.String())
+
+	out.Reset()
+	os.Args = []string{"cleed", "list", "tech", "--rename", "dev"}
+
+	err = root.Cmd.Execute()
+	assert.NoError(t, err)
+	assert.Equal(t, "list tech was renamed to dev\n", out.String())
+
+	lists, err := storage.LoadLists()
+	assert.NoError(t, err)
+	assert.ElementsMatch(t, []string{"abc", "dev", "dev/go", "news/local", "tech-news"}, lists)
+
+	os.Args = []string{"cleed", "list", "dev", "--rename", "dev/old"}
+
+	err = root.Cmd.Execute()
+	assert.EqualError(t, err, "failed to rename list: cannot rename list into itself: dev/old")
+}
+
+func Test_List_Pause_Resume(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	timeMock := mocks.NewMockTime(ctrl)
+	timeMock.EXPECT().Now().Return(defaultCurrentTime).AnyTimes()
+
+	out := new(bytes.Buffer)
+	printer := internal.NewPrinter(nil, out, out)
+	storage := _storage.NewLocalStorage("cleed_test", timeMock)
+	defer localStorageCleanup(t, storage)
+
+	configDir, err := os.UserConfigDir()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	listsDir := path.Join(configDir, "cleed_test", "lists")
+	err = os.MkdirAll(listsDir, 0700)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	rss := createDefaultRSS()
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Write([]byte(rss))
+	}))
+	defer server.Close()
+
+	for _, list := range []string{"tech", "tech/go"} {
+		err = os.WriteFile(path.Join(listsDir, url.QueryEscape(list)),
+			[]byte(fmt.Sprintf("%d %s\n", defaultCurrentTime.Unix(), server.URL+"/"+list)), 0600)
+		if err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	feed := internal.NewTerminalFeed(timeMock, printer, storage)
+
+	root, err := NewRoot("0.1.0", timeMock, printer, storage, feed)
+	assert.NoError(t, err)
+
+	os.Args = []string{"cleed", "list", "tech", "--pause"}
+
+	err = root.Cmd.Execute()
+	assert.NoError(t, err)
+	assert.Equal(t, "list tech was paused\n", out.String())
+
+	out.Reset()
+	os.Args = []string{"cleed", "list"}
+
+	err = root.Cmd.Execute()
+	assert.NoError(t, err)
+	assert.Equal(t, "tech (paused)\n  go\n", out.String())
+
+	// nested lists are paused with their parent
+	out.Reset()
+	os.Args = []string{"cleed"}
+
+	err = root.Cmd.Execute()
+	assert.NoError(t, err)
+	assert.Equal(t, "no items to display\n", out.String())
+
+	root, err = NewRoot("0.1.0", timeMock, printer, storage, feed)
+	assert.NoError(t, err)
+
+	os.Args = []string{"cleed", "list", "tech/go", "--resume"}
+
+	err = root.Cmd.Execute()
+	assert.EqualError(t, err, "list is paused by a parent list: tech/go")
+
+	root, err = NewRoot("0.1.0", timeMock, printer, storage, feed)
+	assert.NoError(t, err)
+
+	out.Reset()
+	os.Args = []string{"cleed", "list", "tech", "--resume"}
+
+	err = root.Cmd.Execute()
+	assert.NoError(t, err)
+	assert.Equal(t, "list tech was resumed\n", out.String())
+
+	config, err := storage.LoadConfig()
+	assert.NoError(t, err)
+	assert.Empty(t, config.PausedLists)
+
+	os.Args = []string{"cleed", "list", "unknown", "--pause"}
+
+	err = root.Cmd.Execute()
+	assert.EqualError(t, err, "list not found: unknown")
+}
+
+func Test_List_Pause_Rename_Merge(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	timeMock := mocks.NewMockTime(ctrl)
+	timeMock.EXPECT().Now().Return(defaultCurrentTime).AnyTimes()
+
+	out := new(bytes.Buffer)
+	printer := internal.NewPrinter(nil, out, out)
+	storage := _storage.NewLocalStorage("cleed_test", timeMock)
+	defer localStorageCleanup(t, storage)
+
+	configDir, err := os.UserConfigDir()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	listsDir := path.Join(configDir, "cleed_test", "lists")
+	err = os.MkdirAll(listsDir, 0700)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	for _, list := range []string{"tech", "tech/go", "news", "misc", "misc/local"} {
+		err = os.WriteFile(path.Join(listsDir, url.QueryEscape(list)),
+			[]byte(fmt.Sprintf("%d %s\n", defaultCurrentTime.Unix(), "https://"+list+".com")), 0600)
+		if err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	feed := internal.NewTerminalFeed(timeMock, printer, storage)
+
+	for _, args := range [][]string{
+		{"list", "tech", "--pause"},
+		{"list", "misc", "--pause"},
+		{"list", "tech", "--rename", "dev"},
+		{"list", "news", "--merge", "misc"},
+		{"list"},
+	} {
+		root, err := NewRoot("0.1.0", timeMock, printer, storage, feed)
+		assert.NoError(t, err)
+
+		out.Reset()
+		os.Args = append([]string{"cleed"}, args...)
+		err = root.Cmd.Execute()
+		assert.NoError(t, err)
+	}
+	assert.Equal(t, "dev (paused)\n  go\nmisc\n  local (paused)\nnews\n", out.String())
+
+	// the paused lists follow the renamed and merged lists
+	config, err := storage.LoadConfig()
+	assert.NoError(t, err)
+	assert.Equal(t, []string{"dev", "misc/local"}, config.PausedLists)
+
+	// a new list with the old name is not paused
+	assert.False(t, config.IsListPaused("tech"))
+}
+
+func Test_List_Stats(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	timeMock := mocks.NewMockTime(ctrl)
+	timeMock.EXPECT().Now().Return(defaultCurrentTime).AnyTimes()
+
+	out := new(bytes.Buffer)
+	printer := internal.NewPrinter(nil, out, out)
+	storage := _storage.NewLocalStorage("cleed_test", timeMock)
+	defer localStorageCleanup(t, storage)
+
+	configDir, err := os.UserConfigDir()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	listsDir := path.Join(configDir, "cleed_test", "lists")
+	err = os.MkdirAll(listsDir, 0700)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	err = os.WriteFile(path.Join(listsDir, "tech"),
+		[]byte(fmt.Sprintf("%d %s\n%d %s\n",
+			defaultCurrentTime.Unix(), "https://example.com/a",
+			defaultCurrentTime.Unix()+300, "https://example.com/b",
+		),
+		), 0600)
+	if err != nil {
+		t.Fatal(err)
+	}
+	err = os.WriteFile(path.Join(listsDir, "news"),
+		[]byte(fmt.Sprintf("%d %s\n", defaultCurrentTime.Unix(), "https://example.com/a")), 0600)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	feed := internal.NewTerminalFeed(timeMock, printer, storage)
+
+	root, err := NewRoot("0.1.0", timeMock, printer, storage, feed)
+	assert.NoError(t, err)
+
+	err = storage.SaveFeedCache(bytes.NewBufferString(createRSS([]*FeedItem{
+		{
+			Title:     "Item 1",
+			Link:      "https://rss-feed.com/item-1/",
+			Published: defaultCurrentTime.Add(-24 * time.Hour).Format(time.RFC1123Z),
+		},
+		{
+			Title:     "Item 2",
+			Link:      "https://rss-feed.com/item-2/",
+			Published: defaultCurrentTime.Add(-8 * 24 * time.Hour).Format(time.RFC1123Z),
+		},
+		{
+			Title:     "Item 3",
+			Link:      "https://rss-feed.com/item-3/",
+			Published: defaultCurrentTime.Add(-15 * 24 * time.Hour).Format(time.RFC1123Z),
+		},
+	})), "https://example.com/a")
+	if err != nil {
+		t.Fatal(err)
+	}
+	err = storage.SaveCacheInfo(map[string]*_storage.CacheInfoItem{
+		"https://example.com/a": {
+			URL:        "https://example.com/a",
+			LastFetch:  defaultCurrentTime.Add(-time.Hour),
+			FetchAfter: defaultCurrentTime,
+		},
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	err = storage.MarkRead([]string{"https://rss-feed.com/item-3/"}, "https://example.com/a")
+	if err != nil {
+		t.Fatal(err)
+	}
+	err = storage.AddFetchHistory(&_storage.FetchHistoryItem{
+		Time:  defaultCurrentTime,
+		Error: "connection refused",
+	}, "https://example.com/b")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	os.Args = []string{"cleed", "list", "--stats"}
+
+	err = root.Cmd.Execute()
+	assert.NoError(t, err)
+	assert.Equal(t, `List  Feeds  Items  Unread  Newest item          Failing
+news  1      3      2       2023-12-31 00:00:00  0
+tech  2      3      2       2023-12-31 00:00:00  1
+Total: 2 lists, 2 feeds, 3 items, 2 unread
+`, out.String())
+
+	root, err = NewRoot("0.1.0", timeMock, printer, storage, feed)
+	assert.NoError(t, err)
+
+	out.Reset()
+	os.Args = []string{"cleed", "list", "--stats", "--sort", "failing"}
+
+	err = root.Cmd.Execute()
+	assert.NoError(t, err)
+	assert.Equal(t, `List  Feeds  Items  Unread  Newest item          Failing
+tech  2      3      2       2023-12-31 00:00:00  1
+news  1      3      2       2023-12-31 00:00:00  0
+Total: 2 lists, 2 feeds, 3 items, 2 unread
+`, out.String())
+
+	root, err = NewRoot("0.1.0", timeMock, printer, storage, feed)
+	assert.NoError(t, err)
+
+	out.Reset()
+	os.Args = []string{"cleed", "list", "tech", "--stats", "--sort", "url"}
+
+	err = root.Cmd.Execute()
+	assert.NoError(t, err)
+	assert.Equal(t, `Title     URL                    Last fetch           Frequency  Last item
+RSS Feed  https://example.com/a  2023-12-31 23:00:00  1.0/week   2023-12-31 00:00:00
+-         https://example.com/b  -                    -          -                    (failing)
+Total: 2 feeds, 3 items, 2 unread
+`, out.String())
+
+	root, err = NewRoot("0.1.0", timeMock, printer, storage, feed)
+	assert.NoError(t, err)
+
+	os.Args = []string{"cleed", "list", "tech", "--stats", "--sort", "unread"}
+
+	err = root.Cmd.Execute()
+	assert.EqualError(t, err, "invalid sort: unread. Use added, title, url, fetched, frequency, newest")
+}
+
+func Test_List_Stale(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	timeMock := mocks.NewMockTime(ctrl)
+	timeMock.EXPECT().Now().Return(defaultCurrentTime).AnyTimes()
+
+	out := new(bytes.Buffer)
+	printer := internal.NewPrinter(nil, out, out)
+	storage := _storage.NewLocalStorage("cleed_test", timeMock)
+	defer localStorageCleanup(t, storage)
+
+	configDir, err := os.UserConfigDir()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	listsDir := path.Join(configDir, "cleed_test", "lists")
+	err = os.MkdirAll(listsDir, 0700)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	err = os.WriteFile(path.Join(listsDir, "tech"),
+		[]byte(fmt.Sprintf("%d %s\n%d %s\n%d %s\n%d %s\n",
+			defaultCurrentTime.Unix(), "https://example.com/active",
+			defaultCurrentTime.Unix()+100, "https://example.com/dormant",
+			defaultCurrentTime.Unix()+200, "https://example.com/dead",
+			defaultCurrentTime.Unix()+300, "https://example.com/empty",
+		),
+		), 0600)
+	if err != nil {
+		t.Fatal(err)
+	}
+	err = os.WriteFile(path.Join(listsDir, "news"),
+		[]byte(fmt.Sprintf("%d %s\n", defaultCurrentTime.Unix(), "https://example.com/dormant")), 0600)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	feed := internal.NewTerminalFeed(timeMock, printer, storage)
+
+	root, err := NewRoot("0.1.0", timeMock, printer, storage, feed)
+	assert.NoError(t, err)
+
+	err = storage.SaveFeedCache(bytes.NewBufferString(createRSS([]*FeedItem{
+		{
+			Title:     "Item 1",
+			Link:      "https://rss-feed.com/item-1/",
+			Published: defaultCurrentTime.Add(-24 * time.Hour).Format(time.RFC1123Z),
+		},
+	})), "https://example.com/active")
+	if err != nil {
+		t.Fatal(err)
+	}
+	err = storage.SaveFeedCache(bytes.NewBufferString(createRSS([]*FeedItem{
+		{
+			Title:     "Item 1",
+			Link:      "https://rss-feed.com/item-1/",
+			Published: defaultCurrentTime.Add(-200 * 24 * time.Hour).Format(time.RFC1123Z),
+		},
+	})), "https://example.com/dormant")
+	if err != nil {
+		t.Fatal(err)
+	}
+	err = storage.SaveFeedCache(bytes.NewBufferString(createRSS(nil)), "https://example.com/empty")
+	if err != nil {
+		t.Fatal(err)
+	}
+	for i := 0; i < 3; i++ {
+		err = storage.AddFetchHistory(&_storage.FetchHistoryItem{
+			Time:  defaultCurrentTime.Add(time.Duration(i) * time.Hour),
+			Error: "connection refused",
+		}, "https://example.com/dead")
+		if err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	os.Args = []string{"cleed", "list", "--stale", "180d"}
+
+	err = root.Cmd.Execute()
+	assert.NoError(t, err)
+	assert.Equal(t, `URL                          Lists       Reason
+https://example.com/dead     tech        failed 3 times in a row: connection refused
+https://example.com/dormant  news, tech  last item 200 days ago
+https://example.com/empty    tech        no items
+Total: 3 stale feeds
+`, out.String())
+
+	root, err = NewRoot("0.1.0", timeMock, printer, storage, feed)
+	assert.NoError(t, err)
+
+	out.Reset()
+	os.Args = []string{"cleed", "list", "--stale", "365d"}
+
+	err = root.Cmd.Execute()
+	assert.NoError(t, err)
+	assert.Equal(t, `URL                        Lists  Reason
+https://example.com/dead   tech   failed 3 times in a row: connection refused
+https://example.com/empty  tech   no items
+Total: 2 stale feeds
+`, out.String())
+
+	root, err = NewRoot("0.1.0", timeMock, printer, storage, feed)
+	assert.NoError(t, err)
+
+	out.Reset()
+	os.Args = []string{"cleed", "list", "tech", "--stale", "180d", "--archive"}
+
+	err = root.Cmd.Execute()
+	assert.NoError(t, err)
+	assert.Equal(t, `URL                          Lists  Reason
+https://example.com/dead     tech   failed 3 times in a row: connection refused
+https://example.com/dormant  tech   last item 200 days ago
+https://example.com/empty    tech   no items
+Total: 3 stale feeds
+moved 3 feeds to archive
+`, out.String())
+
+	items, err := storage.GetFeedsFromList("tech")
+	assert.NoError(t, err)
+	assert.Len(t, items, 1)
+	assert.Equal(t, "https://example.com/active", items[0].Address)
+
+	items, err = storage.GetFeedsFromList("news")
+	assert.NoError(t, err)
+	assert.Len(t, items, 1)
+
+	items, err = storage.GetFeedsFromList("archive")
+	assert.NoError(t, err)
+	assert.Len(t, items, 3)
+
+	// the archive list is skipped when checking all lists
+	root, err = NewRoot("0.1.0", timeMock, printer, storage, feed)
+	assert.NoError(t, err)
+
+	out.Reset()
+	os.Args = []string{"cleed", "list", "--stale", "180d"}
+
+	err = root.Cmd.Execute()
+	assert.NoError(t, err)
+	assert.Equal(t, `URL                          Lists  Reason
+https://example.com/dormant  news   last item 200 days ago
+Total: 1 stale feed
+`, out.String())
+}
+
+func Test_List_Duplicates(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	timeMock := mocks.NewMockTime(ctrl)
+	timeMock.EXPECT().Now().Return(defaultCurrentTime).AnyTimes()
+
+	out := new(bytes.Buffer)
+	printer := internal.NewPrinter(strings.NewReader("2\nx\n2\n"), out, out)
+	storage := _storage.NewLocalStorage("cleed_test", timeMock)
+	defer localStorageCleanup(t, storage)
+
+	configDir, err := os.UserConfigDir()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	listsDir := path.Join(configDir, "cleed_test", "lists")
+	err = os.MkdirAll(listsDir, 0700)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	lists := map[string][]string{
+		"tech": {"https://example.com/feed", "https://blog.com/rss"},
+		"news": {"http://www.example.com/feed/", "https://other.com/atom"},
+		"misc": {"https://example.com/feed", "https://solo.com/feed"},
+	}
+	for list, urls := range lists {
+		content := ""
+		for _, u := range urls {
+			content += fmt.Sprintf("%d %s\n", defaultCurrentTime.Unix(), u)
+		}
+		err = os.WriteFile(path.Join(listsDir, list), []byte(content), 0600)
+		if err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	feed := internal.NewTerminalFeed(timeMock, printer, storage)
+
+	root, err := NewRoot("0.1.0", timeMock, printer, storage, feed)
+	assert.NoError(t, err)
+
+	// the cached feed declares the URL of another feed as its own
+	err = storage.SaveFeedCache(bytes.NewBufferString(`<?xml version="1.0" encoding="utf-8"?>
+<feed xmlns="http://www.w3.org/2005/Atom">
+	<title>Blog</title>
+	<link rel="self" href="https://blog.com/rss"/>
+	<link href="https://blog.com/"/>
+</feed>`), "https://other.com/atom")
+	if err != nil {
+		t.Fatal(err)
+	}
+	err = storage.SaveFeedCache(bytes.NewBufferString(createDefaultRSS()), "https://solo.com/feed")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	os.Args = []string{"cleed", "list", "--duplicates"}
+
+	err = root.Cmd.Execute()
+	assert.NoError(t, err)
+	assert.Equal(t, `same URL
+  1  http://www.example.com/feed/  news
+  2  https://example.com/feed      misc
+  3  https://example.com/feed      tech
+
+same feed URL
+  1  https://blog.com/rss    tech
+  2  https://other.com/atom  news
+Total: 2 groups, 5 feeds
+`, out.String())
+
+	root, err = NewRoot("0.1.0", timeMock, printer, storage, feed)
+	assert.NoError(t, err)
+
+	out.Reset()
+	os.Args = []string{"cleed", "list", "--duplicates", "-i"}
+
+	err = root.Cmd.Execute()
+	assert.NoError(t, err)
+	assert.Equal(t, `same URL
+  1  http://www.example.com/feed/  news
+  2  https://example.com/feed      misc
+  3  https://example.com/feed      tech
+keep (e.g. 1 or 1,3, empty to skip): removed 2 feeds
+
+same feed URL
+  1  https://blog.com/rss    tech
+  2  https://other.com/atom  news
+keep (e.g. 1 or 1,3, empty to skip): invalid selection
+keep (e.g. 1 or 1,3, empty to skip): removed 1 feed
+Total: 2 groups, 5 feeds
+removed 3 duplicate feeds
+`, out.String())
+
+	expected := map[string][]string{
+		"tech": {},
+		"news": {"https://other.com/atom"},
+		"misc": {"https://example.com/feed", "https://solo.com/feed"},
+	}
+	for list, urls := range expected {
+		items, err := storage.GetFeedsFromList(list)
+		assert.NoError(t, err)
+		addresses := make([]string, 0)
+		for _, item := range items {
+			addresses = append(addresses, item.Address)
+		}
+		assert.Equal(t, urls, addresses, list)
+	}
+
+	root, err = NewRoot("0.1.0", timeMock, printer, storage, feed)
+	assert.NoError(t, err)
+
+	out.Reset()
+	os.Args = []string{"cleed", "list", "--duplicates"}
+
+	err = root.Cmd.Execute()
+	assert.NoError(t, err)
+	assert.Equal(t, "no duplicate feeds found\n", out.String())
+}
+
 func Test_List_Rename(t *testing.T) {
 	ctrl := gomock.NewController(t)
 	defer ctrl.Finish()
@@ -264,6 +885,138 @@ func Test_List_Merge(t *testing.T) {
 	}, items)
 }
 
+func Test_List_Move_Copy(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	timeMock := mocks.NewMockTime(ctrl)
+	timeMock.EXPECT().Now().Return(defaultCurrentTime).AnyTimes()
+
+	out := new(bytes.Buffer)
+	printer := internal.NewPrinter(nil, out, out)
+	storage := _storage.NewLocalStorage("cleed_test", timeMock)
+	defer localStorageCleanup(t, storage)
+
+	configDir, err := os.UserConfigDir()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	listsDir := path.Join(configDir, "cleed_test", "lists")
+	err = os.MkdirAll(listsDir, 0700)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	err = os.WriteFile(path.Join(listsDir, "src"),
+		[]byte(fmt.Sprintf("%d %s\n%d %s\n%d %s\n",
+			defaultCurrentTime.Unix(), "https://example.com/a",
+			defaultCurrentTime.Unix()+300, "https://example.com/b",
+			defaultCurrentTime.Unix()+600, "https://other.com/c",
+		),
+		), 0600)
+	if err != nil {
+		t.Fatal(err)
+	}
+	err = os.WriteFile(path.Join(listsDir, "dst"),
+		[]byte(fmt.Sprintf("%d %s\n", defaultCurrentTime.Unix()+100, "https://other.com/d")), 0600)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	feed := internal.NewTerminalFeed(timeMock, printer, storage)
+
+	root, err := NewRoot("0.1.0", timeMock, printer, storage, feed)
+	assert.NoError(t, err)
+
+	err = storage.SaveFeedCache(bytes.NewBufferString(createDefaultRSS()), "https://other.com/c")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	os.Args = []string{"cleed", "list", "src", "--copy", "https://example.com/*", "--to", "dst"}
+
+	err = root.Cmd.Execute()
+	assert.NoError(t, err)
+	assert.Equal(t, "copied 2 feeds from src to dst\n", out.String())
+
+	items, err := storage.GetFeedsFromList("dst")
+	assert.NoError(t, err)
+	assert.Equal(t, []*_storage.ListItem{
+		{AddedAt: time.Unix(defaultCurrentTime.Unix(), 0), Address: "https://example.com/a"},
+		{AddedAt: time.Unix(defaultCurrentTime.Unix()+100, 0), Address: "https://other.com/d"},
+		{AddedAt: time.Unix(defaultCurrentTime.Unix()+300, 0), Address: "https://example.com/b"},
+	}, items)
+
+	root, err = NewRoot("0.1.0", timeMock, printer, storage, feed)
+	assert.NoError(t, err)
+
+	out.Reset()
+	os.Args = []string{"cleed", "list", "src", "--move", "http://www.other.com/c/", "--to", "dst"}
+
+	err = root.Cmd.Execute()
+	assert.NoError(t, err)
+	assert.Equal(t, "moved 1 feed from src to dst\n", out.String())
+
+	items, err = storage.GetFeedsFromList("src")
+	assert.NoError(t, err)
+	assert.Equal(t, []*_storage.ListItem{
+		{AddedAt: time.Unix(defaultCurrentTime.Unix(), 0), Address: "https://example.com/a"},
+		{AddedAt: time.Unix(defaultCurrentTime.Unix()+300, 0), Address: "https://example.com/b"},
+	}, items)
+
+	items, err = storage.GetFeedsFromList("dst")
+	assert.NoError(t, err)
+	assert.Equal(t, &_storage.ListItem{
+		AddedAt: time.Unix(defaultCurrentTime.Unix()+600, 0),
+		Address: "https://other.com/c",
+	}, items[len(items)-1])
+
+	// the cache of a moved feed is kept
+	cache, err := storage.OpenFeedCache("https://other.com/c")
+	assert.NoError(t, err)
+	cache.Close()
+
+	root, err = NewRoot("0.1.0", timeMock, printer, storage, feed)
+	assert.NoError(t, err)
+
+	os.Args = []string{"cleed", "list", "src", "--move", "https://none.com", "--to", "dst"}
+
+	err = root.Cmd.Execute()
+	assert.EqualError(t, err, "no feed in list src matches: https://none.com")
+
+	root, err = NewRoot("0.1.0", timeMock, printer, storage, feed)
+	assert.NoError(t, err)
+
+	os.Args = []string{"cleed", "list", "src", "--move", "https://example.com/a"}
+
+	err = root.Cmd.Execute()
+	assert.EqualError(t, err, "please provide the destination list with --to")
+
+	// several feeds can be given as extra arguments
+	root, err = NewRoot("0.1.0", timeMock, printer, storage, feed)
+	assert.NoError(t, err)
+
+	out.Reset()
+	os.Args = []string{"cleed", "list", "src", "--move", "https://example.com/a", "https://example.com/b", "--to", "other"}
+
+	err = root.Cmd.Execute()
+	assert.NoError(t, err)
+	assert.Equal(t, "moved 2 feeds from src to other\n", out.String())
+
+	items, err = storage.GetFeedsFromList("other")
+	assert.NoError(t, err)
+	assert.Len(t, items, 2)
+
+	root, err = NewRoot("0.1.0", timeMock, printer, storage, feed)
+	assert.NoError(t, err)
+
+	os.Args = []string{"cleed", "list", "src", "other"}
+
+	err = root.Cmd.Execute()
+	assert.EqualError(t, err, "accepts at most 1 arg(s), received 2")
+}
+
 func Test_List_Remove(t *testing.T) {
 	ctrl := gomock.NewController(t)
 	defer ctrl.Finish()
@@ -366,6 +1119,10 @@ func Test_List_Remove(t *testing.T) {
 	assert.NoError(t, err)
 	assert.Equal(t, "list test was removed\n", out.String())
 
+	// the caches are kept until the change can no longer be undone
+	assert.FileExists(t, path.Join(cacheDir, "feed_"+url.QueryEscape("https://example2.com")))
+	expireJournal(t, storage)
+
 	files, err := os.ReadDir(cacheDir)
 	if err != nil {
 		t.Fatal(err)
@@ -443,7 +1200,8 @@ func Test_List_ImportFromFile(t *testing.T) {
 		[]byte(`https://example0.com
  https://test.com
 # comment
-https://example2.com`), 0600)
+HTTP://www.Example.com/
+https://example2.com?utm_source=list`), 0600)
 	if err != nil {
 		t.Fatal(err)
 	}
@@ -457,7 +1215,8 @@ https://example2.com`), 0600)
 
 	err = root.Cmd.Execute()
 	assert.NoError(t, err)
-	assert.Equal(t, "added 3 feeds to list: test\n", out.String())
+	// feeds already in the list are skipped, even with a different URL
+	assert.Equal(t, "added 2 feeds to list: test\n", out.String())
 
 	items, err := storage.GetFeedsFromList("test")
 	assert.NoError(t, err)
@@ -684,6 +1443,96 @@ func Test_List_ImportFromOPML_Multiple_Lists(t *testing.T) {
 	}, items)
 }
 
+func Test_List_ImportFromOPML_Nested(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	timeMock := mocks.NewMockTime(ctrl)
+	timeMock.EXPECT().Now().Return(defaultCurrentTime).AnyTimes()
+
+	out := new(bytes.Buffer)
+	printer := internal.NewPrinter(nil, out, out)
+	storage := _storage.NewLocalStorage("cleed_test", timeMock)
+	defer localStorageCleanup(t, storage)
+
+	configDir, err := os.UserConfigDir()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	importFilePath := path.Join(configDir, "import.opml")
+	err = os.WriteFile(importFilePath,
+		[]byte(`<?xml version="1.0" encoding="UTF-8"?>
+<opml version="1.0">
+  <head>
+    <title>test</title>
+  </head>
+  <body>
+    <outline xmlUrl="https://example.com"/>
+    <outline text="tech">
+      <outline xmlUrl="https://example1.com"/>
+      <outline text="go">
+        <outline xmlUrl="https://example2.com"/>
+        <outline text="releases">
+          <outline xmlUrl="https://example3.com"/>
+        </outline>
+      </outline>
+      <outline text="web/css">
+        <outline xmlUrl="https://example4.com"/>
+      </outline>
+    </outline>
+    <outline text="News/Politics">
+      <outline xmlUrl="https://example5.com"/>
+    </outline>
+  </body>
+</opml>`), 0600)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	feed := internal.NewTerminalFeed(timeMock, printer, storage)
+
+	root, err := NewRoot("0.1.0", timeMock, printer, storage, feed)
+	assert.NoError(t, err)
+
+	os.Args = []string{"cleed", "list", "--import-from-opml", importFilePath}
+
+	err = root.Cmd.Execute()
+	assert.NoError(t, err)
+	assert.Equal(t, `added 1 feed to list: default
+added 1 feed to list: tech
+added 1 feed to list: tech/go
+added 1 feed to list: tech/go/releases
+added 1 feed to list: tech/web-css
+added 1 feed to list: News-Politics
+`, out.String())
+
+	items, err := storage.GetFeedsFromList("tech/go/releases")
+	assert.NoError(t, err)
+	assert.Equal(t, []*_storage.ListItem{
+		{AddedAt: time.Unix(defaultCurrentTime.Unix(), 0), Address: "https://example3.com"},
+	}, items)
+
+	// a separator in a folder name does not nest lists
+	items, err = storage.GetFeedsFromList("News-Politics")
+	assert.NoError(t, err)
+	assert.Equal(t, []*_storage.ListItem{
+		{AddedAt: time.Unix(defaultCurrentTime.Unix(), 0), Address: "https://example5.com"},
+	}, items)
+
+	// nested folders are kept when importing into a list
+	out.Reset()
+	os.Args = []string{"cleed", "list", "imported", "--import-from-opml", importFilePath}
+
+	err = root.Cmd.Execute()
+	assert.NoError(t, err)
+	assert.Equal(t, `added 3 feeds to list: imported
+added 1 feed to list: imported/go
+added 1 feed to list: imported/go/releases
+added 1 feed to list: imported/web-css
+`, out.String())
+}
+
 func Test_List_ExportToOPML(t *testing.T) {
 	ctrl := gomock.NewController(t)
 	defer ctrl.Finish()
@@ -944,3 +1793,97 @@ func Test_List_ExportToOPML_Multiple_Lists(t *testing.T) {
   </body>
 </opml>`, string(b))
 }
+
+func Test_List_ExportToOPML_Nested(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	timeMock := mocks.NewMockTime(ctrl)
+	timeMock.EXPECT().Now().Return(defaultCurrentTime).AnyTimes()
+
+	out := new(bytes.Buffer)
+	printer := internal.NewPrinter(nil, out, out)
+	storage := _storage.NewLocalStorage("cleed_test", timeMock)
+	defer localStorageCleanup(t, storage)
+
+	configDir, err := os.UserConfigDir()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	listsDir := path.Join(configDir, "cleed_test", "lists")
+	err = os.MkdirAll(listsDir, 0700)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	lists := map[string]string{
+		"tech":       "https://example.com",
+		"tech/go":    "https://example1.com",
+		"news/local": "https://example2.com",
+	}
+	for list, address := range lists {
+		err = os.WriteFile(path.Join(listsDir, url.QueryEscape(list)),
+			[]byte(fmt.Sprintf("%d %s\n", defaultCurrentTime.Unix(), address)), 0600)
+		if err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	feed := internal.NewTerminalFeed(timeMock, printer, storage)
+
+	root, err := NewRoot("0.1.0", timeMock, printer, storage, feed)
+	assert.NoError(t, err)
+
+	exportPath := path.Join(configDir, "export.opml")
+	os.Args = []string{"cleed", "list", "--export-to-opml", exportPath}
+
+	err = root.Cmd.Execute()
+	assert.NoError(t, err)
+	assert.Equal(t, fmt.Sprintf("exported 3 feeds from 3 lists to %s\n", exportPath), out.String())
+
+	b, err := os.ReadFile(exportPath)
+	if err != nil {
+		t.Fatal(err)
+	}
+	assert.Equal(t, `<?xml version="1.0" encoding="UTF-8"?>
+<opml version="1.0">
+  <head>
+    <title>Export from cleed/v0.1.0 (github.com/radulucut/cleed)</title>
+    <dateCreated>Mon, 01 Jan 2024 00:00:00 +0000</dateCreated>
+  </head>
+  <body>
+    <outline text="news">
+      <outline text="local">
+        <outline xmlUrl="https://example2.com" />
+      </outline>
+    </outline>
+    <outline text="tech">
+      <outline xmlUrl="https://example.com" />
+      <outline text="go">
+        <outline xmlUrl="https://example1.com" />
+      </outline>
+    </outline>
+  </body>
+</opml>`, string(b))
+
+	out.Reset()
+	os.Args = []string{"cleed", "list", "tech", "--export-to-opml", exportPath}
+
+	err = root.Cmd.Execute()
+	assert.NoError(t, err)
+	assert.Equal(t, fmt.Sprintf("exported 2 feeds from 2 lists to %s\n", exportPath), out.String())
+
+	b, err = os.ReadFile(exportPath)
+	if err != nil {
+		t.Fatal(err)
+	}
+	assert.Contains(t, string(b), `  <body>
+    <outline text="tech">
+      <outline xmlUrl="https://example.com" />
+      <outline text="go">
+        <outline xmlUrl="https://example1.com" />
+      </outline>
+    </outline>
+  </body>`)
+}
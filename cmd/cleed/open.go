@@ -0,0 +1,75 @@
+package cleed
+
+import (
+	"github.com/radulucut/cleed/internal"
+	"github.com/spf13/cobra"
+)
+
+func (r *Root) initOpen() {
+	cmd := &cobra.Command{
+		Use:   "open [item-id|index]...",
+		Short: "Open items in the browser",
+		Long: `Open items from the cached feeds in the browser and mark them as read
+
+Items are opened with the configured command (cleed config --open-command),
+$BROWSER or the system opener. An index is the position of the item in the
+last displayed timeline, counting from the newest item: 1 is the newest item,
+which the timeline displays last. Templates can show the index with
+{{.Index}} and the ID with {{.ID}}.
+
+Examples:
+  # Open the newest item
+  cleed open 1
+
+  # Number the items with a template, then open one by its index
+  cleed config --template='numbered={{.Index}}. {{.Title}} ({{.Relative}})'
+  cleed --layout numbered
+  cleed open 3
+
+  # Display a list, then open its two newest items
+  cleed --list my-list
+  cleed open 1 2
+
+  # Open an item by its ID or link
+  cleed open https://example.com/post
+
+  # Open all unread items from a list, at most 20
+  cleed open --unread --list my-list
+
+  # Open all unread items from the last day
+  cleed open --unread --since 1d --limit 0
+`,
+
+		RunE: r.RunOpen,
+	}
+
+	flags := cmd.Flags()
+	flags.StringP("list", "L", "", "lists to open items from by ID or with --unread, separated by commas. Glob patterns are supported")
+	flags.Bool("unread", false, "open the items that were not marked as read")
+	flags.String("since", "", "open items since the last run (last), a specific date (e.g. 2024-01-01 12:03:04) or duration (e.g. 1d)")
+	flags.Uint("limit", 20, "limit the number of unread items to open (0: unlimited)")
+
+	r.Cmd.AddCommand(cmd)
+}
+
+func (r *Root) RunOpen(cmd *cobra.Command, args []string) error {
+	since, err := r.parseSinceFlag(cmd.Flag("since").Value.String())
+	if err != nil {
+		return err
+	}
+	limit, err := cmd.Flags().GetUint("limit")
+	if err != nil {
+		return err
+	}
+	opts := &internal.OpenOptions{
+		Lists:   splitLists(cmd.Flag("list").Value.String()),
+		Targets: args,
+		Since:   since,
+		Limit:   int(limit),
+	}
+	opts.Unread, err = cmd.Flags().GetBool("unread")
+	if err != nil {
+		return err
+	}
+	return r.feed.Open(opts)
+}
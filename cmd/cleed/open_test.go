@@ -0,0 +1,372 @@
+package cleed
+
+import (
+	"bytes"
+	"fmt"
+	"os"
+	"path"
+	"runtime"
+	"slices"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/radulucut/cleed/internal"
+	_storage "github.com/radulucut/cleed/internal/storage"
+	"github.com/radulucut/cleed/mocks"
+	"github.com/stretchr/testify/assert"
+	"go.uber.org/mock/gomock"
+)
+
+func Test_Open(t *testing.T) {
+	if runtime.GOOS == "windows" {
+		t.Skip("the open command is a shell script")
+	}
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	timeMock := mocks.NewMockTime(ctrl)
+	timeMock.EXPECT().Now().Return(defaultCurrentTime).AnyTimes()
+
+	out := new(bytes.Buffer)
+	printer := internal.NewPrinter(nil, out, out)
+	storage := _storage.NewLocalStorage("cleed_test", timeMock)
+	defer localStorageCleanup(t, storage)
+
+	configDir, err := os.UserConfigDir()
+	if err != nil {
+		t.Fatal(err)
+	}
+	listsDir := path.Join(configDir, "cleed_test", "lists")
+	err = os.MkdirAll(listsDir, 0700)
+	if err != nil {
+		t.Fatal(err)
+	}
+	err = os.WriteFile(path.Join(listsDir, "default"),
+		fmt.Appendf(nil, "%d %s\n", defaultCurrentTime.Unix(), "https://example.com/feed"), 0600)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// the open command records the opened links
+	dir := t.TempDir()
+	opened := path.Join(dir, "opened")
+	script := path.Join(dir, "open.sh")
+	err = os.WriteFile(script, []byte("#!/bin/sh\necho \"$@\" >> "+opened+"\n"), 0700)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	feed := internal.NewTerminalFeed(timeMock, printer, storage)
+	root, err := NewRoot("0.1.0", timeMock, printer, storage, feed)
+	assert.NoError(t, err)
+
+	err = storage.SaveFeedCache(bytes.NewBufferString(createDefaultRSS()), "https://example.com/feed")
+	if err != nil {
+		t.Fatal(err)
+	}
+	config, err := storage.LoadConfig()
+	assert.NoError(t, err)
+	config.OpenCommand = script + " --new-tab %s"
+	err = storage.SaveConfig()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	os.Args = []string{"cleed", "open", "1"}
+	err = root.Cmd.Execute()
+	assert.NoError(t, err)
+	assert.Equal(t, "opened https://rss-feed.com/item-1/\n", out.String())
+
+	assert.Equal(t, []string{"--new-tab https://rss-feed.com/item-1/"}, readOpened(t, opened, 1))
+
+	readState, err := storage.LoadReadState("https://example.com/feed")
+	assert.NoError(t, err)
+	assert.Equal(t, 1, len(readState))
+	assert.Contains(t, readState, "https://rss-feed.com/item-1/")
+
+	// only the items that were not opened before
+	root, err = NewRoot("0.1.0", timeMock, printer, storage, feed)
+	assert.NoError(t, err)
+
+	out.Reset()
+	os.Args = []string{"cleed", "open", "--unread"}
+	err = root.Cmd.Execute()
+	assert.NoError(t, err)
+	assert.Equal(t, "opened https://rss-feed.com/item-2/\n", out.String())
+
+	root, err = NewRoot("0.1.0", timeMock, printer, storage, feed)
+	assert.NoError(t, err)
+
+	out.Reset()
+	os.Args = []string{"cleed", "open", "--unread"}
+	err = root.Cmd.Execute()
+	assert.NoError(t, err)
+	assert.Equal(t, "no items to open\n", out.String())
+
+	root, err = NewRoot("0.1.0", timeMock, printer, storage, feed)
+	assert.NoError(t, err)
+
+	os.Args = []string{"cleed", "open", "3"}
+	err = root.Cmd.Execute()
+	assert.EqualError(t, err, "item not found: 3")
+
+	root, err = NewRoot("0.1.0", timeMock, printer, storage, feed)
+	assert.NoError(t, err)
+
+	os.Args = []string{"cleed", "open"}
+	err = root.Cmd.Execute()
+	assert.EqualError(t, err, "please provide an item ID or index, or use --unread")
+
+	// open the displayed items, the oldest first
+	err = os.Remove(opened)
+	if err != nil {
+		t.Fatal(err)
+	}
+	config.OpenCommand = script
+	err = storage.SaveConfig()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	root, err = NewRoot("0.1.0", timeMock, printer, storage, feed)
+	assert.NoError(t, err)
+
+	out.Reset()
+	os.Args = []string{"cleed", "-C", "--open"}
+	err = root.Cmd.Execute()
+	assert.NoError(t, err)
+	assert.Equal(t, `RSS Feed        • Item 2
+1688 days ago   https://rss-feed.com/item-2/
+
+RSS Feed        • Item 1
+15 minutes ago  https://rss-feed.com/item-1/
+
+opened https://rss-feed.com/item-2/
+opened https://rss-feed.com/item-1/
+`, out.String())
+
+	assert.Equal(t, []string{"https://rss-feed.com/item-1/", "https://rss-feed.com/item-2/"}, readOpened(t, opened, 2))
+
+	// items in several groups are opened once
+	err = os.WriteFile(path.Join(listsDir, "other"),
+		fmt.Appendf(nil, "%d %s\n", defaultCurrentTime.Unix(), "https://example.com/feed"), 0600)
+	if err != nil {
+		t.Fatal(err)
+	}
+	err = os.Remove(opened)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	root, err = NewRoot("0.1.0", timeMock, printer, storage, feed)
+	assert.NoError(t, err)
+
+	out.Reset()
+	os.Args = []string{"cleed", "-C", "--open", "--group-by", "list"}
+	err = root.Cmd.Execute()
+	assert.NoError(t, err)
+
+	assert.Equal(t, []string{"https://rss-feed.com/item-1/", "https://rss-feed.com/item-2/"}, readOpened(t, opened, 2))
+
+	// templates show the index and the ID accepted by the open command
+	config.Templates = map[string]string{"numbered": "{{.Index}}. {{.Title}} {{.ID}}"}
+	err = storage.SaveConfig()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	root, err = NewRoot("0.1.0", timeMock, printer, storage, feed)
+	assert.NoError(t, err)
+
+	out.Reset()
+	os.Args = []string{"cleed", "-C", "--list", "default", "--layout", "numbered"}
+	err = root.Cmd.Execute()
+	assert.NoError(t, err)
+	assert.Equal(t, "2. Item 2 /item-2/\n1. Item 1 https://rss-feed.com/item-1/\n", out.String())
+
+	root, err = NewRoot("0.1.0", timeMock, printer, storage, feed)
+	assert.NoError(t, err)
+
+	err = os.Remove(opened)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	out.Reset()
+	os.Args = []string{"cleed", "open", "/item-2/"}
+	err = root.Cmd.Execute()
+	assert.NoError(t, err)
+	assert.Equal(t, "opened https://rss-feed.com/item-2/\n", out.String())
+	assert.Equal(t, []string{"https://rss-feed.com/item-2/"}, readOpened(t, opened, 1))
+
+	// indexes refer to the last displayed timeline
+	err = os.WriteFile(path.Join(listsDir, "extra"),
+		fmt.Appendf(nil, "%d %s\n", defaultCurrentTime.Unix(), "https://example.com/extra"), 0600)
+	if err != nil {
+		t.Fatal(err)
+	}
+	err = storage.SaveFeedCache(bytes.NewBufferString(createRSS([]*FeedItem{
+		{
+			Title:     "Extra item",
+			Link:      "https://extra.com/1",
+			Published: defaultCurrentTime.Add(-time.Minute).Format(time.RFC1123Z),
+		},
+	})), "https://example.com/extra")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	root, err = NewRoot("0.1.0", timeMock, printer, storage, feed)
+	assert.NoError(t, err)
+
+	out.Reset()
+	os.Args = []string{"cleed", "-C", "--exclude-list", "extra", "--layout", "numbered"}
+	err = root.Cmd.Execute()
+	assert.NoError(t, err)
+	assert.Equal(t, "2. Item 2 /item-2/\n1. Item 1 https://rss-feed.com/item-1/\n", out.String())
+
+	err = os.Remove(opened)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	root, err = NewRoot("0.1.0", timeMock, printer, storage, feed)
+	assert.NoError(t, err)
+
+	out.Reset()
+	os.Args = []string{"cleed", "open", "1"}
+	err = root.Cmd.Execute()
+	assert.NoError(t, err)
+	assert.Equal(t, "opened https://rss-feed.com/item-1/\n", out.String())
+	assert.Equal(t, []string{"https://rss-feed.com/item-1/"}, readOpened(t, opened, 1))
+
+	// only the new items are opened with --open
+	err = storage.SaveCacheInfo(map[string]*_storage.CacheInfoItem{
+		"https://example.com/feed": {
+			URL:        "https://example.com/feed",
+			LastFetch:  defaultCurrentTime.Add(-30 * time.Minute),
+			FetchAfter: defaultCurrentTime.Add(-30 * time.Minute),
+		},
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	err = os.Remove(opened)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	root, err = NewRoot("0.1.0", timeMock, printer, storage, feed)
+	assert.NoError(t, err)
+
+	out.Reset()
+	os.Args = []string{"cleed", "-C", "--list", "default", "--open"}
+	err = root.Cmd.Execute()
+	assert.NoError(t, err)
+	assert.Contains(t, out.String(), "opened https://rss-feed.com/item-1/\n")
+	assert.NotContains(t, out.String(), "opened https://rss-feed.com/item-2/")
+	assert.Equal(t, []string{"https://rss-feed.com/item-1/"}, readOpened(t, opened, 1))
+}
+
+func Test_Open_Confirm(t *testing.T) {
+	if runtime.GOOS == "windows" {
+		t.Skip("the open command is a shell script")
+	}
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	timeMock := mocks.NewMockTime(ctrl)
+	timeMock.EXPECT().Now().Return(defaultCurrentTime).AnyTimes()
+
+	in := new(bytes.Buffer)
+	out := new(bytes.Buffer)
+	printer := internal.NewPrinter(in, out, out)
+	storage := _storage.NewLocalStorage("cleed_test", timeMock)
+	defer localStorageCleanup(t, storage)
+
+	configDir, err := os.UserConfigDir()
+	if err != nil {
+		t.Fatal(err)
+	}
+	listsDir := path.Join(configDir, "cleed_test", "lists")
+	err = os.MkdirAll(listsDir, 0700)
+	if err != nil {
+		t.Fatal(err)
+	}
+	err = os.WriteFile(path.Join(listsDir, "default"),
+		fmt.Appendf(nil, "%d %s\n", defaultCurrentTime.Unix(), "https://example.com/feed"), 0600)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	dir := t.TempDir()
+	opened := path.Join(dir, "opened")
+	script := path.Join(dir, "open.sh")
+	err = os.WriteFile(script, []byte("#!/bin/sh\necho \"$@\" >> "+opened+"\n"), 0700)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	items := make([]*FeedItem, 11)
+	for i := range items {
+		items[i] = &FeedItem{
+			Title:     fmt.Sprintf("Item %d", i+1),
+			Link:      fmt.Sprintf("https://rss-feed.com/item-%d/", i+1),
+			Published: defaultCurrentTime.Add(-time.Duration(i+1) * time.Hour).Format(time.RFC1123Z),
+		}
+	}
+
+	feed := internal.NewTerminalFeed(timeMock, printer, storage)
+	root, err := NewRoot("0.1.0", timeMock, printer, storage, feed)
+	assert.NoError(t, err)
+
+	err = storage.SaveFeedCache(bytes.NewBufferString(createRSS(items)), "https://example.com/feed")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	config, err := storage.LoadConfig()
+	assert.NoError(t, err)
+	config.OpenCommand = script
+	err = storage.SaveConfig()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	in.WriteString("n\n")
+	os.Args = []string{"cleed", "-C", "--open"}
+	err = root.Cmd.Execute()
+	assert.NoError(t, err)
+	assert.Contains(t, out.String(), "open 11 items? [y/N] ")
+	assert.NotContains(t, out.String(), "opened")
+	_, err = os.Stat(opened)
+	assert.True(t, os.IsNotExist(err))
+
+	root, err = NewRoot("0.1.0", timeMock, printer, storage, feed)
+	assert.NoError(t, err)
+
+	out.Reset()
+	in.WriteString("y\n")
+	os.Args = []string{"cleed", "-C", "--open"}
+	err = root.Cmd.Execute()
+	assert.NoError(t, err)
+	assert.Equal(t, 11, strings.Count(out.String(), "opened "))
+	assert.Len(t, readOpened(t, opened, 11), 11)
+}
+
+// readOpened waits until the open command, which runs in the background,
+// recorded n links and returns them sorted.
+func readOpened(t *testing.T, opened string, n int) []string {
+	var links []string
+	assert.Eventually(t, func() bool {
+		b, err := os.ReadFile(opened)
+		if err != nil {
+			return false
+		}
+		links = strings.Split(strings.TrimSuffix(string(b), "\n"), "\n")
+		return len(links) >= n
+	}, 5*time.Second, 10*time.Millisecond)
+	slices.Sort(links)
+	return links
+}
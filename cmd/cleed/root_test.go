@@ -9,6 +9,7 @@ import (
 	"net/url"
 	"os"
 	"path"
+	"strings"
 	"testing"
 	"time"
 
@@ -100,7 +101,7 @@ RSS Feed        • Item 1
 	if err != nil {
 		t.Fatal(err)
 	}
-	assert.Len(t, files, 3)
+	assert.Len(t, files, 11)
 
 	cacheInfo, err := storage.LoadCacheInfo()
 	assert.NoError(t, err)
@@ -366,18 +367,220 @@ func Test_Feed_Search(t *testing.T) {
 
 	err = root.Cmd.Execute()
 	assert.NoError(t, err)
-	assert.Equal(t, `RSS Feed        • zzzz
+	assert.Equal(t, `RSS Feed        • Keyword 1
 15 minutes ago  https://rss-feed.com/item-1/
 
-RSS Feed        • keywords
+`, out.String())
+
+	out.Reset()
+	os.Args = []string{"cleed", "--search", "keyword* -category:keywordzz", "-C"}
+
+	err = root.Cmd.Execute()
+	assert.NoError(t, err)
+	assert.Equal(t, `RSS Feed        Keyword 1
+15 minutes ago  https://rss-feed.com/item-1/
+
+RSS Feed        keywordxaxa
+15 minutes ago  https://rss-feed.com/item-1/
+
+RSS Feed        keywords
+15 minutes ago  https://rss-feed.com/item-1/
+
+`, out.String())
+
+	out.Reset()
+	os.Args = []string{"cleed", "--search", "keyword", "--fuzzy"}
+
+	err = root.Cmd.Execute()
+	assert.NoError(t, err)
+	assert.Equal(t, `RSS Feed        zzzz
+15 minutes ago  https://rss-feed.com/item-1/
+
+RSS Feed        keywords
+15 minutes ago  https://rss-feed.com/item-1/
+
+RSS Feed        Keyword 1
+15 minutes ago  https://rss-feed.com/item-1/
+
+`, out.String())
+}
+
+func Test_Feed_Search_Phrase_And_Field(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	timeMock := mocks.NewMockTime(ctrl)
+	timeMock.EXPECT().Now().Return(defaultCurrentTime).AnyTimes()
+
+	out := new(bytes.Buffer)
+	printer := internal.NewPrinter(nil, out, out)
+	storage := _storage.NewLocalStorage("cleed_test", timeMock)
+	defer localStorageCleanup(t, storage)
+
+	configDir, err := os.UserConfigDir()
+	if err != nil {
+		t.Fatal(err)
+	}
+	listsDir := path.Join(configDir, "cleed_test", "lists")
+	err = os.MkdirAll(listsDir, 0700)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	rss := createRSS([]*FeedItem{
+		{
+			Title:      "A new release of Go",
+			Link:       "https://rss-feed.com/item-1/",
+			Published:  "Wed, 31 Dec 2023 23:45:00 GMT",
+			Categories: []string{"golang"},
+		},
+		{
+			Title:     "Release of the new editor",
+			Link:      "https://rss-feed.com/item-2/",
+			Published: "Wed, 31 Dec 2023 23:30:00 GMT",
+		},
+	})
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Write([]byte(rss))
+	}))
+	defer server.Close()
+
+	err = os.WriteFile(path.Join(listsDir, "default"),
+		fmt.Appendf(nil, "%d %s\n", defaultCurrentTime.Unix(), server.URL), 0600)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	feed := internal.NewTerminalFeed(timeMock, printer, storage)
+	root, err := NewRoot("0.1.0", timeMock, printer, storage, feed)
+	assert.NoError(t, err)
+
+	os.Args = []string{"cleed", "--search", `"new release"`}
+	err = root.Cmd.Execute()
+	assert.NoError(t, err)
+	assert.Equal(t, `RSS Feed        • A new release of Go
 15 minutes ago  https://rss-feed.com/item-1/
 
-RSS Feed        • Keyword 1
+`, out.String())
+
+	out.Reset()
+	os.Args = []string{"cleed", "--search", "category:golang", "-C"}
+	err = root.Cmd.Execute()
+	assert.NoError(t, err)
+	assert.Equal(t, `RSS Feed        A new release of Go
 15 minutes ago  https://rss-feed.com/item-1/
 
 `, out.String())
 }
 
+func Test_Feed_Search_Ranking(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	timeMock := mocks.NewMockTime(ctrl)
+	timeMock.EXPECT().Now().Return(defaultCurrentTime).AnyTimes()
+
+	out := new(bytes.Buffer)
+	printer := internal.NewPrinter(nil, out, out)
+	storage := _storage.NewLocalStorage("cleed_test", timeMock)
+	defer localStorageCleanup(t, storage)
+
+	configDir, err := os.UserConfigDir()
+	if err != nil {
+		t.Fatal(err)
+	}
+	listsDir := path.Join(configDir, "cleed_test", "lists")
+	err = os.MkdirAll(listsDir, 0700)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	oldRSS := createRSS([]*FeedItem{
+		{
+			Title:     "Go 1.21 released",
+			Link:      "https://rss-feed.com/go-1-21/",
+			Published: defaultCurrentTime.Add(-60 * 24 * time.Hour).Format(time.RFC1123Z),
+		},
+	})
+	newRSS := createRSS([]*FeedItem{
+		{
+			Title:     "Go 1.22 released",
+			Link:      "https://rss-feed.com/go-1-22/",
+			Published: defaultCurrentTime.Add(-24 * time.Hour).Format(time.RFC1123Z),
+		},
+		{
+			Title:     "Weekly notes",
+			Link:      "https://rss-feed.com/notes/",
+			Published: defaultCurrentTime.Add(-time.Hour).Format(time.RFC1123Z),
+		},
+	})
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path == "/old" {
+			w.Write([]byte(oldRSS))
+		} else {
+			w.Write([]byte(newRSS))
+		}
+	}))
+	defer server.Close()
+
+	err = os.WriteFile(path.Join(listsDir, "default"),
+		fmt.Appendf(nil, "%d %s\n%d %s\n",
+			defaultCurrentTime.Unix(), server.URL+"/old",
+			defaultCurrentTime.Unix(), server.URL+"/new",
+		), 0600)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	feed := internal.NewTerminalFeed(timeMock, printer, storage)
+	root, err := NewRoot("0.1.0", timeMock, printer, storage, feed)
+	assert.NoError(t, err)
+
+	// the most recent match is displayed last
+	os.Args = []string{"cleed", "--search", "go released"}
+	err = root.Cmd.Execute()
+	assert.NoError(t, err)
+	assert.Equal(t, `RSS Feed     • Go 1.21 released
+60 days ago  https://rss-feed.com/go-1-21/
+
+RSS Feed     • Go 1.22 released
+1 day ago    https://rss-feed.com/go-1-22/
+
+`, out.String())
+
+	err = feed.UpdateFeedPriority(server.URL + "/old=5")
+	assert.NoError(t, err)
+
+	out.Reset()
+	os.Args = []string{"cleed", "--search", "go released", "-C"}
+	err = root.Cmd.Execute()
+	assert.NoError(t, err)
+	assert.Equal(t, `RSS Feed     Go 1.22 released
+1 day ago    https://rss-feed.com/go-1-22/
+
+RSS Feed     Go 1.21 released
+60 days ago  https://rss-feed.com/go-1-21/
+
+`, out.String())
+
+	out.Reset()
+	os.Args = []string{"cleed", "--search", "go released", "-C", "--sort", "date"}
+	err = root.Cmd.Execute()
+	assert.NoError(t, err)
+	assert.Equal(t, `RSS Feed     Go 1.21 released
+60 days ago  https://rss-feed.com/go-1-21/
+
+RSS Feed     Go 1.22 released
+1 day ago    https://rss-feed.com/go-1-22/
+
+`, out.String())
+
+	out.Reset()
+	os.Args = []string{"cleed", "-C", "--sort", "name"}
+	err = root.Cmd.Execute()
+	assert.EqualError(t, err, "invalid sort: name. Use relevance, date, feed")
+}
+
 func Test_Feed_With_Summary(t *testing.T) {
 	ctrl := gomock.NewController(t)
 	defer ctrl.Finish()
@@ -471,7 +674,7 @@ Displayed 4 items from 2 feeds (0 cached, 2 fetched) with 4 items in 0.00s
 	if err != nil {
 		t.Fatal(err)
 	}
-	assert.Len(t, files, 3)
+	assert.Len(t, files, 11)
 
 	cacheInfo, err := storage.LoadCacheInfo()
 	assert.NoError(t, err)
@@ -563,6 +766,715 @@ Atom Feed      • Item 1
 `, out.String())
 }
 
+func Test_Feed_Multiple_Lists(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	timeMock := mocks.NewMockTime(ctrl)
+	timeMock.EXPECT().Now().Return(defaultCurrentTime).AnyTimes()
+
+	out := new(bytes.Buffer)
+	printer := internal.NewPrinter(nil, out, out)
+	storage := _storage.NewLocalStorage("cleed_test", timeMock)
+	defer localStorageCleanup(t, storage)
+
+	configDir, err := os.UserConfigDir()
+	if err != nil {
+		t.Fatal(err)
+	}
+	listsDir := path.Join(configDir, "cleed_test", "lists")
+	err = os.MkdirAll(listsDir, 0700)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	techRSS := createRSS([]*FeedItem{
+		{
+			Title:     "Tech item",
+			Link:      "https://rss-feed.com/tech/",
+			Published: defaultCurrentTime.Add(-time.Hour).Format(time.RFC1123Z),
+		},
+	})
+	sportsRSS := createRSS([]*FeedItem{
+		{
+			Title:     "Sports item",
+			Link:      "https://rss-feed.com/sports/",
+			Published: defaultCurrentTime.Add(-2 * time.Hour).Format(time.RFC1123Z),
+		},
+	})
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path == "/tech" {
+			w.Write([]byte(techRSS))
+		} else {
+			w.Write([]byte(sportsRSS))
+		}
+	}))
+	defer server.Close()
+
+	lists := map[string]string{
+		"news-tech":   server.URL + "/tech",
+		"news-sports": server.URL + "/sports",
+		"misc":        server.URL + "/tech",
+		"misc/extra":  server.URL + "/sports",
+	}
+	for list, address := range lists {
+		err = os.WriteFile(path.Join(listsDir, url.QueryEscape(list)),
+			fmt.Appendf(nil, "%d %s\n", defaultCurrentTime.Unix(), address), 0600)
+		if err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	feed := internal.NewTerminalFeed(timeMock, printer, storage)
+	root, err := NewRoot("0.1.0", timeMock, printer, storage, feed)
+	assert.NoError(t, err)
+
+	// a feed in several lists is displayed once
+	os.Args = []string{"cleed", "--list", "news-*,misc"}
+	err = root.Cmd.Execute()
+	assert.NoError(t, err)
+	assert.Equal(t, `RSS Feed     • Sports item
+2 hours ago  https://rss-feed.com/sports/  (news-sports, misc/extra)
+
+RSS Feed     • Tech item
+1 hour ago   https://rss-feed.com/tech/  (news-tech, misc)
+
+`, out.String())
+
+	root, err = NewRoot("0.1.0", timeMock, printer, storage, feed)
+	assert.NoError(t, err)
+
+	out.Reset()
+	os.Args = []string{"cleed", "--list", "news-*", "--exclude-list", "news-sports", "-C"}
+	err = root.Cmd.Execute()
+	assert.NoError(t, err)
+	assert.Equal(t, `RSS Feed    Tech item
+1 hour ago  https://rss-feed.com/tech/
+
+`, out.String())
+
+	root, err = NewRoot("0.1.0", timeMock, printer, storage, feed)
+	assert.NoError(t, err)
+
+	// a list includes its nested lists
+	out.Reset()
+	os.Args = []string{"cleed", "--list", "misc", "-C"}
+	err = root.Cmd.Execute()
+	assert.NoError(t, err)
+	assert.Equal(t, `RSS Feed     Sports item
+2 hours ago  https://rss-feed.com/sports/  (misc/extra)
+
+RSS Feed     Tech item
+1 hour ago   https://rss-feed.com/tech/  (misc)
+
+`, out.String())
+
+	root, err = NewRoot("0.1.0", timeMock, printer, storage, feed)
+	assert.NoError(t, err)
+
+	os.Args = []string{"cleed", "--exclude-list", "news-[", "-C"}
+	err = root.Cmd.Execute()
+	assert.EqualError(t, err, "invalid list pattern: news-[")
+}
+
+func Test_Feed_Dedupe(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	timeMock := mocks.NewMockTime(ctrl)
+	timeMock.EXPECT().Now().Return(defaultCurrentTime).AnyTimes()
+
+	out := new(bytes.Buffer)
+	printer := internal.NewPrinter(nil, out, out)
+	storage := _storage.NewLocalStorage("cleed_test", timeMock)
+	defer localStorageCleanup(t, storage)
+
+	configDir, err := os.UserConfigDir()
+	if err != nil {
+		t.Fatal(err)
+	}
+	listsDir := path.Join(configDir, "cleed_test", "lists")
+	err = os.MkdirAll(listsDir, 0700)
+	if err != nil {
+		t.Fatal(err)
+	}
+	err = os.WriteFile(path.Join(listsDir, "default"),
+		fmt.Appendf(nil, "%d %s\n%d %s\n%d %s\n",
+			defaultCurrentTime.Unix(), "https://example.com/blog",
+			defaultCurrentTime.Unix(), "https://example.com/aggregator",
+			defaultCurrentTime.Unix(), "https://example.com/digest",
+		), 0600)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	feeds := map[string]string{
+		"https://example.com/blog": createRSS([]*FeedItem{
+			{
+				Title:     "Go 1.24 is released with many new improvements",
+				Link:      "https://go.dev/blog/go1.24?utm_source=rss",
+				Published: defaultCurrentTime.Add(-3 * time.Hour).Format(time.RFC1123Z),
+			},
+			{
+				Title:     "Another item",
+				Link:      "https://go.dev/blog/another",
+				Published: defaultCurrentTime.Add(-4 * time.Hour).Format(time.RFC1123Z),
+				GUID:      "42",
+			},
+		}),
+		// same link
+		"https://example.com/aggregator": strings.Replace(createRSS([]*FeedItem{
+			{
+				Title:     "Go 1.24 released",
+				Link:      "http://go.dev/blog/go1.24/",
+				Published: defaultCurrentTime.Add(-2 * time.Hour).Format(time.RFC1123Z),
+			},
+			// same GUID as a link
+			{
+				Title:     "Weekly",
+				Link:      "https://aggregator.com/1",
+				Published: defaultCurrentTime.Add(-5 * time.Hour).Format(time.RFC1123Z),
+				GUID:      "https://www.go.dev/blog/another",
+			},
+		}), "RSS Feed", "Aggregator", 1),
+		// nearly the same title
+		"https://example.com/digest": strings.Replace(createRSS([]*FeedItem{
+			{
+				Title:     "Go 1.24 is released, with many new improvements!",
+				Link:      "https://digest.com/123",
+				Published: defaultCurrentTime.Add(-time.Hour).Format(time.RFC1123Z),
+			},
+			// same GUID in another feed is a different item
+			{
+				Title:     "Unrelated",
+				Link:      "https://digest.com/42",
+				Published: defaultCurrentTime.Add(-6 * time.Hour).Format(time.RFC1123Z),
+				GUID:      "42",
+			},
+		}), "RSS Feed", "Digest", 1),
+	}
+	feed := internal.NewTerminalFeed(timeMock, printer, storage)
+	root, err := NewRoot("0.1.0", timeMock, printer, storage, feed)
+	assert.NoError(t, err)
+
+	for url, content := range feeds {
+		err = storage.SaveFeedCache(bytes.NewBufferString(content), url)
+		if err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	os.Args = []string{"cleed", "-C"}
+	err = root.Cmd.Execute()
+	assert.NoError(t, err)
+	assert.Equal(t, `Digest       • Unrelated
+6 hours ago  https://digest.com/42
+
+Aggregator   • Weekly
+5 hours ago  https://aggregator.com/1
+
+RSS Feed     • Another item
+4 hours ago  https://go.dev/blog/another
+
+RSS Feed     • Go 1.24 is released with many new improvements
+3 hours ago  https://go.dev/blog/go1.24?utm_source=rss
+
+Aggregator   • Go 1.24 released
+2 hours ago  http://go.dev/blog/go1.24/
+
+Digest       • Go 1.24 is released, with many new improvements!
+1 hour ago   https://digest.com/123
+
+`, out.String())
+
+	root, err = NewRoot("0.1.0", timeMock, printer, storage, feed)
+	assert.NoError(t, err)
+
+	out.Reset()
+	os.Args = []string{"cleed", "-C", "--dedupe"}
+	err = root.Cmd.Execute()
+	assert.NoError(t, err)
+	assert.Equal(t, `Digest       • Unrelated
+6 hours ago  https://digest.com/42
+
+Aggregator   • Weekly
+5 hours ago  https://aggregator.com/1
+             also in RSS Feed
+
+RSS Feed     • Go 1.24 is released with many new improvements
+3 hours ago  https://go.dev/blog/go1.24?utm_source=rss
+             also in Aggregator, Digest
+
+`, out.String())
+}
+
+func Test_Feed_Group_By(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	timeMock := mocks.NewMockTime(ctrl)
+	timeMock.EXPECT().Now().Return(defaultCurrentTime).AnyTimes()
+
+	out := new(bytes.Buffer)
+	printer := internal.NewPrinter(nil, out, out)
+	storage := _storage.NewLocalStorage("cleed_test", timeMock)
+	defer localStorageCleanup(t, storage)
+
+	configDir, err := os.UserConfigDir()
+	if err != nil {
+		t.Fatal(err)
+	}
+	listsDir := path.Join(configDir, "cleed_test", "lists")
+	err = os.MkdirAll(listsDir, 0700)
+	if err != nil {
+		t.Fatal(err)
+	}
+	lists := map[string]string{
+		"tech": "https://example.com/a",
+		"news": "https://example.com/b",
+	}
+	for list, address := range lists {
+		err = os.WriteFile(path.Join(listsDir, list),
+			fmt.Appendf(nil, "%d %s\n", defaultCurrentTime.Unix(), address), 0600)
+		if err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	feed := internal.NewTerminalFeed(timeMock, printer, storage)
+	root, err := NewRoot("0.1.0", timeMock, printer, storage, feed)
+	assert.NoError(t, err)
+
+	err = storage.SaveFeedCache(bytes.NewBufferString(strings.Replace(createRSS([]*FeedItem{
+		{
+			Title:      "A1",
+			Link:       "https://rss-feed.com/a1/",
+			Published:  defaultCurrentTime.Add(-time.Hour).Format(time.RFC1123Z),
+			Categories: []string{"go"},
+		},
+		{
+			Title:      "A2",
+			Link:       "https://rss-feed.com/a2/",
+			Published:  defaultCurrentTime.Add(-25 * time.Hour).Format(time.RFC1123Z),
+			Categories: []string{"rust", "Go"},
+		},
+	}), "RSS Feed", "Feed A", 1)), "https://example.com/a")
+	if err != nil {
+		t.Fatal(err)
+	}
+	err = storage.SaveFeedCache(bytes.NewBufferString(strings.Replace(createRSS([]*FeedItem{
+		{
+			Title:     "B1",
+			Link:      "https://rss-feed.com/b1/",
+			Published: defaultCurrentTime.Add(-2 * time.Hour).Format(time.RFC1123Z),
+		},
+		{
+			Title:      "B2",
+			Link:       "https://rss-feed.com/b2/",
+			Published:  defaultCurrentTime.Add(-26 * time.Hour).Format(time.RFC1123Z),
+			Categories: []string{"go"},
+		},
+	}), "RSS Feed", "Feed B", 1)), "https://example.com/b")
+	if err != nil {
+		t.Fatal(err)
+	}
+	// the items of feed a were already seen
+	err = storage.SaveCacheInfo(map[string]*_storage.CacheInfoItem{
+		"https://example.com/a": {
+			URL:        "https://example.com/a",
+			LastFetch:  defaultCurrentTime,
+			FetchAfter: defaultCurrentTime,
+		},
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	os.Args = []string{"cleed", "-C", "--group-by", "feed"}
+	err = root.Cmd.Execute()
+	assert.NoError(t, err)
+	assert.Equal(t, `Feed A  2 items
+
+Feed A       A2
+1 day ago    https://rss-feed.com/a2/
+
+Feed A       A1
+1 hour ago   https://rss-feed.com/a1/
+
+Feed B  2 items, 2 new
+
+Feed B       • B2
+1 day ago    https://rss-feed.com/b2/
+
+Feed B       • B1
+2 hours ago  https://rss-feed.com/b1/
+
+`, out.String())
+
+	root, err = NewRoot("0.1.0", timeMock, printer, storage, feed)
+	assert.NoError(t, err)
+
+	out.Reset()
+	os.Args = []string{"cleed", "-C", "--group-by", "list", "--per-group", "1", "--collapse"}
+	err = root.Cmd.Execute()
+	assert.NoError(t, err)
+	assert.Equal(t, `news  2 items, 2 new, showing 1
+
+Feed B       • B1
+2 hours ago  https://rss-feed.com/b1/
+
+tech  2 items
+
+`, out.String())
+
+	root, err = NewRoot("0.1.0", timeMock, printer, storage, feed)
+	assert.NoError(t, err)
+
+	out.Reset()
+	os.Args = []string{"cleed", "-C", "--group-by", "day", "--limit", "3"}
+	err = root.Cmd.Execute()
+	assert.NoError(t, err)
+	assert.Equal(t, `2023-12-30  2 items, 1 new, showing 1
+
+Feed A       A2
+1 day ago    https://rss-feed.com/a2/
+
+2023-12-31  2 items, 1 new
+
+Feed B       • B1
+2 hours ago  https://rss-feed.com/b1/
+
+Feed A       A1
+1 hour ago   https://rss-feed.com/a1/
+
+`, out.String())
+
+	root, err = NewRoot("0.1.0", timeMock, printer, storage, feed)
+	assert.NoError(t, err)
+
+	out.Reset()
+	os.Args = []string{"cleed", "-C", "--group-by", "category"}
+	err = root.Cmd.Execute()
+	assert.NoError(t, err)
+	assert.Equal(t, `go  3 items, 1 new
+
+Feed B       • B2
+1 day ago    https://rss-feed.com/b2/
+
+Feed A       A2
+1 day ago    https://rss-feed.com/a2/
+
+Feed A       A1
+1 hour ago   https://rss-feed.com/a1/
+
+rust  1 item
+
+Feed A       A2
+1 day ago    https://rss-feed.com/a2/
+
+uncategorized  1 item, 1 new
+
+Feed B       • B1
+2 hours ago  https://rss-feed.com/b1/
+
+`, out.String())
+
+	root, err = NewRoot("0.1.0", timeMock, printer, storage, feed)
+	assert.NoError(t, err)
+
+	os.Args = []string{"cleed", "-C", "--group-by", "author"}
+	err = root.Cmd.Execute()
+	assert.EqualError(t, err, "invalid group: author. Use feed, list, day, category")
+}
+
+func Test_Feed_Layouts(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	timeMock := mocks.NewMockTime(ctrl)
+	timeMock.EXPECT().Now().Return(defaultCurrentTime).AnyTimes()
+
+	out := new(bytes.Buffer)
+	printer := internal.NewPrinter(nil, out, out)
+	storage := _storage.NewLocalStorage("cleed_test", timeMock)
+	defer localStorageCleanup(t, storage)
+
+	configDir, err := os.UserConfigDir()
+	if err != nil {
+		t.Fatal(err)
+	}
+	listsDir := path.Join(configDir, "cleed_test", "lists")
+	err = os.MkdirAll(listsDir, 0700)
+	if err != nil {
+		t.Fatal(err)
+	}
+	err = os.WriteFile(path.Join(listsDir, "default"),
+		fmt.Appendf(nil, "%d %s\n", defaultCurrentTime.Unix(), "https://example.com/feed"), 0600)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	feed := internal.NewTerminalFeed(timeMock, printer, storage)
+	root, err := NewRoot("0.1.0", timeMock, printer, storage, feed)
+	assert.NoError(t, err)
+
+	err = storage.SaveFeedCache(bytes.NewBufferString(`<rss version="2.0"><channel>
+<title>RSS Feed</title>
+<link>https://rss-feed.com/</link>
+<item>
+	<title>Item 1</title>
+	<link>https://rss-feed.com/item-1/</link>
+	<author>jane@example.com (Jane)</author>
+	<description><![CDATA[<p>The <b>first</b>
+	item.</p>]]></description>
+	<pubDate>`+defaultCurrentTime.Add(-time.Hour).Format(time.RFC1123Z)+`</pubDate>
+</item>
+<item>
+	<title>Item 2</title>
+	<link>https://rss-feed.com/item-2/</link>
+	<pubDate>`+defaultCurrentTime.Add(-2*time.Hour).Format(time.RFC1123Z)+`</pubDate>
+</item>
+</channel></rss>`), "https://example.com/feed")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	os.Args = []string{"cleed", "-C", "--layout", "compact"}
+	err = root.Cmd.Execute()
+	assert.NoError(t, err)
+	assert.Equal(t, `RSS Feed     • Item 2  2 hours ago  https://rss-feed.com/item-2/
+RSS Feed     • Item 1  1 hour ago  https://rss-feed.com/item-1/
+`, out.String())
+
+	root, err = NewRoot("0.1.0", timeMock, printer, storage, feed)
+	assert.NoError(t, err)
+
+	out.Reset()
+	os.Args = []string{"cleed", "-C", "--layout", "detailed"}
+	err = root.Cmd.Execute()
+	assert.NoError(t, err)
+	assert.Equal(t, `RSS Feed     • Item 2
+2 hours ago  https://rss-feed.com/item-2/
+
+RSS Feed     • Item 1
+1 hour ago   https://rss-feed.com/item-1/
+             by Jane
+             The first item.
+
+`, out.String())
+
+	config, err := storage.LoadConfig()
+	assert.NoError(t, err)
+	config.Templates = map[string]string{
+		"short": "{{if .New}}* {{end}}{{.Title}} ({{.Feed}}, {{.Published.Format \"2006-01-02\"}}) {{.Link}}",
+	}
+	config.Layout = "short"
+
+	root, err = NewRoot("0.1.0", timeMock, printer, storage, feed)
+	assert.NoError(t, err)
+
+	out.Reset()
+	os.Args = []string{"cleed", "-C"}
+	err = root.Cmd.Execute()
+	assert.NoError(t, err)
+	assert.Equal(t, `* Item 2 (RSS Feed, 2023-12-31) https://rss-feed.com/item-2/
+* Item 1 (RSS Feed, 2023-12-31) https://rss-feed.com/item-1/
+`, out.String())
+
+	root, err = NewRoot("0.1.0", timeMock, printer, storage, feed)
+	assert.NoError(t, err)
+
+	os.Args = []string{"cleed", "-C", "--layout", "unknown"}
+	err = root.Cmd.Execute()
+	assert.EqualError(t, err, "layout not found: unknown. Use compact, default, detailed or a template name")
+}
+
+func Test_Feed_Theme(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	timeMock := mocks.NewMockTime(ctrl)
+	timeMock.EXPECT().Now().Return(defaultCurrentTime).AnyTimes()
+
+	out := new(bytes.Buffer)
+	printer := internal.NewPrinter(nil, out, out)
+	printer.SetStyling(true)
+	printer.SetTrueColor(true)
+	storage := _storage.NewLocalStorage("cleed_test", timeMock)
+	defer localStorageCleanup(t, storage)
+
+	configDir, err := os.UserConfigDir()
+	if err != nil {
+		t.Fatal(err)
+	}
+	listsDir := path.Join(configDir, "cleed_test", "lists")
+	err = os.MkdirAll(listsDir, 0700)
+	if err != nil {
+		t.Fatal(err)
+	}
+	err = os.WriteFile(path.Join(listsDir, "default"),
+		fmt.Appendf(nil, "%d %s\n", defaultCurrentTime.Unix(), "https://example.com/feed"), 0600)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	feed := internal.NewTerminalFeed(timeMock, printer, storage)
+	root, err := NewRoot("0.1.0", timeMock, printer, storage, feed)
+	assert.NoError(t, err)
+
+	err = storage.SaveFeedCache(bytes.NewBufferString(`<rss version="2.0"><channel>
+<title>RSS Feed</title>
+<item>
+	<title>Item 1</title>
+	<link>https://rss-feed.com/item-1/</link>
+	<pubDate>`+defaultCurrentTime.Add(-time.Hour).Format(time.RFC1123Z)+`</pubDate>
+</item>
+</channel></rss>`), "https://example.com/feed")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	os.Args = []string{"cleed", "config", "--theme", "solarized"}
+	err = root.Cmd.Execute()
+	assert.NoError(t, err)
+
+	root, err = NewRoot("0.1.0", timeMock, printer, storage, feed)
+	assert.NoError(t, err)
+
+	out.Reset()
+	os.Args = []string{"cleed", "-C", "--layout", "compact"}
+	err = root.Cmd.Execute()
+	assert.NoError(t, err)
+	assert.Equal(t, "\033[38;2;38;139;210mRSS Feed  \033[0m  \033[38;2;133;153;0m• \033[0mItem 1  \033[38;2;147;161;161m1 hour ago\033[0m  \033[38;2;131;148;150mhttps://rss-feed.com/item-1/\033[0m\n", out.String())
+
+	// true colors are approximated with the 256-color palette
+	printer.SetTrueColor(false)
+	root, err = NewRoot("0.1.0", timeMock, printer, storage, feed)
+	assert.NoError(t, err)
+
+	out.Reset()
+	os.Args = []string{"cleed", "-C", "--layout", "compact"}
+	err = root.Cmd.Execute()
+	assert.NoError(t, err)
+	assert.Equal(t, "\033[38;5;32mRSS Feed  \033[0m  \033[38;5;100m• \033[0mItem 1  \033[38;5;247m1 hour ago\033[0m  \033[38;5;246mhttps://rss-feed.com/item-1/\033[0m\n", out.String())
+
+	// NO_COLOR disables colors
+	t.Setenv("NO_COLOR", "1")
+	printer = internal.NewPrinter(nil, out, out)
+	printer.SetStyling(true)
+	feed = internal.NewTerminalFeed(timeMock, printer, storage)
+	root, err = NewRoot("0.1.0", timeMock, printer, storage, feed)
+	assert.NoError(t, err)
+
+	out.Reset()
+	os.Args = []string{"cleed", "-C", "--layout", "compact"}
+	err = root.Cmd.Execute()
+	assert.NoError(t, err)
+	assert.Equal(t, "RSS Feed    • Item 1  1 hour ago  https://rss-feed.com/item-1/\n", out.String())
+}
+
+func Test_Feed_Hyperlinks(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	timeMock := mocks.NewMockTime(ctrl)
+	timeMock.EXPECT().Now().Return(defaultCurrentTime).AnyTimes()
+
+	out := new(bytes.Buffer)
+	printer := internal.NewPrinter(nil, out, out)
+	storage := _storage.NewLocalStorage("cleed_test", timeMock)
+	defer localStorageCleanup(t, storage)
+
+	configDir, err := os.UserConfigDir()
+	if err != nil {
+		t.Fatal(err)
+	}
+	listsDir := path.Join(configDir, "cleed_test", "lists")
+	err = os.MkdirAll(listsDir, 0700)
+	if err != nil {
+		t.Fatal(err)
+	}
+	err = os.WriteFile(path.Join(listsDir, "default"),
+		fmt.Appendf(nil, "%d %s\n", defaultCurrentTime.Unix(), "https://example.com/feed"), 0600)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	feed := internal.NewTerminalFeed(timeMock, printer, storage)
+	root, err := NewRoot("0.1.0", timeMock, printer, storage, feed)
+	assert.NoError(t, err)
+
+	err = storage.SaveFeedCache(bytes.NewBufferString(`<rss version="2.0"><channel>
+<title>RSS Feed</title>
+<item>
+	<title>Item 1</title>
+	<link>https://rss-feed.com/item-1/</link>
+	<pubDate>`+defaultCurrentTime.Add(-time.Hour).Format(time.RFC1123Z)+`</pubDate>
+</item>
+</channel></rss>`), "https://example.com/feed")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// hyperlinks are not printed without styling
+	printer.SetHyperlinks(true)
+	os.Args = []string{"cleed", "-C", "--hide-links"}
+	err = root.Cmd.Execute()
+	assert.NoError(t, err)
+	assert.Equal(t, `RSS Feed    • Item 1
+1 hour ago  https://rss-feed.com/item-1/
+
+`, out.String())
+
+	printer.SetStyling(true)
+	defer printer.SetStyling(false)
+
+	root, err = NewRoot("0.1.0", timeMock, printer, storage, feed)
+	assert.NoError(t, err)
+
+	out.Reset()
+	os.Args = []string{"cleed", "-C"}
+	err = root.Cmd.Execute()
+	assert.NoError(t, err)
+	assert.Contains(t, out.String(), "\033]8;;https://rss-feed.com/item-1/\033\\Item 1\033]8;;\033\\\n")
+	assert.Contains(t, out.String(), "https://rss-feed.com/item-1/\033[0m\n")
+
+	root, err = NewRoot("0.1.0", timeMock, printer, storage, feed)
+	assert.NoError(t, err)
+
+	out.Reset()
+	os.Args = []string{"cleed", "-C", "--hide-links"}
+	err = root.Cmd.Execute()
+	assert.NoError(t, err)
+	assert.Contains(t, out.String(), "\033]8;;https://rss-feed.com/item-1/\033\\Item 1\033]8;;\033\\\n")
+	assert.Equal(t, 1, strings.Count(out.String(), "https://rss-feed.com/item-1/"))
+
+	// the compact layout prints the link only when the title cannot be clicked
+	root, err = NewRoot("0.1.0", timeMock, printer, storage, feed)
+	assert.NoError(t, err)
+
+	out.Reset()
+	os.Args = []string{"cleed", "-C", "--layout", "compact"}
+	err = root.Cmd.Execute()
+	assert.NoError(t, err)
+	assert.Equal(t, 1, strings.Count(out.String(), "https://rss-feed.com/item-1/"))
+
+	// disabled in the config
+	config, err := storage.LoadConfig()
+	assert.NoError(t, err)
+	config.Hyperlinks = 2
+
+	root, err = NewRoot("0.1.0", timeMock, printer, storage, feed)
+	assert.NoError(t, err)
+
+	out.Reset()
+	os.Args = []string{"cleed", "-C", "--hide-links"}
+	err = root.Cmd.Execute()
+	assert.NoError(t, err)
+	assert.NotContains(t, out.String(), "\033]8;;")
+	assert.Contains(t, out.String(), "https://rss-feed.com/item-1/")
+}
+
 func Test_Feed_NotModified(t *testing.T) {
 	ctrl := gomock.NewController(t)
 	defer ctrl.Finish()
@@ -0,0 +1,86 @@
+package cleed
+
+import (
+	"github.com/radulucut/cleed/internal/storage"
+	"github.com/spf13/cobra"
+)
+
+func (r *Root) initSearch() {
+	cmd := &cobra.Command{
+		Use:   "search",
+		Short: "Show or manage saved searches",
+		Long: `Show or manage saved searches. A saved search can be displayed with --saved or used as a list name, alone or together with lists
+
+Examples:
+  # Show all saved searches
+  cleed search
+
+  # Save a search for items from a list published in the last 7 days
+  cleed search save releases "release OR changelog" --list tech --since 7d
+
+  # Display the items of a saved search
+  cleed --saved releases
+
+  # Display the items of a list together with the items of a saved search
+  cleed --list news,releases --limit 10
+
+  # Remove a saved search
+  cleed search remove releases
+`,
+
+		RunE: r.RunSearch,
+		Args: cobra.NoArgs,
+	}
+
+	saveCmd := &cobra.Command{
+		Use:   "save <name> <query>",
+		Short: "Save a search",
+		RunE:  r.RunSearchSave,
+		Args:  cobra.ExactArgs(2),
+	}
+	flags := saveCmd.Flags()
+	flags.StringP("list", "L", "", "lists to search in, separated by commas. Glob patterns are supported")
+	flags.String("since", "", "search items since the last run (last), a specific date (e.g. 2024-01-01 12:03:04) or duration (e.g. 1d)")
+	flags.String("sort", "", "sort items by relevance, date or feed")
+	flags.Bool("fuzzy", false, "also match terms similar to the search terms")
+	cmd.AddCommand(saveCmd)
+
+	cmd.AddCommand(&cobra.Command{
+		Use:   "remove <name>",
+		Short: "Remove a saved search",
+		RunE:  r.RunSearchRemove,
+		Args:  cobra.ExactArgs(1),
+	})
+
+	r.Cmd.AddCommand(cmd)
+}
+
+func (r *Root) RunSearch(cmd *cobra.Command, args []string) error {
+	return r.feed.ListSavedSearches()
+}
+
+func (r *Root) RunSearchSave(cmd *cobra.Command, args []string) error {
+	s := &storage.SavedSearch{
+		Query: args[1],
+		List:  cmd.Flag("list").Value.String(),
+		Since: cmd.Flag("since").Value.String(),
+		Sort:  cmd.Flag("sort").Value.String(),
+	}
+	_, err := r.parseSinceFlag(s.Since)
+	if err != nil {
+		return err
+	}
+	err = validateSort(s.Sort)
+	if err != nil {
+		return err
+	}
+	s.Fuzzy, err = cmd.Flags().GetBool("fuzzy")
+	if err != nil {
+		return err
+	}
+	return r.feed.SaveSearch(args[0], s)
+}
+
+func (r *Root) RunSearchRemove(cmd *cobra.Command, args []string) error {
+	return r.feed.RemoveSavedSearch(args[0])
+}
@@ -0,0 +1,249 @@
+package cleed
+
+import (
+	"bytes"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path"
+	"testing"
+	"time"
+
+	"github.com/radulucut/cleed/internal"
+	_storage "github.com/radulucut/cleed/internal/storage"
+	"github.com/radulucut/cleed/mocks"
+	"github.com/stretchr/testify/assert"
+	"go.uber.org/mock/gomock"
+)
+
+func Test_Search_Save(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	timeMock := mocks.NewMockTime(ctrl)
+	timeMock.EXPECT().Now().Return(defaultCurrentTime).AnyTimes()
+
+	out := new(bytes.Buffer)
+	printer := internal.NewPrinter(nil, out, out)
+	storage := _storage.NewLocalStorage("cleed_test", timeMock)
+	defer localStorageCleanup(t, storage)
+
+	feed := internal.NewTerminalFeed(timeMock, printer, storage)
+	root, err := NewRoot("0.1.0", timeMock, printer, storage, feed)
+	assert.NoError(t, err)
+
+	os.Args = []string{"cleed", "search"}
+	err = root.Cmd.Execute()
+	assert.NoError(t, err)
+	assert.Equal(t, "no saved searches\n", out.String())
+
+	out.Reset()
+	os.Args = []string{"cleed", "search", "save", "releases", "release OR changelog", "--list", "tech", "--since", "7d"}
+	err = root.Cmd.Execute()
+	assert.NoError(t, err)
+	assert.Equal(t, "search releases was saved\n", out.String())
+
+	config, err := storage.LoadConfig()
+	assert.NoError(t, err)
+	assert.Equal(t, map[string]*_storage.SavedSearch{
+		"releases": {
+			Query: "release OR changelog",
+			List:  "tech",
+			Since: "7d",
+		},
+	}, config.SavedSearches)
+
+	out.Reset()
+	os.Args = []string{"cleed", "search"}
+	err = root.Cmd.Execute()
+	assert.NoError(t, err)
+	assert.Equal(t, `Name      Query                 List  Since
+releases  release OR changelog  tech  7d
+`, out.String())
+
+	os.Args = []string{"cleed", "search", "save", "empty", "  "}
+	err = root.Cmd.Execute()
+	assert.EqualError(t, err, "query is empty")
+
+	os.Args = []string{"cleed", "search", "save", "invalid", "go", "--since", "tomorrow"}
+	err = root.Cmd.Execute()
+	assert.Error(t, err)
+
+	out.Reset()
+	os.Args = []string{"cleed", "search", "remove", "releases"}
+	err = root.Cmd.Execute()
+	assert.NoError(t, err)
+	assert.Equal(t, "search releases was removed\n", out.String())
+	assert.Empty(t, config.SavedSearches)
+
+	os.Args = []string{"cleed", "search", "remove", "releases"}
+	err = root.Cmd.Execute()
+	assert.EqualError(t, err, "saved search not found: releases")
+
+	// the names of the subcommands can be used in queries and names
+	root, err = NewRoot("0.1.0", timeMock, printer, storage, feed)
+	assert.NoError(t, err)
+
+	out.Reset()
+	os.Args = []string{"cleed", "search", "save", "remove", "save OR remove"}
+	err = root.Cmd.Execute()
+	assert.NoError(t, err)
+	assert.Equal(t, "search remove was saved\n", out.String())
+
+	out.Reset()
+	os.Args = []string{"cleed", "search", "remove", "remove"}
+	err = root.Cmd.Execute()
+	assert.NoError(t, err)
+	assert.Equal(t, "search remove was removed\n", out.String())
+}
+
+func Test_Feed_Saved_Search(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	timeMock := mocks.NewMockTime(ctrl)
+	timeMock.EXPECT().Now().Return(defaultCurrentTime).AnyTimes()
+
+	out := new(bytes.Buffer)
+	printer := internal.NewPrinter(nil, out, out)
+	storage := _storage.NewLocalStorage("cleed_test", timeMock)
+	defer localStorageCleanup(t, storage)
+
+	configDir, err := os.UserConfigDir()
+	if err != nil {
+		t.Fatal(err)
+	}
+	listsDir := path.Join(configDir, "cleed_test", "lists")
+	err = os.MkdirAll(listsDir, 0700)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	techRSS := createRSS([]*FeedItem{
+		{
+			Title:     "Go 1.22 released",
+			Link:      "https://rss-feed.com/go-1-22/",
+			Published: defaultCurrentTime.Add(-24 * time.Hour).Format(time.RFC1123Z),
+		},
+		{
+			Title:     "Go 1.21 released",
+			Link:      "https://rss-feed.com/go-1-21/",
+			Published: defaultCurrentTime.Add(-30 * 24 * time.Hour).Format(time.RFC1123Z),
+		},
+	})
+	newsRSS := createRSS([]*FeedItem{
+		{
+			Title:     "Album released",
+			Link:      "https://rss-feed.com/album/",
+			Published: defaultCurrentTime.Add(-time.Hour).Format(time.RFC1123Z),
+		},
+	})
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path == "/tech" {
+			w.Write([]byte(techRSS))
+		} else {
+			w.Write([]byte(newsRSS))
+		}
+	}))
+	defer server.Close()
+
+	err = os.WriteFile(path.Join(listsDir, "tech"),
+		fmt.Appendf(nil, "%d %s\n", defaultCurrentTime.Unix(), server.URL+"/tech"), 0600)
+	if err != nil {
+		t.Fatal(err)
+	}
+	err = os.WriteFile(path.Join(listsDir, "news"),
+		fmt.Appendf(nil, "%d %s\n", defaultCurrentTime.Unix(), server.URL+"/news"), 0600)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	feed := internal.NewTerminalFeed(timeMock, printer, storage)
+	root, err := NewRoot("0.1.0", timeMock, printer, storage, feed)
+	assert.NoError(t, err)
+
+	err = feed.SaveSearch("releases", &_storage.SavedSearch{
+		Query: "released",
+		List:  "tech",
+		Since: "7d",
+	})
+	assert.NoError(t, err)
+
+	out.Reset()
+	os.Args = []string{"cleed", "--saved", "releases"}
+	err = root.Cmd.Execute()
+	assert.NoError(t, err)
+	assert.Equal(t, `RSS Feed   • Go 1.22 released
+1 day ago  https://rss-feed.com/go-1-22/
+
+`, out.String())
+
+	// a saved search can be used in place of a list
+	root, err = NewRoot("0.1.0", timeMock, printer, storage, feed)
+	assert.NoError(t, err)
+
+	out.Reset()
+	os.Args = []string{"cleed", "--list", "releases", "--since", "60d", "-C"}
+	err = root.Cmd.Execute()
+	assert.NoError(t, err)
+	assert.Equal(t, `RSS Feed     Go 1.21 released
+30 days ago  https://rss-feed.com/go-1-21/
+
+RSS Feed     Go 1.22 released
+1 day ago    https://rss-feed.com/go-1-22/
+
+`, out.String())
+
+	root, err = NewRoot("0.1.0", timeMock, printer, storage, feed)
+	assert.NoError(t, err)
+
+	os.Args = []string{"cleed", "--saved", "missing"}
+	err = root.Cmd.Execute()
+	assert.EqualError(t, err, "saved search not found: missing")
+
+	// a saved search can be combined with lists
+	root, err = NewRoot("0.1.0", timeMock, printer, storage, feed)
+	assert.NoError(t, err)
+
+	out.Reset()
+	os.Args = []string{"cleed", "--list", "news,releases"}
+	err = root.Cmd.Execute()
+	assert.NoError(t, err)
+	assert.Equal(t, `RSS Feed    Go 1.22 released
+1 day ago   https://rss-feed.com/go-1-22/  (tech, releases)
+
+RSS Feed    • Album released
+1 hour ago  https://rss-feed.com/album/  (news)
+
+`, out.String())
+
+	// --search narrows the items of the lists and saved searches
+	root, err = NewRoot("0.1.0", timeMock, printer, storage, feed)
+	assert.NoError(t, err)
+
+	out.Reset()
+	os.Args = []string{"cleed", "--list", "news,releases", "--search", "go", "-C"}
+	err = root.Cmd.Execute()
+	assert.NoError(t, err)
+	assert.Equal(t, `RSS Feed   Go 1.22 released
+1 day ago  https://rss-feed.com/go-1-22/  (tech, releases)
+
+`, out.String())
+
+	root, err = NewRoot("0.1.0", timeMock, printer, storage, feed)
+	assert.NoError(t, err)
+
+	out.Reset()
+	os.Args = []string{"cleed", "--saved", "releases", "--search", "album", "-C"}
+	err = root.Cmd.Execute()
+	assert.NoError(t, err)
+	assert.Equal(t, "no items to display\n", out.String())
+
+	root, err = NewRoot("0.1.0", timeMock, printer, storage, feed)
+	assert.NoError(t, err)
+
+	os.Args = []string{"cleed", "-C", "--exclude-list", "releases"}
+	err = root.Cmd.Execute()
+	assert.EqualError(t, err, "releases is a saved search and cannot be excluded")
+}
@@ -0,0 +1,43 @@
+package cleed
+
+import (
+	"github.com/spf13/cobra"
+)
+
+func (r *Root) initUndo() {
+	cmd := &cobra.Command{
+		Use:   "undo",
+		Short: "Undo the last change to the lists",
+		Long: `Undo the last change to the lists
+
+Removing, renaming, merging and unfollowing, as well as moving and copying
+feeds between lists, can be undone. The lists are restored as they were before
+the change. A removed list that was created again is merged with the restored
+one. The caches of removed feeds, including their archive and read state, are
+kept while the change can be undone (the last 50 changes, up to 30 days).
+
+Examples:
+  # Undo the last change
+  cleed undo
+
+  # Show what would be restored
+  cleed undo --dry-run
+`,
+
+		RunE: r.RunUndo,
+		Args: cobra.NoArgs,
+	}
+
+	flags := cmd.Flags()
+	flags.Bool("dry-run", false, "show what would be restored without changing anything")
+
+	r.Cmd.AddCommand(cmd)
+}
+
+func (r *Root) RunUndo(cmd *cobra.Command, args []string) error {
+	dryRun, err := cmd.Flags().GetBool("dry-run")
+	if err != nil {
+		return err
+	}
+	return r.feed.Undo(dryRun)
+}
@@ -0,0 +1,279 @@
+package cleed
+
+import (
+	"bytes"
+	"fmt"
+	"os"
+	"path"
+	"testing"
+	"time"
+
+	"github.com/mmcdole/gofeed"
+	"github.com/radulucut/cleed/internal"
+	_storage "github.com/radulucut/cleed/internal/storage"
+	"github.com/radulucut/cleed/mocks"
+	"github.com/stretchr/testify/assert"
+	"go.uber.org/mock/gomock"
+)
+
+func Test_Undo(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	timeMock := mocks.NewMockTime(ctrl)
+	timeMock.EXPECT().Now().Return(defaultCurrentTime).AnyTimes()
+
+	out := new(bytes.Buffer)
+	printer := internal.NewPrinter(nil, out, out)
+	storage := _storage.NewLocalStorage("cleed_test", timeMock)
+	defer localStorageCleanup(t, storage)
+
+	configDir, err := os.UserConfigDir()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	listsDir := path.Join(configDir, "cleed_test", "lists")
+	err = os.MkdirAll(listsDir, 0700)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	err = os.WriteFile(path.Join(listsDir, "a"),
+		[]byte(fmt.Sprintf("%d %s\n%d %s\n",
+			defaultCurrentTime.Unix(), "https://example.com",
+			defaultCurrentTime.Unix()+300, "https://test.com",
+		),
+		), 0600)
+	if err != nil {
+		t.Fatal(err)
+	}
+	err = os.WriteFile(path.Join(listsDir, "b"),
+		[]byte(fmt.Sprintf("%d %s\n", defaultCurrentTime.Unix()-300, "https://other.com")), 0600)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	feed := internal.NewTerminalFeed(timeMock, printer, storage)
+
+	root, err := NewRoot("0.1.0", timeMock, printer, storage, feed)
+	assert.NoError(t, err)
+
+	os.Args = []string{"cleed", "undo"}
+	err = root.Cmd.Execute()
+	assert.NoError(t, err)
+	assert.Equal(t, "nothing to undo\n", out.String())
+
+	out.Reset()
+	os.Args = []string{"cleed", "list", "b", "--remove", "--dry-run"}
+	err = root.Cmd.Execute()
+	assert.NoError(t, err)
+	assert.Equal(t, "list b would be removed with 1 feed\n", out.String())
+
+	out.Reset()
+	os.Args = []string{"cleed", "list", "a", "--merge", "b", "--dry-run"}
+	err = root.Cmd.Execute()
+	assert.NoError(t, err)
+	assert.Equal(t, "list a would be merged with b (1 feed added). b would be removed\n", out.String())
+
+	out.Reset()
+	os.Args = []string{"cleed", "unfollow", "https://example.com", "https://missing.com", "--list", "a", "--dry-run"}
+	err = root.Cmd.Execute()
+	assert.NoError(t, err)
+	assert.Equal(t, `https://example.com would be removed from the list
+https://missing.com was not found in the list
+`, out.String())
+
+	lists, err := storage.LoadLists()
+	assert.NoError(t, err)
+	assert.Equal(t, []string{"a", "b"}, lists)
+
+	// the archive and read state of removed feeds are restored by undo
+	for _, url := range []string{"https://other.com", "https://example.com"} {
+		_, err = storage.AddToArchive([]*gofeed.Item{{Title: "Item", Link: url + "/item"}}, url, &_storage.ArchiveRetention{})
+		if err != nil {
+			t.Fatal(err)
+		}
+		err = storage.MarkRead([]string{url + "/item"}, url)
+		if err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	root, err = NewRoot("0.1.0", timeMock, printer, storage, feed)
+	assert.NoError(t, err)
+
+	os.Args = []string{"cleed", "list", "b", "--remove"}
+	err = root.Cmd.Execute()
+	assert.NoError(t, err)
+
+	os.Args = []string{"cleed", "unfollow", "https://example.com", "--list", "a"}
+	err = root.Cmd.Execute()
+	assert.NoError(t, err)
+
+	out.Reset()
+	os.Args = []string{"cleed", "undo", "--dry-run"}
+	err = root.Cmd.Execute()
+	assert.NoError(t, err)
+	assert.Equal(t, `would undo: unfollow 1 feed from a (2024-01-01 00:00:00)
+  list a would be restored with 2 feeds
+`, out.String())
+
+	root, err = NewRoot("0.1.0", timeMock, printer, storage, feed)
+	assert.NoError(t, err)
+
+	out.Reset()
+	os.Args = []string{"cleed", "undo"}
+	err = root.Cmd.Execute()
+	assert.NoError(t, err)
+	err = root.Cmd.Execute()
+	assert.NoError(t, err)
+	assert.Equal(t, "undone: unfollow 1 feed from a\nundone: remove list b\n", out.String())
+
+	items, err := storage.GetFeedsFromList("a")
+	assert.NoError(t, err)
+	assert.Equal(t, []*_storage.ListItem{
+		{AddedAt: time.Unix(defaultCurrentTime.Unix(), 0), Address: "https://example.com"},
+		{AddedAt: time.Unix(defaultCurrentTime.Unix()+300, 0), Address: "https://test.com"},
+	}, items)
+
+	items, err = storage.GetFeedsFromList("b")
+	assert.NoError(t, err)
+	assert.Equal(t, []*_storage.ListItem{
+		{AddedAt: time.Unix(defaultCurrentTime.Unix()-300, 0), Address: "https://other.com"},
+	}, items)
+
+	for _, url := range []string{"https://other.com", "https://example.com"} {
+		archive, err := storage.LoadArchive(url)
+		assert.NoError(t, err)
+		assert.Len(t, archive, 1)
+		state, err := storage.LoadReadState(url)
+		assert.NoError(t, err)
+		assert.Contains(t, state, url+"/item")
+	}
+}
+
+func Test_Undo_Rename_Transfer(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	now := defaultCurrentTime
+	timeMock := mocks.NewMockTime(ctrl)
+	timeMock.EXPECT().Now().DoAndReturn(func() time.Time { return now }).AnyTimes()
+
+	out := new(bytes.Buffer)
+	printer := internal.NewPrinter(nil, out, out)
+	storage := _storage.NewLocalStorage("cleed_test", timeMock)
+	defer localStorageCleanup(t, storage)
+
+	configDir, err := os.UserConfigDir()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	listsDir := path.Join(configDir, "cleed_test", "lists")
+	err = os.MkdirAll(listsDir, 0700)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	err = os.WriteFile(path.Join(listsDir, "a"),
+		[]byte(fmt.Sprintf("%d %s\n%d %s\n",
+			defaultCurrentTime.Unix(), "https://example.com",
+			defaultCurrentTime.Unix()+300, "https://test.com",
+		),
+		), 0600)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	feed := internal.NewTerminalFeed(timeMock, printer, storage)
+
+	root, err := NewRoot("0.1.0", timeMock, printer, storage, feed)
+	assert.NoError(t, err)
+
+	os.Args = []string{"cleed", "list", "a", "--rename", "b"}
+	err = root.Cmd.Execute()
+	assert.NoError(t, err)
+
+	root, err = NewRoot("0.1.0", timeMock, printer, storage, feed)
+	assert.NoError(t, err)
+
+	out.Reset()
+	os.Args = []string{"cleed", "undo"}
+	err = root.Cmd.Execute()
+	assert.NoError(t, err)
+	assert.Equal(t, "undone: rename list a to b\n", out.String())
+
+	lists, err := storage.LoadLists()
+	assert.NoError(t, err)
+	assert.Equal(t, []string{"a"}, lists)
+
+	err = storage.MoveFeeds([]string{"https://test.com"}, "a", "c")
+	assert.NoError(t, err)
+
+	out.Reset()
+	err = root.Cmd.Execute()
+	assert.NoError(t, err)
+	assert.Equal(t, "undone: move 1 feed from a to c\n", out.String())
+
+	lists, err = storage.LoadLists()
+	assert.NoError(t, err)
+	assert.Equal(t, []string{"a"}, lists)
+	items, err := storage.GetFeedsFromList("a")
+	assert.NoError(t, err)
+	assert.Len(t, items, 2)
+
+	// a removed list that was created again is merged with the restored one
+	err = storage.RemoveList("a")
+	assert.NoError(t, err)
+	err = storage.AddToList([]string{"https://new.com"}, "a")
+	assert.NoError(t, err)
+
+	out.Reset()
+	os.Args = []string{"cleed", "undo", "--dry-run"}
+	err = root.Cmd.Execute()
+	assert.NoError(t, err)
+	assert.Equal(t, `would undo: remove list a (2024-01-01 00:00:00)
+  list a would be merged with 2 feeds
+`, out.String())
+
+	root, err = NewRoot("0.1.0", timeMock, printer, storage, feed)
+	assert.NoError(t, err)
+
+	os.Args = []string{"cleed", "undo"}
+	err = root.Cmd.Execute()
+	assert.NoError(t, err)
+
+	items, err = storage.GetFeedsFromList("a")
+	assert.NoError(t, err)
+	assert.Equal(t, []*_storage.ListItem{
+		{AddedAt: time.Unix(defaultCurrentTime.Unix(), 0), Address: "https://example.com"},
+		{AddedAt: time.Unix(defaultCurrentTime.Unix(), 0), Address: "https://new.com"},
+		{AddedAt: time.Unix(defaultCurrentTime.Unix()+300, 0), Address: "https://test.com"},
+	}, items)
+
+	// the caches of removed feeds are kept only while the change can be undone
+	_, err = storage.AddToArchive([]*gofeed.Item{{Title: "Item", Link: "https://new.com/item"}}, "https://new.com", &_storage.ArchiveRetention{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	_, err = storage.RemoveFromList([]string{"https://new.com"}, "a")
+	assert.NoError(t, err)
+
+	err = storage.Init("0.1.0")
+	assert.NoError(t, err)
+	archive, err := storage.LoadArchive("https://new.com")
+	assert.NoError(t, err)
+	assert.Len(t, archive, 1)
+
+	now = now.Add(31 * 24 * time.Hour)
+	err = storage.Init("0.1.0")
+	assert.NoError(t, err)
+	archive, err = storage.LoadArchive("https://new.com")
+	assert.NoError(t, err)
+	assert.Empty(t, archive)
+	journal, err := storage.LoadJournal()
+	assert.NoError(t, err)
+	assert.Empty(t, journal)
+}
@@ -14,6 +14,9 @@ Examples:
 
   # Remove multiple feeds from a list
   cleed unfollow https://example.com/feed.xml https://example2.com/feed --list mylist
+
+  # Show which feeds would be removed
+  cleed unfollow https://example.com/feed.xml --dry-run
 `,
 
 		RunE: r.RunUnfollow,
@@ -22,6 +25,7 @@ Examples:
 
 	flags := cmd.Flags()
 	flags.StringP("list", "L", "default", "the list to remove the feed from")
+	flags.Bool("dry-run", false, "show which feeds would be removed without removing them")
 
 	r.Cmd.AddCommand(cmd)
 }
@@ -31,5 +35,12 @@ func (r *Root) RunUnfollow(cmd *cobra.Command, args []string) error {
 	if err != nil {
 		return err
 	}
+	dryRun, err := cmd.Flags().GetBool("dry-run")
+	if err != nil {
+		return err
+	}
+	if dryRun {
+		return r.feed.PreviewUnfollow(args, list)
+	}
 	return r.feed.Unfollow(args, list)
 }
@@ -252,6 +252,10 @@ func Test_Unfollow_Clean_Cache(t *testing.T) {
 https://test.com was removed from the list
 `, out.String())
 
+	// the caches are kept until the change can no longer be undone
+	assert.FileExists(t, path.Join(cacheDir, "feed_"+url.QueryEscape("https://example.com")))
+	expireJournal(t, storage)
+
 	files, err := os.ReadDir(cacheDir)
 	if err != nil {
 		t.Fatal(err)
@@ -18,11 +18,28 @@ func localStorageCleanup(t *testing.T, storage *storage.LocalStorage) {
 	}
 }
 
+// expireJournal adds changes to the journal until the existing entries
+// expire and can no longer be undone.
+func expireJournal(t *testing.T, storage *storage.LocalStorage) {
+	// the journal keeps the last 50 changes
+	for range 50 {
+		err := storage.AddToList([]string{"https://journal.com"}, "journal")
+		if err != nil {
+			t.Fatal(err)
+		}
+		_, err = storage.RemoveFromList([]string{"https://journal.com"}, "journal")
+		if err != nil {
+			t.Fatal(err)
+		}
+	}
+}
+
 type FeedItem struct {
 	Title      string
 	Link       string
 	Published  string
 	Categories []string
+	GUID       string
 }
 
 func createRSS(items []*FeedItem) string {
@@ -32,6 +49,9 @@ func createRSS(items []*FeedItem) string {
 			item.Title + "</title><link>" +
 			item.Link + "</link><pubDate>" +
 			item.Published + "</pubDate>"
+		if item.GUID != "" {
+			itemsStr += "<guid>" + item.GUID + "</guid>"
+		}
 		for i := range item.Categories {
 			itemsStr += "<category>" + item.Categories[i] + "</category>"
 		}
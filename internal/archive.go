@@ -0,0 +1,122 @@
+package internal
+
+import (
+	"slices"
+	"strconv"
+
+	"github.com/mattn/go-runewidth"
+	"github.com/radulucut/cleed/internal/storage"
+	"github.com/radulucut/cleed/internal/utils"
+)
+
+func (f *TerminalFeed) followedFeeds() ([]string, error) {
+	lists, err := f.storage.LoadLists()
+	if err != nil {
+		return nil, err
+	}
+	feeds := make(map[string]*storage.ListItem)
+	for i := range lists {
+		err = f.storage.LoadFeedsFromList(feeds, lists[i])
+		if err != nil {
+			return nil, err
+		}
+	}
+	urls := make([]string, 0, len(feeds))
+	for url := range feeds {
+		urls = append(urls, url)
+	}
+	slices.Sort(urls)
+	return urls, nil
+}
+
+func (f *TerminalFeed) ShowArchive() error {
+	urls, err := f.followedFeeds()
+	if err != nil {
+		return utils.NewInternalError("failed to load feeds: " + err.Error())
+	}
+	cellMax := [1]int{len("URL")}
+	for i := range urls {
+		cellMax[0] = max(cellMax[0], len(urls[i]))
+	}
+	f.printer.Print(runewidth.FillRight("URL", cellMax[0]))
+	f.printer.Println("  Items  Oldest item")
+	total := 0
+	for i := range urls {
+		archive, err := f.storage.LoadArchive(urls[i])
+		if err != nil {
+			return utils.NewInternalError("failed to load archive: " + err.Error())
+		}
+		oldest := ""
+		if len(archive) > 0 {
+			t := archive[0].Date()
+			for _, item := range archive[1:] {
+				if item.Date().Before(t) {
+					t = item.Date()
+				}
+			}
+			oldest = t.Format("2006-01-02 15:04:05")
+		}
+		f.printer.Print(runewidth.FillRight(urls[i], cellMax[0]))
+		f.printer.Printf("  %s  %s\n", runewidth.FillRight(strconv.Itoa(len(archive)), 5), oldest)
+		total += len(archive)
+	}
+	f.printer.Printf("Total: %s from %s\n", utils.Pluralize(int64(total), "item"), utils.Pluralize(int64(len(urls)), "feed"))
+	return nil
+}
+
+// PruneArchive removes archived items using the given retention, falling
+// back to the configured retention when it is nil.
+func (f *TerminalFeed) PruneArchive(retention *storage.ArchiveRetention) error {
+	if retention == nil {
+		config, err := f.storage.LoadConfig()
+		if err != nil {
+			return utils.NewInternalError("failed to load config: " + err.Error())
+		}
+		retention = config.ArchiveRetention()
+	}
+	if retention.MaxAge == 0 && retention.MaxCount == 0 {
+		return utils.NewInternalError("no retention set. Use --older-than or --keep, or configure the archive retention")
+	}
+	urls, err := f.followedFeeds()
+	if err != nil {
+		return utils.NewInternalError("failed to load feeds: " + err.Error())
+	}
+	removed := 0
+	feeds := 0
+	for _, url := range urls {
+		n, err := f.storage.PruneArchive(url, retention)
+		if err != nil {
+			return utils.NewInternalError("failed to prune archive: " + err.Error())
+		}
+		if n > 0 {
+			removed += n
+			feeds++
+		}
+	}
+	f.printer.Printf("pruned %s from %s\n", utils.Pluralize(int64(removed), "item"), utils.Pluralize(int64(feeds), "feed"))
+	return nil
+}
+
+func (f *TerminalFeed) SetArchiveRetention(maxAge, maxCount *uint) error {
+	err := f.updateConfig(func(config *storage.Config) error {
+		if maxAge != nil {
+			config.ArchiveMaxAge = *maxAge
+		}
+		if maxCount != nil {
+			config.ArchiveMaxCount = *maxCount
+		}
+		return nil
+	})
+	if err != nil {
+		return err
+	}
+	f.printer.Println("archive retention was updated")
+	return nil
+}
+
+func formatArchiveLimit(v uint, unit string) string {
+	if v == 0 {
+		return "unlimited"
+	}
+	return utils.Pluralize(int64(v), unit)
+}
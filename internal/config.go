@@ -1,10 +1,14 @@
 package internal
 
 import (
+	"cmp"
 	"fmt"
+	"maps"
+	"slices"
 	"strconv"
 	"strings"
 
+	"github.com/radulucut/cleed/internal/storage"
 	"github.com/radulucut/cleed/internal/utils"
 )
 
@@ -24,6 +28,11 @@ func (f *TerminalFeed) DisplayConfig() error {
 	f.printer.Println("User-Agent:", config.UserAgent)
 	f.printer.Println("Timeout:", config.Timeout)
 	f.printer.Println("Batch size:", config.BatchSize)
+	cacheBackend := config.CacheBackend
+	if cacheBackend == "" {
+		cacheBackend = storage.CacheBackendFile
+	}
+	f.printer.Println("Cache backend:", cacheBackend)
 	styling := "default"
 	if config.Styling == 0 {
 		styling = "enabled"
@@ -31,6 +40,14 @@ func (f *TerminalFeed) DisplayConfig() error {
 		styling = "disabled"
 	}
 	f.printer.Println("Styling:", styling)
+	hyperlinks := "detect"
+	if config.Hyperlinks == 1 {
+		hyperlinks = "enabled"
+	} else if config.Hyperlinks == 2 {
+		hyperlinks = "disabled"
+	}
+	f.printer.Println("Hyperlinks:", hyperlinks)
+	f.printer.Println("Theme:", cmp.Or(config.Theme, ThemeDefault))
 	f.printer.Print("Color map:")
 	for k, v := range config.ColorMap {
 		f.printer.Printf(" %d:%d", k, v)
@@ -46,6 +63,43 @@ func (f *TerminalFeed) DisplayConfig() error {
 		futureItems = "hide"
 	}
 	f.printer.Println("Future items:", futureItems)
+	dedupe := "disabled"
+	if config.Dedupe {
+		dedupe = "enabled"
+	}
+	f.printer.Println("Dedupe:", dedupe)
+	f.printer.Println("Layout:", cmp.Or(config.Layout, LayoutDefault))
+	f.printer.Println("Templates:")
+	for _, name := range slices.Sorted(maps.Keys(config.Templates)) {
+		f.printer.Printf("  %s=%s\n", name, config.Templates[name])
+	}
+	pager := config.Pager
+	if pager == "" {
+		pager = "default"
+	} else if pager == "-" {
+		pager = "disabled"
+	}
+	f.printer.Println("Pager:", pager)
+	f.printer.Println("Open command:", cmp.Or(config.OpenCommand, "default"))
+	f.printer.Println("Download directory:", cmp.Or(config.DownloadDir, "default"))
+	f.printer.Print("Auto-download:")
+	for _, url := range slices.Sorted(maps.Keys(config.AutoDownload)) {
+		f.printer.Printf(" %s=%d", url, config.AutoDownload[url])
+	}
+	f.printer.Println()
+	f.printer.Println("Player command:", cmp.Or(config.PlayerCommand, "default"))
+	f.printer.Println("Archive max age:", formatArchiveLimit(config.ArchiveMaxAge, "day"))
+	f.printer.Println("Archive max count:", formatArchiveLimit(config.ArchiveMaxCount, "item"))
+	searchLanguage := config.SearchLanguage
+	if searchLanguage == "" {
+		searchLanguage = "none"
+	}
+	f.printer.Println("Search language:", searchLanguage)
+	f.printer.Print("Feed priority:")
+	for _, url := range slices.Sorted(maps.Keys(config.FeedPriority)) {
+		f.printer.Printf(" %s=%d", url, config.FeedPriority[url])
+	}
+	f.printer.Println()
 	if config.MinifluxToken != "" {
 		f.printer.Println("Miniflux token:", "******"+config.MinifluxToken[len(config.MinifluxToken)-6:])
 	} else {
@@ -54,139 +108,389 @@ func (f *TerminalFeed) DisplayConfig() error {
 	return nil
 }
 
-func (f *TerminalFeed) SetTimeout(timeout uint) error {
-	config, err := f.storage.LoadConfig()
-	if err != nil {
-		return utils.NewInternalError("failed to load config: " + err.Error())
+// updateConfig saves the change made by update, keeping the changes saved by
+// other processes since the config was loaded. Errors returned by update are
+// returned as they are.
+func (f *TerminalFeed) updateConfig(update func(config *storage.Config) error) error {
+	err := f.storage.UpdateConfig(update)
+	if _, ok := err.(*utils.InternalError); err != nil && !ok {
+		return utils.NewInternalError("failed to save config: " + err.Error())
 	}
-	config.Timeout = timeout
-	err = f.storage.SaveConfig()
+	return err
+}
+
+func (f *TerminalFeed) SetTimeout(timeout uint) error {
+	err := f.updateConfig(func(config *storage.Config) error {
+		config.Timeout = timeout
+		return nil
+	})
 	if err != nil {
-		return utils.NewInternalError("failed to save config: " + err.Error())
+		return err
 	}
 	f.printer.Println("timeout was updated")
 	return nil
 }
 
 func (f *TerminalFeed) SetBatchSize(batchSize uint) error {
-	config, err := f.storage.LoadConfig()
+	err := f.updateConfig(func(config *storage.Config) error {
+		config.BatchSize = batchSize
+		return nil
+	})
 	if err != nil {
-		return utils.NewInternalError("failed to load config: " + err.Error())
+		return err
 	}
-	config.BatchSize = batchSize
-	err = f.storage.SaveConfig()
+	f.printer.Println("batch size was updated")
+	return nil
+}
+
+func (f *TerminalFeed) SetCacheBackend(backend string) error {
+	err := f.storage.SetCacheBackend(backend)
 	if err != nil {
-		return utils.NewInternalError("failed to save config: " + err.Error())
+		return utils.NewInternalError("failed to set cache backend: " + err.Error())
 	}
-	f.printer.Println("batch size was updated")
+	f.printer.Println("cache backend was updated")
 	return nil
 }
 
-func (f *TerminalFeed) SetUserAgent(agent string) error {
-	config, err := f.storage.LoadConfig()
+func (f *TerminalFeed) SetSearchLanguage(language string) error {
+	err := f.updateConfig(func(config *storage.Config) error {
+		if language == "none" {
+			language = ""
+		}
+		analyzer, err := utils.NewAnalyzer(language)
+		if err != nil {
+			return utils.NewInternalError(err.Error())
+		}
+		config.SearchLanguage = analyzer.Language()
+		return nil
+	})
 	if err != nil {
-		return utils.NewInternalError("failed to load config: " + err.Error())
+		return err
 	}
-	config.UserAgent = agent
-	err = f.storage.SaveConfig()
+	f.printer.Println("search language was updated")
+	return nil
+}
+
+func (f *TerminalFeed) SetUserAgent(agent string) error {
+	err := f.updateConfig(func(config *storage.Config) error {
+		config.UserAgent = agent
+		return nil
+	})
 	if err != nil {
-		return utils.NewInternalError("failed to save config: " + err.Error())
+		return err
 	}
 	f.printer.Println("User-Agent was updated")
 	return nil
 }
 
-func (f *TerminalFeed) SetStyling(v uint8) error {
-	config, err := f.storage.LoadConfig()
+func (f *TerminalFeed) SetPager(pager string) error {
+	err := f.updateConfig(func(config *storage.Config) error {
+		config.Pager = strings.TrimSpace(pager)
+		return nil
+	})
 	if err != nil {
-		return utils.NewInternalError("failed to load config: " + err.Error())
+		return err
+	}
+	f.printer.Println("pager was updated")
+	return nil
+}
+
+func (f *TerminalFeed) SetOpenCommand(command string) error {
+	err := f.updateConfig(func(config *storage.Config) error {
+		config.OpenCommand = strings.TrimSpace(command)
+		return nil
+	})
+	if err != nil {
+		return err
+	}
+	f.printer.Println("open command was updated")
+	return nil
+}
+
+// UpdateAutoDownload sets the number of newest items of a feed to download
+// when fetching it. The rule is stored under the address the feed is followed
+// with, since it is looked up by that address.
+func (f *TerminalFeed) UpdateAutoDownload(mapping string) error {
+	i := strings.LastIndex(mapping, "=")
+	if i <= 0 {
+		return utils.NewInternalError("failed to parse auto-download rule: " + mapping)
 	}
-	if v > 2 {
-		return utils.NewInternalError("invalid value for styling")
+	url, value := mapping[:i], mapping[i+1:]
+	count := 0
+	if value != "" {
+		var err error
+		count, err = strconv.Atoi(value)
+		if err != nil || count < 0 {
+			return utils.NewInternalError("invalid number of items to download: " + value)
+		}
 	}
-	config.Styling = v
-	err = f.storage.SaveConfig()
+	if count > 0 {
+		_, address, _, err := f.loadFeedLists(url)
+		if err != nil {
+			return err
+		}
+		url = address
+	}
+	err := f.updateConfig(func(config *storage.Config) error {
+		for key := range config.AutoDownload {
+			if utils.URLKey(key) == utils.URLKey(url) {
+				delete(config.AutoDownload, key)
+			}
+		}
+		if count > 0 {
+			if config.AutoDownload == nil {
+				config.AutoDownload = make(map[string]int)
+			}
+			config.AutoDownload[url] = count
+		}
+		return nil
+	})
 	if err != nil {
-		return utils.NewInternalError("failed to save config: " + err.Error())
+		return err
 	}
-	f.printer.Println("styling was updated")
+	f.printer.Println("auto-download was updated")
 	return nil
 }
 
-func (f *TerminalFeed) SetSummary(v uint8) error {
-	config, err := f.storage.LoadConfig()
+func (f *TerminalFeed) SetDownloadDir(dir string) error {
+	err := f.updateConfig(func(config *storage.Config) error {
+		config.DownloadDir = strings.TrimSpace(dir)
+		return nil
+	})
 	if err != nil {
-		return utils.NewInternalError("failed to load config: " + err.Error())
+		return err
 	}
-	if v > 1 {
-		return utils.NewInternalError("invalid value for summary")
+	f.printer.Println("download directory was updated")
+	return nil
+}
+
+func (f *TerminalFeed) SetPlayerCommand(command string) error {
+	err := f.updateConfig(func(config *storage.Config) error {
+		config.PlayerCommand = strings.TrimSpace(command)
+		return nil
+	})
+	if err != nil {
+		return err
 	}
-	config.Summary = v
-	err = f.storage.SaveConfig()
+	f.printer.Println("player command was updated")
+	return nil
+}
+
+func (f *TerminalFeed) SetStyling(v uint8) error {
+	err := f.updateConfig(func(config *storage.Config) error {
+		if v > 2 {
+			return utils.NewInternalError("invalid value for styling")
+		}
+		config.Styling = v
+		return nil
+	})
 	if err != nil {
-		return utils.NewInternalError("failed to save config: " + err.Error())
+		return err
+	}
+	f.printer.Println("styling was updated")
+	return nil
+}
+
+func (f *TerminalFeed) SetHyperlinks(v uint8) error {
+	err := f.updateConfig(func(config *storage.Config) error {
+		if v > 2 {
+			return utils.NewInternalError("invalid value for hyperlinks")
+		}
+		config.Hyperlinks = v
+		return nil
+	})
+	if err != nil {
+		return err
+	}
+	f.printer.Println("hyperlinks were updated")
+	return nil
+}
+
+func (f *TerminalFeed) SetSummary(v uint8) error {
+	err := f.updateConfig(func(config *storage.Config) error {
+		if v > 1 {
+			return utils.NewInternalError("invalid value for summary")
+		}
+		config.Summary = v
+		return nil
+	})
+	if err != nil {
+		return err
 	}
 	f.printer.Println("summary was updated")
 	return nil
 }
 
-func (f *TerminalFeed) UpdateColorMap(mappings string) error {
-	config, err := f.storage.LoadConfig()
+func (f *TerminalFeed) SetTheme(name string) error {
+	err := f.updateConfig(func(config *storage.Config) error {
+		if !slices.Contains(Themes, name) {
+			return utils.NewInternalError("invalid theme: " + name + ". Use " + strings.Join(Themes, ", "))
+		}
+		if name == ThemeDefault {
+			name = ""
+		}
+		config.Theme = name
+		return nil
+	})
 	if err != nil {
-		return utils.NewInternalError("failed to load config: " + err.Error())
+		return err
 	}
-	if mappings == "" {
-		config.ColorMap = make(map[uint8]uint8)
-	} else {
-		colors := strings.Split(mappings, ",")
-		for i := range colors {
-			parts := strings.Split(colors[i], ":")
-			if len(parts) == 0 {
-				return utils.NewInternalError("failed to parse color mapping: " + colors[i])
-			}
-			left, err := strconv.Atoi(parts[0])
-			if err != nil {
-				return utils.NewInternalError("failed to parse color mapping: " + parts[0])
-			}
-			if len(parts) == 1 || parts[1] == "" {
-				delete(config.ColorMap, uint8(left))
-			} else {
-				right, err := strconv.Atoi(parts[1])
+	f.printer.Println("theme was updated")
+	return nil
+}
+
+func (f *TerminalFeed) UpdateColorMap(mappings string) error {
+	err := f.updateConfig(func(config *storage.Config) error {
+		if mappings == "" {
+			config.ColorMap = make(map[uint8]uint8)
+		} else {
+			colors := strings.Split(mappings, ",")
+			for i := range colors {
+				parts := strings.Split(colors[i], ":")
+				if len(parts) == 0 {
+					return utils.NewInternalError("failed to parse color mapping: " + colors[i])
+				}
+				left, err := strconv.Atoi(parts[0])
 				if err != nil {
-					return utils.NewInternalError("failed to parse color mapping: " + parts[1])
+					return utils.NewInternalError("failed to parse color mapping: " + parts[0])
+				}
+				if len(parts) == 1 || parts[1] == "" {
+					delete(config.ColorMap, uint8(left))
+				} else {
+					right, err := strconv.Atoi(parts[1])
+					if err != nil {
+						return utils.NewInternalError("failed to parse color mapping: " + parts[1])
+					}
+					config.ColorMap[uint8(left)] = uint8(right)
 				}
-				config.ColorMap[uint8(left)] = uint8(right)
 			}
 		}
-	}
-	err = f.storage.SaveConfig()
+		return nil
+	})
 	if err != nil {
-		return utils.NewInternalError("failed to save config: " + err.Error())
+		return err
 	}
 	f.printer.Println("color map updated")
 	return nil
 }
 
-func (f *TerminalFeed) UpdateFutureItems(value uint8) error {
-	config, err := f.storage.LoadConfig()
+// UpdateFeedPriority sets the priority of a feed from a url=priority
+// mapping. An empty priority removes it.
+func (f *TerminalFeed) UpdateFeedPriority(mapping string) error {
+	err := f.updateConfig(func(config *storage.Config) error {
+		i := strings.LastIndex(mapping, "=")
+		if i <= 0 {
+			return utils.NewInternalError("failed to parse feed priority: " + mapping)
+		}
+		url, value := mapping[:i], mapping[i+1:]
+		if value == "" {
+			delete(config.FeedPriority, url)
+		} else {
+			priority, err := strconv.Atoi(value)
+			if err != nil || priority < MinFeedPriority || priority > MaxFeedPriority {
+				return utils.NewInternalError(fmt.Sprintf("invalid feed priority: %s. Use a number between %d and %d", value, MinFeedPriority, MaxFeedPriority))
+			}
+			if config.FeedPriority == nil {
+				config.FeedPriority = make(map[string]int)
+			}
+			config.FeedPriority[url] = priority
+		}
+		return nil
+	})
 	if err != nil {
-		return utils.NewInternalError("failed to load config: " + err.Error())
+		return err
 	}
-	if value == 0 {
-		config.HideFutureItems = true
-	} else if value == 1 {
-		config.HideFutureItems = false
-	} else {
-		return utils.NewInternalError("invalid value for future items")
+	f.printer.Println("feed priority was updated")
+	return nil
+}
+
+func (f *TerminalFeed) SetLayout(name string) error {
+	err := f.updateConfig(func(config *storage.Config) error {
+		if _, ok := config.Templates[name]; !ok && !slices.Contains(Layouts, name) {
+			return utils.NewInternalError("layout not found: " + name + ". Use " + strings.Join(Layouts, ", ") + " or a template name")
+		}
+		config.Layout = name
+		if name == LayoutDefault {
+			config.Layout = ""
+		}
+		return nil
+	})
+	if err != nil {
+		return err
 	}
-	err = f.storage.SaveConfig()
+	f.printer.Println("layout was updated")
+	return nil
+}
+
+// UpdateTemplates adds, replaces or, with an empty template, removes an item
+// template given as name=template.
+func (f *TerminalFeed) UpdateTemplates(mapping string) error {
+	err := f.updateConfig(func(config *storage.Config) error {
+		name, text, ok := strings.Cut(mapping, "=")
+		name = strings.TrimSpace(name)
+		if !ok || name == "" {
+			return utils.NewInternalError("failed to parse template: " + mapping)
+		}
+		if slices.Contains(Layouts, name) {
+			return utils.NewInternalError("template name is reserved for a built-in layout: " + name)
+		}
+		if text == "" {
+			delete(config.Templates, name)
+			if config.Layout == name {
+				config.Layout = ""
+			}
+		} else {
+			_, err := parseItemTemplate(name, text, f.printer, configTheme(config))
+			if err != nil {
+				return utils.NewInternalError("failed to parse template: " + err.Error())
+			}
+			if config.Templates == nil {
+				config.Templates = make(map[string]string)
+			}
+			config.Templates[name] = text
+		}
+		return nil
+	})
 	if err != nil {
-		return utils.NewInternalError("failed to save config: " + err.Error())
+		return err
+	}
+	f.printer.Println("templates were updated")
+	return nil
+}
+
+func (f *TerminalFeed) UpdateFutureItems(value uint8) error {
+	err := f.updateConfig(func(config *storage.Config) error {
+		if value == 0 {
+			config.HideFutureItems = true
+		} else if value == 1 {
+			config.HideFutureItems = false
+		} else {
+			return utils.NewInternalError("invalid value for future items")
+		}
+		return nil
+	})
+	if err != nil {
+		return err
 	}
 	f.printer.Println("future items was updated")
 	return nil
 }
 
+func (f *TerminalFeed) SetDedupe(value uint8) error {
+	err := f.updateConfig(func(config *storage.Config) error {
+		if value > 1 {
+			return utils.NewInternalError("invalid value for dedupe")
+		}
+		config.Dedupe = value == 1
+		return nil
+	})
+	if err != nil {
+		return err
+	}
+	f.printer.Println("dedupe was updated")
+	return nil
+}
+
 func (f *TerminalFeed) DisplayColorRange() {
 	styling := f.printer.GetStyling()
 	f.printer.SetStyling(true)
@@ -198,14 +502,12 @@ func (f *TerminalFeed) DisplayColorRange() {
 }
 
 func (f *TerminalFeed) SetMinifluxToken(token string) error {
-	config, err := f.storage.LoadConfig()
+	err := f.updateConfig(func(config *storage.Config) error {
+		config.MinifluxToken = token
+		return nil
+	})
 	if err != nil {
-		return utils.NewInternalError("failed to load config: " + err.Error())
-	}
-	config.MinifluxToken = token
-	err = f.storage.SaveConfig()
-	if err != nil {
-		return utils.NewInternalError("failed to save config: " + err.Error())
+		return err
 	}
 	f.printer.Println("miniflux token was updated")
 	return nil
@@ -0,0 +1,172 @@
+package internal
+
+import (
+	"net/url"
+	"slices"
+	"strings"
+	"time"
+	"unicode"
+
+	"github.com/radulucut/cleed/internal/utils"
+)
+
+const (
+	// dedupeWindow is the maximum time between two items with similar
+	// titles for them to be considered the same story.
+	dedupeWindow = 72 * time.Hour
+	// dedupeMinTitleLength is the minimum length of a normalized title for
+	// it to be compared, so that short titles like "Weekly links" are not
+	// merged.
+	dedupeMinTitleLength = 20
+)
+
+// dedupeItems collapses items from different feeds that are the same story
+// into the earliest published one, which keeps the others as duplicates.
+// Items are the same when they have the same canonical link, including a GUID
+// that is a URL, or nearly the same title and were published close to each
+// other. Other GUIDs are only unique within their feed.
+func dedupeItems(items []*FeedItem) []*FeedItem {
+	parent := make([]int, len(items))
+	for i := range parent {
+		parent[i] = i
+	}
+	find := func(i int) int {
+		for parent[i] != i {
+			parent[i] = parent[parent[i]]
+			i = parent[i]
+		}
+		return i
+	}
+	union := func(a, b int) {
+		if items[a].FeedURL != items[b].FeedURL {
+			parent[find(b)] = find(a)
+		}
+	}
+	seen := make(map[string]int)
+	for i, fi := range items {
+		keys := make([]string, 0, 2)
+		if guid := strings.TrimSpace(fi.Item.GUID); isURL(guid) {
+			keys = append(keys, "link:"+utils.URLKey(guid))
+		} else if guid != "" {
+			keys = append(keys, "guid:"+fi.FeedURL+" "+guid)
+		}
+		if link := itemLinkKey(fi); link != "" {
+			keys = append(keys, "link:"+link)
+		}
+		for _, key := range keys {
+			if j, ok := seen[key]; ok {
+				union(j, i)
+			} else {
+				seen[key] = i
+			}
+		}
+	}
+	titles := make([][]rune, len(items))
+	order := make([]int, len(items))
+	for i, fi := range items {
+		titles[i] = normalizeTitle(fi.Item.Title)
+		order[i] = i
+	}
+	slices.SortFunc(order, func(a, b int) int {
+		return items[a].Item.PublishedParsed.Compare(*items[b].Item.PublishedParsed)
+	})
+	for x, i := range order {
+		if len(titles[i]) < dedupeMinTitleLength {
+			continue
+		}
+		for _, j := range order[x+1:] {
+			if items[j].Item.PublishedParsed.Sub(*items[i].Item.PublishedParsed) > dedupeWindow {
+				break
+			}
+			if find(i) != find(j) && similarTitles(titles[i], titles[j]) {
+				union(i, j)
+			}
+		}
+	}
+	groups := make(map[int][]int)
+	for i := range items {
+		root := find(i)
+		groups[root] = append(groups[root], i)
+	}
+	result := make([]*FeedItem, 0, len(groups))
+	for i, fi := range items {
+		group := groups[find(i)]
+		if len(group) == 1 {
+			result = append(result, fi)
+			continue
+		}
+		// items are collected concurrently, so sort the group to get the
+		// same result on every run
+		slices.SortFunc(group, func(a, b int) int {
+			if c := items[a].Item.PublishedParsed.Compare(*items[b].Item.PublishedParsed); c != 0 {
+				return c
+			}
+			return strings.Compare(items[a].FeedURL, items[b].FeedURL)
+		})
+		if i != group[0] {
+			continue
+		}
+		// the lists are shared by the items of a feed
+		fi.Lists = slices.Clone(fi.Lists)
+		for _, j := range group[1:] {
+			other := items[j]
+			fi.Duplicates = append(fi.Duplicates, other)
+			fi.IsNew = fi.IsNew || other.IsNew
+			fi.Score = max(fi.Score, other.Score)
+			for _, list := range other.Lists {
+				if !slices.Contains(fi.Lists, list) {
+					fi.Lists = append(fi.Lists, list)
+				}
+			}
+		}
+		result = append(result, fi)
+	}
+	return result
+}
+
+// itemLinkKey returns the key of the item link, resolving relative links.
+func itemLinkKey(fi *FeedItem) string {
+	link := strings.TrimSpace(fi.Item.Link)
+	if link == "" {
+		return ""
+	}
+	if strings.HasPrefix(link, "/") {
+		link = strings.TrimSuffix(fi.Feed.Link, "/") + link
+	}
+	return utils.URLKey(link)
+}
+
+// isURL reports whether s is an absolute http(s) URL. gofeed does not keep the
+// isPermaLink attribute of a GUID, but a permalink is always a URL.
+func isURL(s string) bool {
+	u, err := url.Parse(s)
+	return err == nil && (u.Scheme == "http" || u.Scheme == "https") && u.Host != ""
+}
+
+// normalizeTitle lowercases the title and keeps only its words.
+func normalizeTitle(title string) []rune {
+	words := strings.FieldsFunc(strings.ToLower(title), func(r rune) bool {
+		return !unicode.IsLetter(r) && !unicode.IsNumber(r)
+	})
+	return []rune(strings.Join(words, " "))
+}
+
+// similarTitles reports whether two normalized titles differ by at most a
+// tenth of their length.
+func similarTitles(a, b []rune) bool {
+	if len(b) < dedupeMinTitleLength {
+		return false
+	}
+	limit := max(len(a), len(b)) / 10
+	if abs(len(a)-len(b)) > limit {
+		return false
+	}
+	return utils.LevenshteinDistance(a, b) <= limit
+}
+
+func abs(n int) int {
+	if n < 0 {
+		return -n
+	}
+	return n
+}
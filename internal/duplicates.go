@@ -0,0 +1,274 @@
+package internal
+
+import (
+	"bufio"
+	"io"
+	"slices"
+	"strconv"
+	"strings"
+
+	"github.com/mattn/go-runewidth"
+	"github.com/radulucut/cleed/internal/storage"
+	"github.com/radulucut/cleed/internal/utils"
+)
+
+const (
+	duplicateSameURL  = "same URL"
+	duplicateFinalURL = "same feed URL"
+	duplicateSameSite = "same site"
+	duplicateKeyURL   = "url:"
+	duplicateKeySite  = "site:"
+)
+
+var duplicateReasons = []string{duplicateSameURL, duplicateFinalURL, duplicateSameSite}
+
+// DuplicateFeed is a feed in a list that is a duplicate of other feeds.
+type DuplicateFeed struct {
+	URL  string
+	List string
+}
+
+type DuplicateGroup struct {
+	Feeds   []*DuplicateFeed
+	Reasons []string
+}
+
+// ShowDuplicateFeeds reports the feeds that are followed more than once across
+// all lists: the same URL written differently, feeds whose cached feed
+// declares the URL of another feed as its own and feeds of the same site.
+// When interactive is set, it asks which feeds of every group to keep and
+// removes the others from their lists.
+func (f *TerminalFeed) ShowDuplicateFeeds(interactive bool) error {
+	if interactive && f.printer.InReader == nil {
+		return utils.NewInternalError("interactive mode requires an input")
+	}
+	groups, err := f.duplicateFeeds()
+	if err != nil {
+		return err
+	}
+	if len(groups) == 0 {
+		f.printer.Println("no duplicate feeds found")
+		return nil
+	}
+	var reader *bufio.Reader
+	if interactive {
+		reader = bufio.NewReader(f.printer.InReader)
+	}
+	total, removed := 0, 0
+	for i, group := range groups {
+		if i > 0 {
+			f.printer.Println()
+		}
+		f.printDuplicateGroup(group)
+		total += len(group.Feeds)
+		if reader == nil {
+			continue
+		}
+		keep, ok := f.promptDuplicateSelection(reader, len(group.Feeds))
+		if !ok {
+			reader = nil
+			continue
+		}
+		if len(keep) == 0 {
+			continue
+		}
+		n, err := f.removeDuplicateFeeds(group, keep)
+		if err != nil {
+			return err
+		}
+		f.printer.Printf("removed %s\n", utils.Pluralize(int64(n), "feed"))
+		removed += n
+	}
+	f.printer.Printf("Total: %s, %s\n",
+		utils.Pluralize(int64(len(groups)), "group"),
+		utils.Pluralize(int64(total), "feed"),
+	)
+	if interactive {
+		f.printer.Printf("removed %s\n", utils.Pluralize(int64(removed), "duplicate feed"))
+	}
+	return nil
+}
+
+func (f *TerminalFeed) printDuplicateGroup(group *DuplicateGroup) {
+	f.printer.Println(strings.Join(group.Reasons, ", "))
+	width := 0
+	for _, feed := range group.Feeds {
+		width = max(width, runewidth.StringWidth(feed.URL))
+	}
+	for i, feed := range group.Feeds {
+		f.printer.Printf("  %d  %s  %s\n", i+1, runewidth.FillRight(feed.URL, width), feed.List)
+	}
+}
+
+// promptDuplicateSelection asks which feeds to keep until the answer is valid.
+// An empty answer keeps all feeds. It returns false when there is no more
+// input.
+func (f *TerminalFeed) promptDuplicateSelection(reader *bufio.Reader, count int) ([]int, bool) {
+	for {
+		f.printer.Print("keep (e.g. 1 or 1,3, empty to skip): ")
+		line, err := reader.ReadString('\n')
+		if err != nil && (err != io.EOF || line == "") {
+			f.printer.Println()
+			return nil, false
+		}
+		keep, ok := parseDuplicateSelection(strings.TrimSpace(line), count)
+		if ok {
+			return keep, true
+		}
+		f.printer.Println("invalid selection")
+	}
+}
+
+func parseDuplicateSelection(s string, count int) ([]int, bool) {
+	keep := make([]int, 0)
+	if s == "" {
+		return keep, true
+	}
+	for _, part := range strings.Split(s, ",") {
+		n, err := strconv.Atoi(strings.TrimSpace(part))
+		if err != nil || n < 1 || n > count {
+			return nil, false
+		}
+		if !slices.Contains(keep, n-1) {
+			keep = append(keep, n-1)
+		}
+	}
+	return keep, true
+}
+
+// removeDuplicateFeeds removes the feeds of the group that were not selected
+// and returns their number.
+func (f *TerminalFeed) removeDuplicateFeeds(group *DuplicateGroup, keep []int) (int, error) {
+	urlsByList := make(map[string][]string)
+	lists := make([]string, 0)
+	removed := 0
+	for i, feed := range group.Feeds {
+		if slices.Contains(keep, i) {
+			continue
+		}
+		if _, ok := urlsByList[feed.List]; !ok {
+			lists = append(lists, feed.List)
+		}
+		urlsByList[feed.List] = append(urlsByList[feed.List], feed.URL)
+		removed++
+	}
+	for _, list := range lists {
+		_, err := f.storage.RemoveFromList(urlsByList[list], list)
+		if err != nil {
+			return 0, utils.NewInternalError("failed to remove feeds: " + err.Error())
+		}
+	}
+	return removed, nil
+}
+
+// duplicateFeeds groups the followed feeds that are most likely the same.
+func (f *TerminalFeed) duplicateFeeds() ([]*DuplicateGroup, error) {
+	lists, err := f.storage.LoadLists()
+	if err != nil {
+		return nil, utils.NewInternalError("failed to load lists: " + err.Error())
+	}
+	slices.SortFunc(lists, storage.CompareLists)
+	urls := make([]string, 0)
+	feedLists := make(map[string][]string)
+	for _, list := range lists {
+		feeds, err := f.storage.GetFeedsFromList(list)
+		if err != nil {
+			return nil, utils.NewInternalError("failed to list feeds: " + err.Error())
+		}
+		for _, item := range feeds {
+			if _, ok := feedLists[item.Address]; !ok {
+				urls = append(urls, item.Address)
+			}
+			feedLists[item.Address] = append(feedLists[item.Address], list)
+		}
+	}
+	slices.Sort(urls)
+	parent := make(map[string]string, len(urls))
+	reasons := make(map[string][]string)
+	find := func(url string) string {
+		for parent[url] != url {
+			parent[url] = parent[parent[url]]
+			url = parent[url]
+		}
+		return url
+	}
+	union := func(a, b, reason string) {
+		ra, rb := find(a), find(b)
+		if ra != rb {
+			parent[rb] = ra
+			for _, r := range reasons[rb] {
+				if !slices.Contains(reasons[ra], r) {
+					reasons[ra] = append(reasons[ra], r)
+				}
+			}
+			delete(reasons, rb)
+		}
+		if !slices.Contains(reasons[ra], reason) {
+			reasons[ra] = append(reasons[ra], reason)
+		}
+	}
+	type owner struct {
+		url     string
+		address bool // the key comes from the followed URL
+	}
+	owners := make(map[string]owner)
+	addKey := func(url, key string, address bool) {
+		o, ok := owners[key]
+		if !ok {
+			owners[key] = owner{url: url, address: address}
+			return
+		}
+		if o.url == url {
+			return
+		}
+		reason := duplicateSameURL
+		if strings.HasPrefix(key, duplicateKeySite) {
+			reason = duplicateSameSite
+		} else if !address || !o.address {
+			reason = duplicateFinalURL
+		}
+		union(o.url, url, reason)
+	}
+	for _, url := range urls {
+		parent[url] = url
+		if len(feedLists[url]) > 1 {
+			reasons[url] = append(reasons[url], duplicateSameURL)
+		}
+	}
+	for _, url := range urls {
+		addKey(url, duplicateKeyURL+utils.URLKey(url), true)
+	}
+	for _, url := range urls {
+		feed, err := f.parseFeed(url)
+		if err != nil {
+			continue
+		}
+		if feed.FeedLink != "" {
+			addKey(url, duplicateKeyURL+utils.URLKey(feed.FeedLink), false)
+		}
+		if feed.Link != "" {
+			addKey(url, duplicateKeySite+utils.URLKey(feed.Link), false)
+		}
+	}
+	groups := make([]*DuplicateGroup, 0)
+	byRoot := make(map[string]*DuplicateGroup)
+	for _, url := range urls {
+		root := find(url)
+		if len(reasons[root]) == 0 {
+			continue
+		}
+		group, ok := byRoot[root]
+		if !ok {
+			slices.SortFunc(reasons[root], func(a, b string) int {
+				return slices.Index(duplicateReasons, a) - slices.Index(duplicateReasons, b)
+			})
+			group = &DuplicateGroup{Reasons: reasons[root]}
+			byRoot[root] = group
+			groups = append(groups, group)
+		}
+		for _, list := range feedLists[url] {
+			group.Feeds = append(group.Feeds, &DuplicateFeed{URL: url, List: list})
+		}
+	}
+	return groups, nil
+}
@@ -0,0 +1,428 @@
+package internal
+
+import (
+	"cmp"
+	"context"
+	"fmt"
+	"hash/fnv"
+	"io"
+	"maps"
+	"mime"
+	"net/http"
+	"net/url"
+	"os"
+	"path"
+	"path/filepath"
+	"slices"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+	"unicode"
+
+	"github.com/mmcdole/gofeed"
+	"github.com/radulucut/cleed/internal/storage"
+	"github.com/radulucut/cleed/internal/utils"
+)
+
+const (
+	// partSuffix is added to files while they are downloaded, so that an
+	// interrupted download can be resumed.
+	partSuffix = ".part"
+	// maxFileNameLength keeps file names within the limits of file systems.
+	maxFileNameLength = 200
+	// autoDownloadWorkers is the number of enclosures downloaded at the same
+	// time by auto-download.
+	autoDownloadWorkers = 3
+)
+
+// Enclosure is a media file attached to an item, e.g. a podcast episode.
+type Enclosure struct {
+	URL      string
+	Type     string
+	Size     int64         // in bytes, 0 if unknown
+	Duration time.Duration // 0 if unknown
+}
+
+// String returns the type, size and duration of the enclosure, e.g.
+// audio/mpeg, 24.5 MB, 45:12.
+func (e *Enclosure) String() string {
+	parts := make([]string, 0, 3)
+	if e.Type != "" {
+		parts = append(parts, e.Type)
+	}
+	if e.Size > 0 {
+		parts = append(parts, utils.FormatSize(e.Size))
+	}
+	if e.Duration > 0 {
+		parts = append(parts, utils.FormatClock(e.Duration))
+	}
+	if len(parts) == 0 {
+		return e.URL
+	}
+	return strings.Join(parts, ", ")
+}
+
+// itemEnclosures returns the enclosures of the item. The duration from the
+// iTunes extension is the one of the first enclosure.
+func itemEnclosures(item *gofeed.Item) []*Enclosure {
+	enclosures := make([]*Enclosure, 0, len(item.Enclosures))
+	for _, e := range item.Enclosures {
+		if e == nil || e.URL == "" {
+			continue
+		}
+		enclosure := &Enclosure{
+			URL:  e.URL,
+			Type: e.Type,
+		}
+		if enclosure.Type == "" {
+			if u, err := url.Parse(e.URL); err == nil {
+				enclosure.Type, _, _ = strings.Cut(mime.TypeByExtension(path.Ext(u.Path)), ";")
+			}
+		}
+		if size, err := strconv.ParseInt(e.Length, 10, 64); err == nil && size > 0 {
+			enclosure.Size = size
+		}
+		enclosures = append(enclosures, enclosure)
+	}
+	if len(enclosures) > 0 && item.ITunesExt != nil && item.ITunesExt.Duration != "" {
+		if d, err := utils.ParseClock(item.ITunesExt.Duration); err == nil {
+			enclosures[0].Duration = d
+		}
+	}
+	return enclosures
+}
+
+type DownloadOptions struct {
+	Lists   []string
+	Targets []string // item IDs or links, or indexes in the last timeline where 1 is the newest item
+	Since   time.Time
+	Dir     string // the configured directory if empty
+	Play    bool   // play the downloaded files with the configured player
+}
+
+// Download downloads the enclosures of items from the cached feeds.
+func (f *TerminalFeed) Download(opts *DownloadOptions) error {
+	config, err := f.storage.LoadConfig()
+	if err != nil {
+		return utils.NewInternalError("failed to load config: " + err.Error())
+	}
+	if len(opts.Targets) == 0 {
+		return utils.NewInternalError("please provide an item ID or index")
+	}
+	items, err := f.findItems(opts.Lists, opts.Since, opts.Targets, config)
+	if err != nil {
+		return err
+	}
+	dir, err := downloadDir(cmp.Or(opts.Dir, config.DownloadDir))
+	if err != nil {
+		return err
+	}
+	paths := make([]string, 0)
+	for _, fi := range items {
+		enclosures := itemEnclosures(fi.Item)
+		if len(enclosures) == 0 {
+			return utils.NewInternalError("item has no enclosures: " + fi.Item.Title)
+		}
+		for i, e := range enclosures {
+			p := enclosurePath(dir, fi, e, i)
+			if _, err := os.Stat(p); err == nil {
+				f.printer.Println("already downloaded", p)
+				paths = append(paths, p)
+				continue
+			}
+			size, err := f.downloadEnclosure(context.Background(), e, p, config)
+			if err != nil {
+				return utils.NewInternalError("failed to download " + e.URL + ": " + err.Error())
+			}
+			f.printer.Printf("downloaded %s (%s)\n", p, utils.FormatSize(size))
+			paths = append(paths, p)
+		}
+		err = f.storage.MarkDownloaded([]string{storage.ItemID(fi.Item)}, fi.FeedURL)
+		if err != nil {
+			return utils.NewInternalError("failed to save downloads: " + err.Error())
+		}
+	}
+	if !opts.Play {
+		return nil
+	}
+	player := playerCommand(config)
+	for _, p := range paths {
+		err := f.runCommand(player, p)
+		if err != nil {
+			return utils.NewInternalError("failed to play " + p + ": " + err.Error())
+		}
+	}
+	return nil
+}
+
+// autoDownload downloads the enclosures of the newest items of the feeds with
+// an auto-download rule. Items that were downloaded before are skipped, even
+// if their files were deleted. Progress is reported on stderr, after the
+// timeline was written.
+func (f *TerminalFeed) autoDownload(items []*FeedItem, config *storage.Config) {
+	if len(config.AutoDownload) == 0 {
+		return
+	}
+	byFeed := make(map[string][]*FeedItem)
+	for _, fi := range items {
+		for _, item := range append([]*FeedItem{fi}, fi.Duplicates...) {
+			if config.AutoDownload[item.FeedURL] > 0 && len(item.Item.Enclosures) > 0 {
+				byFeed[item.FeedURL] = append(byFeed[item.FeedURL], item)
+			}
+		}
+	}
+	if len(byFeed) == 0 {
+		return
+	}
+	pending := make([]*FeedItem, 0)
+	for _, url := range slices.Sorted(maps.Keys(byFeed)) {
+		downloads, err := f.storage.LoadDownloads(url)
+		if err != nil {
+			f.printer.ErrPrintf("failed to load downloads of %s: %v\n", url, err)
+			continue
+		}
+		feedItems := byFeed[url]
+		sortItems(feedItems, SortDate)
+		for _, fi := range feedItems[:min(len(feedItems), config.AutoDownload[url])] {
+			if _, ok := downloads[storage.ItemID(fi.Item)]; !ok {
+				pending = append(pending, fi)
+			}
+		}
+	}
+	if len(pending) == 0 {
+		return
+	}
+	dir, err := downloadDir(config.DownloadDir)
+	if err != nil {
+		f.printer.ErrPrintln(err)
+		return
+	}
+	// show the timeline instead of holding it while downloading
+	f.printer.StopPager()
+	// mu guards the printer and the storage, which is only used between
+	// downloads
+	var mu sync.Mutex
+	var wg sync.WaitGroup
+	queue := make(chan int)
+	for range min(autoDownloadWorkers, len(pending)) {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for i := range queue {
+				f.autoDownloadItem(pending[i], i+1, len(pending), dir, config, &mu)
+			}
+		}()
+	}
+	for i := range pending {
+		queue <- i
+	}
+	close(queue)
+	wg.Wait()
+}
+
+// autoDownloadItem downloads the enclosures of the item and marks it as
+// downloaded if all of them were downloaded.
+func (f *TerminalFeed) autoDownloadItem(fi *FeedItem, n, total int, dir string, config *storage.Config, mu *sync.Mutex) {
+	downloaded := true
+	for i, e := range itemEnclosures(fi.Item) {
+		p := enclosurePath(dir, fi, e, i)
+		if _, err := os.Stat(p); err == nil {
+			continue
+		}
+		mu.Lock()
+		f.printer.ErrPrintf("downloading %s (%d/%d)\n", p, n, total)
+		mu.Unlock()
+		size, err := f.downloadEnclosure(context.Background(), e, p, config)
+		mu.Lock()
+		if err != nil {
+			f.printer.ErrPrintf("failed to download %s: %v\n", e.URL, err)
+			downloaded = false
+		} else {
+			f.printer.ErrPrintf("downloaded %s (%s)\n", p, utils.FormatSize(size))
+		}
+		mu.Unlock()
+	}
+	if !downloaded {
+		return
+	}
+	mu.Lock()
+	defer mu.Unlock()
+	err := f.storage.MarkDownloaded([]string{storage.ItemID(fi.Item)}, fi.FeedURL)
+	if err != nil {
+		f.printer.ErrPrintf("failed to save downloads: %v\n", err)
+	}
+}
+
+// enclosurePath returns the path of the enclosure in a directory named after
+// the feed.
+func enclosurePath(dir string, fi *FeedItem, e *Enclosure, index int) string {
+	folder := sanitizeFileName(cmp.Or(fi.Feed.Title, fi.FeedURL))
+	return filepath.Join(dir, folder, enclosureFileName(fi.Item, e, index))
+}
+
+// downloadEnclosure downloads the enclosure to the path and returns the size
+// of the file. A partially downloaded file is resumed when the server supports
+// range requests. Downloads can take longer than the timeout for fetching
+// feeds, so the timeout only applies while no data is received.
+func (f *TerminalFeed) downloadEnclosure(ctx context.Context, e *Enclosure, p string, config *storage.Config) (size int64, err error) {
+	ctx, cancel := context.WithCancelCause(ctx)
+	defer cancel(nil)
+	var timer *time.Timer
+	timeout := time.Duration(config.Timeout) * time.Second
+	if timeout > 0 {
+		timer = time.AfterFunc(timeout, func() {
+			cancel(fmt.Errorf("no data received for %s", timeout))
+		})
+		defer timer.Stop()
+	}
+	defer func() {
+		if err != nil && context.Cause(ctx) != nil {
+			err = context.Cause(ctx)
+		}
+	}()
+	err = os.MkdirAll(filepath.Dir(p), 0700)
+	if err != nil {
+		return 0, err
+	}
+	part := p + partSuffix
+	var offset int64
+	if info, err := os.Stat(part); err == nil {
+		offset = info.Size()
+	}
+	req, err := http.NewRequestWithContext(ctx, "GET", e.URL, nil)
+	if err != nil {
+		return 0, err
+	}
+	if config.UserAgent != "-" {
+		req.Header.Set("User-Agent", config.UserAgent)
+	}
+	if offset > 0 {
+		req.Header.Set("Range", fmt.Sprintf("bytes=%d-", offset))
+	}
+	client := &http.Client{Transport: f.http.Transport}
+	res, err := client.Do(req)
+	if err != nil {
+		return 0, err
+	}
+	defer res.Body.Close()
+	flags := os.O_CREATE | os.O_WRONLY
+	switch {
+	case res.StatusCode == http.StatusPartialContent && offset > 0:
+		if !strings.HasPrefix(res.Header.Get("Content-Range"), fmt.Sprintf("bytes %d-", offset)) {
+			return 0, fmt.Errorf("unexpected content range: %s", res.Header.Get("Content-Range"))
+		}
+		flags |= os.O_APPEND
+	case res.StatusCode == http.StatusRequestedRangeNotSatisfiable && offset > 0:
+		// the file was downloaded completely but not renamed
+		return offset, os.Rename(part, p)
+	case res.StatusCode == http.StatusOK:
+		flags |= os.O_TRUNC
+	default:
+		return 0, fmt.Errorf("unexpected status code: %d", res.StatusCode)
+	}
+	file, err := os.OpenFile(part, flags, 0600)
+	if err != nil {
+		return 0, err
+	}
+	var body io.Reader = res.Body
+	if timer != nil {
+		body = &stallReader{r: body, timer: timer, timeout: timeout}
+	}
+	_, err = io.Copy(file, body)
+	if closeErr := file.Close(); err == nil {
+		err = closeErr
+	}
+	if err != nil {
+		return 0, err
+	}
+	info, err := os.Stat(part)
+	if err != nil {
+		return 0, err
+	}
+	return info.Size(), os.Rename(part, p)
+}
+
+// stallReader resets the timer whenever data is read.
+type stallReader struct {
+	r       io.Reader
+	timer   *time.Timer
+	timeout time.Duration
+}
+
+func (r *stallReader) Read(p []byte) (int, error) {
+	n, err := r.r.Read(p)
+	if n > 0 {
+		r.timer.Reset(r.timeout)
+	}
+	return n, err
+}
+
+// enclosureFileName names the file after the item, with the extension of the
+// enclosure URL or of its type. A short hash of the item ID, and the position
+// of the enclosure after the first one, tell apart items with the same title
+// and enclosures of the same item.
+func enclosureFileName(item *gofeed.Item, e *Enclosure, index int) string {
+	ext := ""
+	if u, err := url.Parse(e.URL); err == nil {
+		ext = path.Ext(u.Path)
+	}
+	if len(ext) < 2 || len(ext) > 6 {
+		ext = ""
+		if exts, err := mime.ExtensionsByType(e.Type); err == nil && len(exts) > 0 {
+			ext = exts[0]
+		}
+	}
+	h := fnv.New32a()
+	h.Write([]byte(storage.ItemID(item)))
+	suffix := fmt.Sprintf(" [%08x]", h.Sum32())
+	if index > 0 {
+		suffix += " " + strconv.Itoa(index+1)
+	}
+	suffix += ext
+	name := sanitizeFileName(cmp.Or(item.Title, storage.ItemID(item)))
+	if len(name)+len(suffix) > maxFileNameLength {
+		name = strings.ToValidUTF8(name[:maxFileNameLength-len(suffix)], "")
+	}
+	return name + suffix
+}
+
+// sanitizeFileName replaces the characters that are not allowed in file names
+// on common file systems.
+func sanitizeFileName(name string) string {
+	name = strings.Map(func(r rune) rune {
+		if unicode.IsControl(r) || strings.ContainsRune(`/\:*?"<>|`, r) {
+			return '_'
+		}
+		return r
+	}, name)
+	name = strings.Trim(name, " .")
+	if len(name) > maxFileNameLength {
+		name = strings.ToValidUTF8(name[:maxFileNameLength], "")
+	}
+	return cmp.Or(name, "untitled")
+}
+
+// downloadDir returns the directory for downloads, ~/Downloads/cleed by
+// default.
+func downloadDir(configured string) (string, error) {
+	if configured != "" && configured != "~" && !strings.HasPrefix(configured, "~/") {
+		return configured, nil
+	}
+	home, err := os.UserHomeDir()
+	if err != nil {
+		return "", utils.NewInternalError("failed to get home directory: " + err.Error())
+	}
+	if configured == "" {
+		return filepath.Join(home, "Downloads", "cleed"), nil
+	}
+	return filepath.Join(home, strings.TrimPrefix(configured, "~")), nil
+}
+
+// playerCommand returns the configured player or the system opener.
+func playerCommand(config *storage.Config) []string {
+	if command := strings.Fields(config.PlayerCommand); len(command) > 0 {
+		return command
+	}
+	return systemOpener()
+}
@@ -4,8 +4,10 @@ import (
 	"os"
 	"path/filepath"
 	"slices"
+	"strconv"
 	"strings"
 
+	"github.com/radulucut/cleed/internal/search"
 	"github.com/radulucut/cleed/internal/utils"
 )
 
@@ -15,6 +17,7 @@ type ExploreOptions struct {
 	Limit  int
 	Query  string
 	Import bool
+	Fuzzy  bool
 }
 
 func (f *TerminalFeed) ExploreRemove(url string) error {
@@ -30,43 +33,60 @@ func (f *TerminalFeed) ExploreRemove(url string) error {
 }
 
 type ExploreSearchItem struct {
-	ListColor uint8
+	ListColor Color
 	Outline   *utils.OPMLOutline
-	Score     int
+	Score     float64
 }
 
 func (f *TerminalFeed) ExploreSearch(opts *ExploreOptions) error {
-	queryTokens := utils.Tokenize(opts.Query, nil)
-	if len(queryTokens) == 0 {
-		return utils.NewInternalError("query is empty")
+	config, err := f.storage.LoadConfig()
+	if err != nil {
+		return utils.NewInternalError("failed to load config: " + err.Error())
 	}
-	lists, err := f.getExploreFeeds(opts.Url, opts.Update)
+	analyzer, err := newAnalyzer(config)
 	if err != nil {
 		return err
 	}
-	config, err := f.storage.LoadConfig()
+	theme := configTheme(config)
+	query, err := search.ParseQuery(opts.Query, analyzer)
 	if err != nil {
-		return utils.NewInternalError("failed to load config: " + err.Error())
+		return utils.NewInternalError("invalid query: " + err.Error())
 	}
-	items := make([]*ExploreSearchItem, 0)
+	query.Fuzzy = opts.Fuzzy
+	if query.IsEmpty() {
+		return utils.NewInternalError("query is empty")
+	}
+	lists, err := f.getExploreFeeds(opts.Url, opts.Update)
+	if err != nil {
+		return err
+	}
+	idx := search.NewIndex(analyzer)
+	candidates := make([]*ExploreSearchItem, 0)
 	for i, list := range lists {
 		for _, outline := range list.Outlines {
 			if outline.XMLURL == "" {
 				continue
 			}
-			itemTokens := utils.Tokenize(outline.Text, nil)
-			itemTokens = utils.Tokenize(outline.Description, itemTokens)
-			score := utils.Score(queryTokens, itemTokens)
-			if score == -1 {
-				continue
-			}
-			items = append(items, &ExploreSearchItem{
-				ListColor: mapColor(uint8(i+1%256), config),
+			idx.Add(strconv.Itoa(len(candidates)), map[string]string{
+				search.FieldTitle:       outline.Text,
+				search.FieldDescription: outline.Description,
+			})
+			candidates = append(candidates, &ExploreSearchItem{
+				ListColor: theme.FeedColor(i + 1),
 				Outline:   outline,
-				Score:     score,
 			})
 		}
 	}
+	results := idx.Search(query)
+	items := make([]*ExploreSearchItem, 0, len(results))
+	for i, item := range candidates {
+		r, ok := results[strconv.Itoa(i)]
+		if !ok {
+			continue
+		}
+		item.Score = r.Score
+		items = append(items, item)
+	}
 	if len(items) == 0 {
 		f.printer.Printf("no results found for %s\n", opts.Query)
 	}
@@ -78,22 +98,21 @@ func (f *TerminalFeed) ExploreSearch(opts *ExploreOptions) error {
 			}
 			urls = append(urls, item.Outline.XMLURL)
 		}
-		err := f.storage.AddToList(urls, opts.Query)
+		added, err := f.addToList(urls, opts.Query)
 		if err != nil {
 			f.printer.Printf("failed to add feeds to list %s: %v\n", opts.Query, err)
 		}
-		f.printer.Printf("Imported %s into %s\n", utils.Pluralize(int64(len(items)), "feed"), opts.Query)
+		f.printer.Printf("Imported %s into %s\n", utils.Pluralize(int64(added), "feed"), opts.Query)
 		return nil
 	}
 	slices.SortFunc(items, func(a, b *ExploreSearchItem) int {
 		if a.Score == b.Score {
 			return strings.Compare(a.Outline.Text, b.Outline.Text)
-		} else if a.Score < b.Score {
+		} else if a.Score > b.Score {
 			return -1
 		}
 		return 1
 	})
-	secondaryTextColor := mapColor(7, config)
 	totalDisplayed := 0
 	l := min(opts.Limit, len(items))
 	if l <= 0 {
@@ -105,12 +124,12 @@ func (f *TerminalFeed) ExploreSearch(opts *ExploreOptions) error {
 			continue
 		}
 		if item.Outline.Text != "" {
-			f.printer.Print(f.printer.ColorForeground(item.Outline.Text, item.ListColor), "\n")
+			f.printer.Print(f.printer.Color(item.Outline.Text, item.ListColor), "\n")
 		}
 		if item.Outline.Description != "" {
 			f.printer.Print(item.Outline.Description, "\n")
 		}
-		f.printer.Print(f.printer.ColorForeground(item.Outline.XMLURL, secondaryTextColor), "\n\n")
+		f.printer.Print(f.printer.Color(item.Outline.XMLURL, theme.Link), "\n\n")
 		totalDisplayed++
 	}
 	f.printer.Printf("Displayed %d out of %s\n", totalDisplayed, utils.Pluralize(int64(len(items)), "item"))
@@ -133,12 +152,12 @@ func (f *TerminalFeed) Explore(opts *ExploreOptions) error {
 	if err != nil {
 		return utils.NewInternalError("failed to load config: " + err.Error())
 	}
-	secondaryTextColor := mapColor(7, config)
+	theme := configTheme(config)
 	total := 0
 	totalDisplayed := 0
 	for i := len(lists) - 1; i >= 0; i-- {
 		list := lists[i]
-		listColor := mapColor(uint8(i+1%256), config)
+		listColor := theme.FeedColor(i + 1)
 		for j, outline := range list.Outlines {
 			if outline.XMLURL == "" {
 				continue
@@ -147,12 +166,12 @@ func (f *TerminalFeed) Explore(opts *ExploreOptions) error {
 				break
 			}
 			if outline.Text != "" {
-				f.printer.Print(f.printer.ColorForeground(outline.Text, listColor), "\n")
+				f.printer.Print(f.printer.Color(outline.Text, listColor), "\n")
 			}
 			if outline.Description != "" {
 				f.printer.Print(outline.Description, "\n")
 			}
-			f.printer.Print(f.printer.ColorForeground(outline.XMLURL, secondaryTextColor), "\n\n")
+			f.printer.Print(f.printer.Color(outline.XMLURL, theme.Link), "\n\n")
 		}
 		displayed := len(list.Outlines)
 		if opts.Limit > 0 && opts.Limit < displayed {
@@ -187,8 +206,8 @@ func (f *TerminalFeed) ExploreImport(opts *ExploreOptions) error {
 			}
 			urls = append(urls, outline.XMLURL)
 		}
-		totalImported += len(urls)
-		err = f.storage.AddToList(urls, list.Text)
+		added, err := f.addToList(urls, list.Text)
+		totalImported += added
 		if err != nil {
 			f.printer.Printf("failed to add feeds to list %s: %v\n", list.Text, err)
 		}
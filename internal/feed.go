@@ -1,11 +1,15 @@
 package internal
 
 import (
+	"cmp"
 	"compress/gzip"
 	"fmt"
 	"io"
+	"maps"
 	"net/http"
 	"net/url"
+	"path"
+	"regexp"
 	"slices"
 	"strconv"
 	"strings"
@@ -15,6 +19,7 @@ import (
 	"github.com/andybalholm/brotli"
 	"github.com/mattn/go-runewidth"
 	"github.com/mmcdole/gofeed"
+	"github.com/radulucut/cleed/internal/search"
 	"github.com/radulucut/cleed/internal/storage"
 	"github.com/radulucut/cleed/internal/utils"
 	"golang.org/x/net/proxy"
@@ -24,7 +29,7 @@ import (
 type TerminalFeed struct {
 	time     utils.Time
 	printer  *Printer
-	storage  *storage.LocalStorage
+	storage  storage.Storage
 	http     *http.Client
 	parser   *gofeed.Parser
 	miniflux *miniflux.Client
@@ -36,7 +41,7 @@ type TerminalFeed struct {
 func NewTerminalFeed(
 	_time utils.Time,
 	printer *Printer,
-	storage *storage.LocalStorage,
+	storage storage.Storage,
 ) *TerminalFeed {
 	return &TerminalFeed{
 		time:    _time,
@@ -59,16 +64,49 @@ func (f *TerminalFeed) Follow(urls []string, list string) error {
 		}
 		urls[i] = u.String()
 	}
-	err := f.storage.AddToList(urls, list)
+	added, err := f.addToList(urls, list)
 	if err != nil {
 		return utils.NewInternalError("failed to save feeds: " + err.Error())
 	}
-	f.printer.Printf("added %s to list: %s\n", utils.Pluralize(int64(len(urls)), "feed"), list)
+	f.printer.Printf("added %s to list: %s\n", utils.Pluralize(int64(added), "feed"), list)
 	return nil
 }
 
+// addToList adds the feeds to the list after normalizing their URLs. Feeds
+// that are already in the list, even with a slightly different URL (e.g. http
+// instead of https), are skipped. It returns the number of feeds added.
+func (f *TerminalFeed) addToList(urls []string, list string) (int, error) {
+	feeds, err := f.storage.GetFeedsFromList(list)
+	if err != nil {
+		return 0, err
+	}
+	keys := make(map[string]struct{}, len(feeds))
+	for _, item := range feeds {
+		keys[utils.URLKey(item.Address)] = struct{}{}
+	}
+	added := make([]string, 0, len(urls))
+	for _, u := range urls {
+		u = utils.CanonicalURL(u)
+		key := utils.URLKey(u)
+		if _, ok := keys[key]; ok {
+			continue
+		}
+		keys[key] = struct{}{}
+		added = append(added, u)
+	}
+	return len(added), f.storage.AddToList(added, list)
+}
+
 func (f *TerminalFeed) Unfollow(urls []string, list string) error {
-	results, err := f.storage.RemoveFromList(urls, list)
+	config, err := f.storage.LoadConfig()
+	if err != nil {
+		return utils.NewInternalError("failed to load config: " + err.Error())
+	}
+	feeds, err := f.storage.GetFeedsFromList(list)
+	if err != nil {
+		return utils.NewInternalError("failed to list feeds: " + err.Error())
+	}
+	results, err := f.storage.RemoveFromList(followedAddresses(urls, feeds), list)
 	if err != nil {
 		return utils.NewInternalError(err.Error())
 	}
@@ -76,12 +114,30 @@ func (f *TerminalFeed) Unfollow(urls []string, list string) error {
 		if results[i] {
 			f.printer.Print(urls[i] + " was removed from the list\n")
 		} else {
-			f.printer.Print(f.printer.ColorForeground(urls[i]+" was not found in the list\n", 11))
+			f.printer.Print(f.printer.Color(urls[i]+" was not found in the list", configTheme(config).Error), "\n")
 		}
 	}
 	return nil
 }
 
+// followedAddresses returns the address under which each URL is followed,
+// since URLs are canonicalized when they are followed. URLs that are not
+// followed are returned unchanged.
+func followedAddresses(urls []string, feeds []*storage.ListItem) []string {
+	addresses := make([]string, len(urls))
+	for i, u := range urls {
+		addresses[i] = u
+		key := utils.URLKey(u)
+		for _, item := range feeds {
+			if utils.URLKey(item.Address) == key {
+				addresses[i] = item.Address
+				break
+			}
+		}
+	}
+	return addresses
+}
+
 func (f *TerminalFeed) Lists() error {
 	lists, err := f.storage.LoadLists()
 	if err != nil {
@@ -91,9 +147,26 @@ func (f *TerminalFeed) Lists() error {
 		f.printer.Println("default")
 		return nil
 	}
-	slices.Sort(lists)
+	config, err := f.storage.LoadConfig()
+	if err != nil {
+		return utils.NewInternalError("failed to load config: " + err.Error())
+	}
+	slices.SortFunc(lists, storage.CompareLists)
+	printed := make(map[string]struct{})
 	for i := range lists {
-		f.printer.Println(lists[i])
+		parts := strings.Split(lists[i], storage.ListSeparator)
+		for depth := range parts {
+			name := strings.Join(parts[:depth+1], storage.ListSeparator)
+			if _, ok := printed[name]; ok {
+				continue
+			}
+			printed[name] = struct{}{}
+			line := strings.Repeat("  ", depth) + parts[depth]
+			if slices.Contains(config.PausedLists, name) {
+				line += " (paused)"
+			}
+			f.printer.Println(line)
+		}
 	}
 	return nil
 }
@@ -103,8 +176,16 @@ func (f *TerminalFeed) ListFeeds(list string) error {
 	if err != nil {
 		return utils.NewInternalError("failed to list feeds: " + err.Error())
 	}
+	config, err := f.storage.LoadConfig()
+	if err != nil {
+		return utils.NewInternalError("failed to load config: " + err.Error())
+	}
 	for i := range feeds {
-		f.printer.Printf("%s  %s\n", feeds[i].AddedAt.Format("2006-01-02 15:04:05"), feeds[i].Address)
+		f.printer.Printf("%s  %s", feeds[i].AddedAt.Format("2006-01-02 15:04:05"), feeds[i].Address)
+		if config.IsFeedPaused(feeds[i].Address, []string{list}) {
+			f.printer.Print("  (paused)")
+		}
+		f.printer.Println()
 	}
 	f.printer.Println("Total: " + utils.Pluralize(int64(len(feeds)), "feed"))
 	return nil
@@ -128,6 +209,69 @@ func (f *TerminalFeed) MergeLists(list, otherList string) error {
 	return nil
 }
 
+// MoveFeeds moves the feeds matching the patterns from one list to another.
+func (f *TerminalFeed) MoveFeeds(patterns []string, from, to string) error {
+	return f.transferFeeds(patterns, from, to, true)
+}
+
+// CopyFeeds copies the feeds matching the patterns from one list to another.
+func (f *TerminalFeed) CopyFeeds(patterns []string, from, to string) error {
+	return f.transferFeeds(patterns, from, to, false)
+}
+
+func (f *TerminalFeed) transferFeeds(patterns []string, from, to string, move bool) error {
+	if to == "" {
+		return utils.NewInternalError("please provide the destination list with --to")
+	}
+	if from == to {
+		return utils.NewInternalError("source and destination lists are the same: " + to)
+	}
+	feeds, err := f.storage.GetFeedsFromList(from)
+	if err != nil {
+		return utils.NewInternalError("failed to list feeds: " + err.Error())
+	}
+	urls := make([]string, 0)
+	for _, pattern := range patterns {
+		matched := false
+		for _, feed := range feeds {
+			if matchFeed(pattern, feed.Address) {
+				matched = true
+				if !slices.Contains(urls, feed.Address) {
+					urls = append(urls, feed.Address)
+				}
+			}
+		}
+		if !matched {
+			return utils.NewInternalError(fmt.Sprintf("no feed in list %s matches: %s", from, pattern))
+		}
+	}
+	action := "copied"
+	if move {
+		action = "moved"
+		err = f.storage.MoveFeeds(urls, from, to)
+	} else {
+		err = f.storage.CopyFeeds(urls, from, to)
+	}
+	if err != nil {
+		return utils.NewInternalError("failed to save feeds: " + err.Error())
+	}
+	f.printer.Printf("%s %s from %s to %s\n", action, utils.Pluralize(int64(len(urls)), "feed"), from, to)
+	return nil
+}
+
+// matchFeed reports whether the URL matches the pattern, where * matches any
+// sequence of characters and ? any single character.
+func matchFeed(pattern, url string) bool {
+	if !strings.ContainsAny(pattern, "*?") {
+		return utils.URLKey(pattern) == utils.URLKey(url)
+	}
+	expr := regexp.QuoteMeta(pattern)
+	expr = strings.ReplaceAll(expr, `\*`, ".*")
+	expr = strings.ReplaceAll(expr, `\?`, ".")
+	ok, _ := regexp.MatchString("^"+expr+"$", url)
+	return ok
+}
+
 func (f *TerminalFeed) RemoveList(list string) error {
 	err := f.storage.RemoveList(list)
 	if err != nil {
@@ -160,6 +304,24 @@ func (f *TerminalFeed) ShowCacheInfo() error {
 	if err != nil {
 		return utils.NewInternalError("failed to load cache info: " + err.Error())
 	}
+	config, err := f.storage.LoadConfig()
+	if err != nil {
+		return utils.NewInternalError("failed to load config: " + err.Error())
+	}
+	lists, err := f.storage.LoadLists()
+	if err != nil {
+		return utils.NewInternalError("failed to load lists: " + err.Error())
+	}
+	feedLists := make(map[string][]string)
+	for _, list := range lists {
+		feeds, err := f.storage.GetFeedsFromList(list)
+		if err != nil {
+			return utils.NewInternalError("failed to list feeds: " + err.Error())
+		}
+		for _, feed := range feeds {
+			feedLists[feed.Address] = append(feedLists[feed.Address], list)
+		}
+	}
 	cellMax := [1]int{}
 	items := make([]*storage.CacheInfoItem, 0, len(cacheInfo))
 	for k, v := range cacheInfo {
@@ -173,18 +335,60 @@ func (f *TerminalFeed) ShowCacheInfo() error {
 	})
 	for i := range items {
 		f.printer.Print(runewidth.FillRight(items[i].URL, cellMax[0]))
-		f.printer.Printf("  %s  %s\n", items[i].LastFetch.Format("2006-01-02 15:04:05"), items[i].FetchAfter.Format("2006-01-02 15:04:05"))
+		f.printer.Printf("  %s  %s", items[i].LastFetch.Format("2006-01-02 15:04:05"), items[i].FetchAfter.Format("2006-01-02 15:04:05"))
+		if config.IsFeedPaused(items[i].URL, feedLists[items[i].URL]) {
+			f.printer.Print("  (paused)")
+		}
+		f.printer.Println()
 	}
 	return nil
 }
 
+const (
+	SortRelevance = "relevance"
+	SortDate      = "date"
+	SortFeed      = "feed"
+)
+
+var SortOptions = []string{SortRelevance, SortDate, SortFeed}
+
+const (
+	GroupByFeed     = "feed"
+	GroupByList     = "list"
+	GroupByDay      = "day"
+	GroupByCategory = "category"
+)
+
+var GroupByOptions = []string{GroupByFeed, GroupByList, GroupByDay, GroupByCategory}
+
 type FeedOptions struct {
-	List       string
-	Query      [][]rune
-	Limit      int
-	Since      time.Time
-	Proxy      *url.URL
-	CachedOnly bool
+	Lists        []string // list names or glob patterns, all lists if empty
+	ExcludeLists []string // list names or glob patterns
+	Query        *search.Query
+	Sort         string
+	Limit        int
+	Since        time.Time
+	Proxy        *url.URL
+	CachedOnly   bool
+	Fuzzy        bool
+	Dedupe       bool             // collapse the same item from different feeds
+	GroupBy      string           // feed, list, day or category, no groups if empty
+	PerGroup     int              // maximum number of items per group, 0: unlimited
+	Collapse     bool             // hide the items of groups without new items
+	Layout       string           // built-in layout or template name, the configured one if empty
+	HideLinks    bool             // hide item links when titles are clickable
+	Open         bool             // open the displayed items and mark them as read
+	Searches     []*SearchOptions // saved searches whose items are displayed with the items of Lists
+}
+
+// SearchOptions selects the items of a saved search. Only the saved
+// searches are displayed when Lists is empty.
+type SearchOptions struct {
+	Name  string
+	Query string
+	Lists []string // list names or glob patterns, all lists if empty
+	Since time.Time
+	Fuzzy bool
 }
 
 func (f *TerminalFeed) Search(query string, opts *FeedOptions) error {
@@ -195,34 +399,42 @@ func (f *TerminalFeed) Search(query string, opts *FeedOptions) error {
 	if err != nil {
 		return utils.NewInternalError("failed to load config: " + err.Error())
 	}
-	opts.Query = utils.Tokenize(query, nil)
-	if len(opts.Query) == 0 {
+	analyzer, err := newAnalyzer(config)
+	if err != nil {
+		return err
+	}
+	opts.Query, err = search.ParseQuery(query, analyzer)
+	if err != nil {
+		return utils.NewInternalError("invalid query: " + err.Error())
+	}
+	opts.Query.Fuzzy = opts.Fuzzy
+	if opts.Query.IsEmpty() && len(opts.Searches) == 0 {
 		return utils.NewInternalError("query is empty")
 	}
+	layout, err := f.newItemLayout(opts, config)
+	if err != nil {
+		return err
+	}
 	items, err := f.processFeeds(opts, config, summary)
 	if err != nil {
 		return err
 	}
-	slices.SortFunc(items, func(a, b *FeedItem) int {
-		if a.Score > b.Score {
-			return 1
-		}
-		if a.Score < b.Score {
-			return -1
-		}
-		return 0
-	})
-	f.outputItems(items, config, summary, opts)
-	return nil
+	sortItems(items, cmp.Or(opts.Sort, SortRelevance))
+	return f.outputItems(items, config, summary, opts, layout)
 }
 
 type FeedItem struct {
 	Feed              *gofeed.Feed
+	FeedURL           string
 	Item              *gofeed.Item
 	PublishedRelative string
-	FeedColor         uint8
+	FeedColor         Color
 	IsNew             bool
-	Score             int
+	Score             float64
+	Highlights        []string    // matched search terms
+	Lists             []string    // lists the feed of the item belongs to
+	Duplicates        []*FeedItem // the same item from other feeds
+	Index             int         // position in the timeline where 1 is the newest item, 0 in search results
 }
 
 type RunSummary struct {
@@ -242,26 +454,55 @@ func (f *TerminalFeed) Feed(opts *FeedOptions) error {
 	if err != nil {
 		return utils.NewInternalError("failed to load config: " + err.Error())
 	}
+	layout, err := f.newItemLayout(opts, config)
+	if err != nil {
+		return err
+	}
 	items, err := f.processFeeds(opts, config, summary)
 	if err != nil {
 		return err
 	}
+	sortItems(items, cmp.Or(opts.Sort, SortDate))
+	now := f.time.Now()
+	f.storage.UpdateConfig(func(config *storage.Config) error {
+		config.LastRun = now
+		return nil
+	})
+	err = f.outputItems(items, config, summary, opts, layout)
+	if err != nil {
+		return err
+	}
+	if !opts.CachedOnly {
+		f.autoDownload(items, config)
+	}
+	return nil
+}
+
+// sortItems puts the first item to display last at the top. Ties are broken
+// by date, feed and title so that the order does not depend on fetch order.
+func sortItems(items []*FeedItem, by string) {
 	slices.SortFunc(items, func(a, b *FeedItem) int {
-		if a.Item.PublishedParsed == nil || b.Item.PublishedParsed == nil {
-			return 0
+		switch by {
+		case SortRelevance:
+			if c := cmp.Compare(b.Score, a.Score); c != 0 {
+				return c
+			}
+		case SortFeed:
+			if c := strings.Compare(a.Feed.Title, b.Feed.Title); c != 0 {
+				return c
+			}
+			if c := strings.Compare(a.FeedURL, b.FeedURL); c != 0 {
+				return c
+			}
 		}
-		if a.Item.PublishedParsed.After(*b.Item.PublishedParsed) {
-			return -1
+		if c := b.Item.PublishedParsed.Compare(*a.Item.PublishedParsed); c != 0 {
+			return c
 		}
-		if a.Item.PublishedParsed.Before(*b.Item.PublishedParsed) {
-			return 1
+		if c := strings.Compare(a.Feed.Title, b.Feed.Title); c != 0 {
+			return c
 		}
-		return 0
+		return strings.Compare(a.Item.Title, b.Item.Title)
 	})
-	config.LastRun = f.time.Now()
-	f.storage.SaveConfig()
-	f.outputItems(items, config, summary, opts)
-	return nil
 }
 
 func (f *TerminalFeed) outputItems(
@@ -269,49 +510,105 @@ func (f *TerminalFeed) outputItems(
 	config *storage.Config,
 	summary *RunSummary,
 	opts *FeedOptions,
-) {
-	l := len(items)
-	if l == 0 {
+	layout *itemLayout,
+) error {
+	if len(items) == 0 {
 		f.printer.ErrPrintln("no items to display")
-		return
+		return nil
 	}
-	if opts.Limit > 0 {
-		l = min(len(items), opts.Limit)
+	var groups []*itemGroup
+	if opts.GroupBy != "" {
+		groups = groupItems(items, opts)
+	} else {
+		l := len(items)
+		if opts.Limit > 0 {
+			l = min(len(items), opts.Limit)
+		}
+		groups = []*itemGroup{{Items: items[:l]}}
 	}
-	cellMax := [1]int{}
-	for i := l - 1; i >= 0; i-- {
-		fi := items[i]
+	// an item is in several groups when grouped by list or category
+	displayed := make([]*FeedItem, 0)
+	seen := make(map[*FeedItem]struct{})
+	for _, g := range groups {
+		if g.collapsed(opts) {
+			continue
+		}
+		for _, fi := range g.Items {
+			if _, ok := seen[fi]; !ok {
+				seen[fi] = struct{}{}
+				displayed = append(displayed, fi)
+			}
+		}
+	}
+	if opts.Query == nil {
+		// the same index as in cleed open, which counts from the newest item
+		byDate := slices.Clone(items)
+		sortItems(byDate, SortDate)
+		for i, fi := range byDate {
+			fi.Index = i + 1
+		}
+		err := f.storage.SaveTimeline(timelineItems(byDate))
+		if err != nil {
+			f.printer.ErrPrintln("failed to save timeline:", err)
+		}
+	}
+	for _, fi := range displayed {
 		fi.PublishedRelative = utils.Relative(f.time.Now().Unix() - fi.Item.PublishedParsed.Unix())
-		cellMax[0] = max(cellMax[0], runewidth.StringWidth(fi.Feed.Title), len(fi.PublishedRelative))
-	}
-	cellMax[0] = min(cellMax[0], 30)
-	secondaryTextColor := mapColor(7, config)
-	highlightColor := mapColor(10, config)
-	for i := l - 1; i >= 0; i-- {
-		fi := items[i]
-		newMark := ""
-		if fi.IsNew {
-			newMark = f.printer.ColorForeground("• ", highlightColor)
-		}
-		if strings.HasPrefix(fi.Item.Link, "/") {
-			baseUrl := strings.TrimSuffix(fi.Feed.Link, "/")
-			fi.Item.Link = baseUrl + fi.Item.Link
-		}
-		f.printer.Print(
-			f.printer.ColorForeground(runewidth.FillRight(runewidth.Truncate(fi.Feed.Title, cellMax[0], "..."), cellMax[0]), fi.FeedColor),
-			"  ",
-			newMark+fi.Item.Title,
-			"\n",
-			f.printer.ColorForeground(runewidth.FillRight(fi.PublishedRelative, cellMax[0]), secondaryTextColor),
-			"  ",
-			f.printer.ColorForeground(fi.Item.Link, secondaryTextColor),
-			"\n\n",
-		)
+	}
+	layout.prepare(displayed)
+	layout.showLists = len(opts.Lists)+len(opts.ExcludeLists) > 0 && countLists(displayed) > 1 && opts.GroupBy != GroupByList
+	separator := layout.groupSeparator()
+	for i, g := range groups {
+		collapsed := g.collapsed(opts)
+		if g.Name != "" {
+			if i > 0 && separator == "" {
+				f.printer.Println()
+			}
+			counts := utils.Pluralize(int64(g.Total), "item")
+			if g.New > 0 {
+				counts += fmt.Sprintf(", %d new", g.New)
+			}
+			if len(g.Items) < g.Total && !collapsed {
+				counts += fmt.Sprintf(", showing %d", len(g.Items))
+			}
+			f.printer.Print(
+				f.printer.Color(g.Name, layout.theme.Highlight),
+				"  ",
+				f.printer.Color(counts, layout.theme.Secondary),
+				"\n",
+				separator,
+			)
+		}
+		if collapsed {
+			continue
+		}
+		for i := len(g.Items) - 1; i >= 0; i-- {
+			err := layout.printItem(g.Items[i])
+			if err != nil {
+				return err
+			}
+			f.printer.Print(separator)
+		}
 	}
 	if config.Summary == 1 {
-		summary.ItemsShown = l
+		summary.ItemsShown = len(displayed)
 		f.printSummary(summary)
 	}
+	if opts.Open {
+		return f.openNewItems(displayed, config)
+	}
+	return nil
+}
+
+// countLists returns the number of distinct lists the items come from.
+func countLists(items []*FeedItem) int {
+	lists := make(map[string]struct{})
+	for _, fi := range items {
+		for _, list := range fi.Lists {
+			lists[list] = struct{}{}
+		}
+	}
+	return len(lists)
 }
 
 func (f *TerminalFeed) printSummary(s *RunSummary) {
@@ -331,43 +628,43 @@ func (f *TerminalFeed) processFeeds(opts *FeedOptions, config *storage.Config, s
 	if err != nil {
 		return nil, err
 	}
-	lists := make([]string, 0)
-	if opts.List != "" {
-		lists = append(lists, opts.List)
-	} else {
-		lists, err = f.storage.LoadLists()
-		if err != nil {
-			return nil, utils.NewInternalError("failed to load lists: " + err.Error())
-		}
-		if len(lists) == 0 {
-			return nil, utils.NewInternalError("no feeds to display")
-		}
+	analyzer, err := newAnalyzer(config)
+	if err != nil {
+		return nil, err
 	}
-	feeds := make(map[string]*storage.ListItem)
-	for i := range lists {
-		f.storage.LoadFeedsFromList(feeds, lists[i])
+	sel, err := f.selectFeeds(opts, analyzer)
+	if err != nil {
+		return nil, err
 	}
+	feeds, feedLists := sel.feeds, sel.feedLists
+	since := sel.since(opts)
+	// searches also find the archived items, which are no longer published
+	includeArchive := opts.Query != nil || len(opts.Searches) > 0 || !since.IsZero()
 	summary.FeedsCount = len(feeds)
 	cacheInfo, err := f.storage.LoadCacheInfo()
 	if err != nil {
 		return nil, utils.NewInternalError("failed to load cache info: " + err.Error())
 	}
+	index := &feedIndex{
+		analyzer: analyzer,
+	}
 	mx := sync.Mutex{}
 	wg := sync.WaitGroup{}
 	sem := make(chan struct{}, config.BatchSize)
 	items := make([]*FeedItem, 0)
-	feedColorMap := make(map[string]uint8)
+	// only the cache info of fetched feeds is saved, so that the fetches of
+	// other processes in the meantime are kept
+	fetched := make(map[string]*storage.CacheInfoItem)
+	feedColorMap := make(map[string]Color)
+	theme := configTheme(config)
 	for url := range feeds {
-		sem <- struct{}{}
-		ci := cacheInfo[url]
-		if ci == nil {
-			ci = &storage.CacheInfoItem{
-				URL:        url,
-				LastFetch:  time.Unix(0, 0),
-				FetchAfter: time.Unix(0, 0),
-			}
-			cacheInfo[url] = ci
+		// paused feeds keep their cache but are neither fetched nor displayed
+		if config.IsFeedPaused(url, feedLists[url]) {
+			summary.FeedsCount--
+			continue
 		}
+		sem <- struct{}{}
+		ci := cacheInfoItem(cacheInfo, url)
 		wg.Add(1)
 		go func(ci *storage.CacheInfoItem) {
 			defer wg.Done()
@@ -379,58 +676,281 @@ func (f *TerminalFeed) processFeeds(opts *FeedOptions, config *storage.Config, s
 				if err != nil {
 					return
 				}
+				if includeArchive {
+					feed = f.withArchivedItems(feed, url)
+				}
 				mx.Lock()
 				defer mx.Unlock()
-				items = f.processFeedItems(feed, items, config, opts, summary, feedColorMap, ci)
+				items = f.processFeedItems(feed, items, config, theme, since, summary, feedColorMap, ci)
 				summary.FeedsCached++
 				return
 			}
 			res, err := f.fetchFeed(ci, config)
 			if err != nil {
 				f.printer.ErrPrintf("failed to fetch feed: %s: %v\n", ci.URL, err)
+				f.storage.AddFetchHistory(&storage.FetchHistoryItem{
+					Time:  f.time.Now(),
+					Error: err.Error(),
+				}, url)
 				return
 			}
-			feed, err := f.parseFeed(url)
+			var feed *gofeed.Feed
+			if res.Changed {
+				feed, err = f.parseRawFeed(url)
+			} else {
+				feed, err = f.parseFeed(url)
+			}
 			if err != nil {
 				f.printer.ErrPrintf("failed to parse feed: %s: %v\n", ci.URL, err)
+				f.storage.AddFetchHistory(&storage.FetchHistoryItem{
+					Time:   f.time.Now(),
+					Status: res.Status,
+					Error:  err.Error(),
+				}, url)
 				return
 			}
+			if res.Status != 0 {
+				f.storage.AddFetchHistory(&storage.FetchHistoryItem{
+					Time:   f.time.Now(),
+					Status: res.Status,
+					Items:  len(feed.Items),
+				}, url)
+			}
+			var archived *gofeed.Feed
+			if res.Changed {
+				archive, err := f.storage.AddToArchive(feed.Items, url, config.ArchiveRetention())
+				if err != nil {
+					f.printer.ErrPrintf("failed to archive feed items: %s: %v\n", ci.URL, err)
+				}
+				archived = withArchive(feed, archive)
+			}
+			// only the displayed items include the archive, the fetched feed
+			// is cached as it is
+			displayed := feed
+			if includeArchive {
+				if archived == nil {
+					archived = f.withArchivedItems(feed, url)
+				}
+				displayed = archived
+			}
 			mx.Lock()
 			defer mx.Unlock()
-			items = f.processFeedItems(feed, items, config, opts, summary, feedColorMap, ci)
+			items = f.processFeedItems(displayed, items, config, theme, since, summary, feedColorMap, ci)
 			if res.Changed {
+				f.updateIndex(index, url, archived.Items)
 				ci.ETag = res.ETag
 				ci.LastFetch = f.time.Now()
 				summary.FeedsFetched++
 				f.storage.SaveParsedFeedCache(feed, url)
+				fetched[url] = ci
 			} else {
 				summary.FeedsCached++
 			}
 			if res.FetchAfter.After(ci.FetchAfter) {
 				ci.FetchAfter = res.FetchAfter
+				fetched[url] = ci
 			}
 		}(ci)
 	}
 	wg.Wait()
-	err = f.storage.SaveCacheInfo(cacheInfo)
+	for _, item := range items {
+		item.Lists = feedLists[item.FeedURL]
+	}
+	err = f.storage.UpdateCacheInfo(func(cacheInfo map[string]*storage.CacheInfoItem) error {
+		maps.Copy(cacheInfo, fetched)
+		return nil
+	})
 	if err != nil {
 		f.printer.ErrPrintln("failed to save cache informaton:", err)
 	}
+	if len(opts.Searches) > 0 {
+		items = f.matchSearches(index, items, opts, sel, config)
+	} else if opts.Query != nil {
+		items = f.searchItems(index, items, opts.Query, config)
+	}
+	if opts.Dedupe || config.Dedupe {
+		items = dedupeItems(items)
+	}
+	if len(opts.Lists) == 0 && len(opts.ExcludeLists) == 0 && len(opts.Searches) == 0 {
+		f.tidyIndex(index, feeds)
+	}
+	f.saveIndex(index)
 	return items, nil
 }
 
-func (f *TerminalFeed) tokenizeItem(item *gofeed.Item) [][]rune {
-	tokens := utils.Tokenize(item.Title, nil)
-	for i := range item.Categories {
-		tokens = utils.Tokenize(item.Categories[i], tokens)
+// cacheInfoItem returns the cache info of the feed, or the cache info of a
+// feed that was never fetched.
+func cacheInfoItem(cacheInfo map[string]*storage.CacheInfoItem, url string) *storage.CacheInfoItem {
+	if ci := cacheInfo[url]; ci != nil {
+		return ci
+	}
+	return &storage.CacheInfoItem{
+		URL:        url,
+		LastFetch:  time.Unix(0, 0),
+		FetchAfter: time.Unix(0, 0),
+	}
+}
+
+// selectLists returns the lists matching opts.Lists, or all lists, without
+// the ones matching opts.ExcludeLists. Matching a list also matches its
+// nested lists.
+func (f *TerminalFeed) selectLists(opts *FeedOptions) ([]string, error) {
+	all, err := f.storage.LoadLists()
+	if err != nil {
+		return nil, utils.NewInternalError("failed to load lists: " + err.Error())
+	}
+	if len(opts.Lists) == 0 && len(all) == 0 {
+		return nil, utils.NewInternalError("no feeds to display")
+	}
+	config, err := f.storage.LoadConfig()
+	if err != nil {
+		return nil, utils.NewInternalError("failed to load config: " + err.Error())
+	}
+	for _, pattern := range slices.Concat(opts.Lists, opts.ExcludeLists) {
+		if _, err := path.Match(pattern, ""); err != nil {
+			return nil, utils.NewInternalError("invalid list pattern: " + pattern)
+		}
+		// saved searches are selected with opts.Searches
+		if config.SavedSearches[pattern] != nil && !slices.Contains(all, pattern) {
+			if slices.Contains(opts.ExcludeLists, pattern) {
+				return nil, utils.NewInternalError(pattern + " is a saved search and cannot be excluded")
+			}
+			return nil, utils.NewInternalError(pattern + " is a saved search and cannot be used here")
+		}
+	}
+	lists := make([]string, 0)
+	if len(opts.Lists) == 0 {
+		lists = all
+	}
+	for _, pattern := range opts.Lists {
+		matched := false
+		for _, list := range all {
+			if matchList(pattern, list) {
+				matched = true
+				if !slices.Contains(lists, list) {
+					lists = append(lists, list)
+				}
+			}
+		}
+		if !matched && !isListPattern(pattern) && !slices.Contains(lists, pattern) {
+			lists = append(lists, pattern)
+		}
+	}
+	for _, pattern := range opts.ExcludeLists {
+		lists = slices.DeleteFunc(lists, func(list string) bool {
+			return matchList(pattern, list)
+		})
+	}
+	return lists, nil
+}
+
+// feedSelection holds the feeds of a run. The items of listFeeds are
+// displayed, as well as the items matching each saved search.
+type feedSelection struct {
+	feeds     map[string]*storage.ListItem
+	feedLists map[string][]string // the lists of each feed
+	listFeeds map[string]struct{} // nil if only saved searches are displayed
+	searches  []*feedSearch
+}
+
+type feedSearch struct {
+	*SearchOptions
+	query *search.Query
+	feeds map[string]struct{}
+}
+
+func (f *TerminalFeed) selectFeeds(opts *FeedOptions, analyzer *utils.Analyzer) (*feedSelection, error) {
+	sel := &feedSelection{
+		feeds:     make(map[string]*storage.ListItem),
+		feedLists: make(map[string][]string),
+	}
+	if len(opts.Lists) > 0 || len(opts.Searches) == 0 {
+		lists, err := f.selectLists(opts)
+		if err != nil {
+			return nil, err
+		}
+		sel.listFeeds = f.addListFeeds(sel, lists)
+	}
+	for _, s := range opts.Searches {
+		query, err := search.ParseQuery(s.Query, analyzer)
+		if err != nil {
+			return nil, utils.NewInternalError("invalid query of saved search " + s.Name + ": " + err.Error())
+		}
+		query.Fuzzy = s.Fuzzy
+		lists, err := f.selectLists(&FeedOptions{
+			Lists:        s.Lists,
+			ExcludeLists: opts.ExcludeLists,
+		})
+		if err != nil {
+			return nil, err
+		}
+		sel.searches = append(sel.searches, &feedSearch{
+			SearchOptions: s,
+			query:         query,
+			feeds:         f.addListFeeds(sel, lists),
+		})
+	}
+	return sel, nil
+}
+
+// addListFeeds adds the feeds of the lists to the selection and returns them.
+func (f *TerminalFeed) addListFeeds(sel *feedSelection, lists []string) map[string]struct{} {
+	added := make(map[string]struct{})
+	for i := range lists {
+		m := make(map[string]*storage.ListItem)
+		f.storage.LoadFeedsFromList(m, lists[i])
+		for url, item := range m {
+			if _, ok := sel.feeds[url]; !ok {
+				sel.feeds[url] = item
+			}
+			if !slices.Contains(sel.feedLists[url], lists[i]) {
+				sel.feedLists[url] = append(sel.feedLists[url], lists[i])
+			}
+			added[url] = struct{}{}
+		}
+	}
+	return added
+}
+
+// since returns the date of the oldest items to display.
+func (sel *feedSelection) since(opts *FeedOptions) time.Time {
+	dates := make([]time.Time, 0, len(sel.searches)+1)
+	if sel.listFeeds != nil {
+		dates = append(dates, opts.Since)
+	}
+	for _, s := range sel.searches {
+		dates = append(dates, s.Since)
+	}
+	return slices.MinFunc(dates, time.Time.Compare)
+}
+
+// matchList reports whether the pattern matches the list or one of its
+// parents.
+func matchList(pattern, list string) bool {
+	for {
+		if ok, _ := path.Match(pattern, list); ok {
+			return true
+		}
+		i := strings.LastIndex(list, storage.ListSeparator)
+		if i < 0 {
+			return false
+		}
+		list = list[:i]
 	}
-	return tokens
+}
+
+func isListPattern(s string) bool {
+	return strings.ContainsAny(s, "*?[")
 }
 
 func (f *TerminalFeed) parseFeed(url string) (*gofeed.Feed, error) {
 	if f.storage.HasParsedFeedCache(url) {
 		return f.storage.LoadParsedFeedCache(url)
 	}
+	return f.parseRawFeed(url)
+}
+
+func (f *TerminalFeed) parseRawFeed(url string) (*gofeed.Feed, error) {
 	fc, err := f.storage.OpenFeedCache(url)
 	if err != nil {
 		return nil, err
@@ -439,19 +959,49 @@ func (f *TerminalFeed) parseFeed(url string) (*gofeed.Feed, error) {
 	return f.parser.Parse(fc)
 }
 
+// withArchivedItems returns a copy of the feed that also includes archived
+// items which are no longer published by the feed.
+func (f *TerminalFeed) withArchivedItems(feed *gofeed.Feed, url string) *gofeed.Feed {
+	archive, err := f.storage.LoadArchive(url)
+	if err != nil {
+		return feed
+	}
+	return withArchive(feed, archive)
+}
+
+func withArchive(feed *gofeed.Feed, archive []*storage.ArchiveItem) *gofeed.Feed {
+	if len(archive) == 0 {
+		return feed
+	}
+	seen := make(map[string]struct{}, len(feed.Items))
+	for _, item := range feed.Items {
+		seen[storage.ItemID(item)] = struct{}{}
+	}
+	c := *feed
+	c.Items = slices.Clone(feed.Items)
+	for _, item := range archive {
+		if _, ok := seen[item.ID]; ok || item.Item == nil {
+			continue
+		}
+		c.Items = append(c.Items, item.Item)
+	}
+	return &c
+}
+
 func (f *TerminalFeed) processFeedItems(
 	feed *gofeed.Feed,
 	items []*FeedItem,
 	config *storage.Config,
-	opts *FeedOptions,
+	theme *Theme,
+	since time.Time,
 	summary *RunSummary,
-	feedColorMap map[string]uint8,
+	feedColorMap map[string]Color,
 	ci *storage.CacheInfoItem,
 ) []*FeedItem {
 	summary.ItemsCount += len(feed.Items)
 	color, ok := feedColorMap[feed.Title]
 	if !ok {
-		color = mapColor(uint8(len(feedColorMap)%256), config)
+		color = theme.FeedColor(len(feedColorMap))
 		feedColorMap[feed.Title] = color
 	}
 	currentTime := f.time.Now()
@@ -459,31 +1009,25 @@ func (f *TerminalFeed) processFeedItems(
 		if feedItem.PublishedParsed == nil {
 			feedItem.PublishedParsed = &time.Time{}
 		}
-		if !opts.Since.IsZero() && feedItem.PublishedParsed.Before(opts.Since) {
+		if !since.IsZero() && feedItem.PublishedParsed.Before(since) {
 			continue
 		}
 		if config.HideFutureItems && feedItem.PublishedParsed.After(currentTime) {
 			continue
 		}
-		score := 0
-		if len(opts.Query) > 0 {
-			score = utils.Score(opts.Query, f.tokenizeItem(feedItem))
-		}
-		if score == -1 {
-			continue
-		}
 		items = append(items, &FeedItem{
 			Feed:      feed,
+			FeedURL:   ci.URL,
 			Item:      feedItem,
 			FeedColor: color,
 			IsNew:     feedItem.PublishedParsed.After(ci.LastFetch),
-			Score:     score,
 		})
 	}
 	return items
 }
 
 type FetchResult struct {
+	Status     int // 0 if no request was made
 	Changed    bool
 	ETag       string
 	FetchAfter time.Time
@@ -517,12 +1061,14 @@ func (f *TerminalFeed) fetchFeed(feed *storage.CacheInfoItem, config *storage.Co
 	defer res.Body.Close()
 	if res.StatusCode == http.StatusNotModified {
 		return &FetchResult{
+			Status:     res.StatusCode,
 			Changed:    false,
 			FetchAfter: f.time.Now().Add(parseMaxAge(res.Header.Get("Cache-Control"))),
 		}, nil
 	}
 	if res.StatusCode == http.StatusTooManyRequests || res.StatusCode == http.StatusServiceUnavailable {
 		return &FetchResult{
+			Status:     res.StatusCode,
 			Changed:    false,
 			FetchAfter: f.parseRetryAfter(res.Header.Get("Retry-After")),
 		}, nil
@@ -542,6 +1088,7 @@ func (f *TerminalFeed) fetchFeed(feed *storage.CacheInfoItem, config *storage.Co
 	}
 	err = f.storage.SaveFeedCache(bodyReader, feed.URL)
 	return &FetchResult{
+		Status:     res.StatusCode,
 		Changed:    true,
 		ETag:       res.Header.Get("ETag"),
 		FetchAfter: f.time.Now().Add(parseMaxAge(res.Header.Get("Cache-Control"))),
@@ -610,10 +1157,3 @@ func parseMaxAge(cacheControl string) time.Duration {
 	}
 	return 60 * time.Second
 }
-
-func mapColor(color uint8, config *storage.Config) uint8 {
-	if c, ok := config.ColorMap[color]; ok {
-		return c
-	}
-	return color
-}
@@ -0,0 +1,150 @@
+package internal
+
+import (
+	"bytes"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/radulucut/cleed/internal/storage"
+	"github.com/radulucut/cleed/mocks"
+	"github.com/stretchr/testify/assert"
+	"go.uber.org/mock/gomock"
+)
+
+var (
+	defaultCurrentTime = time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+)
+
+func newTestFeed(t *testing.T) (*TerminalFeed, *storage.MemoryStorage, *bytes.Buffer) {
+	ctrl := gomock.NewController(t)
+	timeMock := mocks.NewMockTime(ctrl)
+	timeMock.EXPECT().Now().Return(defaultCurrentTime).AnyTimes()
+
+	out := new(bytes.Buffer)
+	printer := NewPrinter(nil, out, out)
+	s := storage.NewMemoryStorage(timeMock)
+	err := s.Init("0.1.0")
+	if err != nil {
+		t.Fatal(err)
+	}
+	return NewTerminalFeed(timeMock, printer, s), s, out
+}
+
+func Test_Follow_Unfollow(t *testing.T) {
+	feed, s, out := newTestFeed(t)
+
+	err := feed.Follow([]string{"https://example.com/feed", "https://test.com/rss"}, "mylist")
+	assert.NoError(t, err)
+	assert.Equal(t, "added 2 feeds to list: mylist\n", out.String())
+
+	items, err := s.GetFeedsFromList("mylist")
+	assert.NoError(t, err)
+	assert.Equal(t, []*storage.ListItem{
+		{AddedAt: time.Unix(defaultCurrentTime.Unix(), 0), Address: "https://example.com/feed"},
+		{AddedAt: time.Unix(defaultCurrentTime.Unix(), 0), Address: "https://test.com/rss"},
+	}, items)
+
+	out.Reset()
+	err = feed.Unfollow([]string{"https://example.com/feed", "https://missing.com"}, "mylist")
+	assert.NoError(t, err)
+	assert.Equal(t, `https://example.com/feed was removed from the list
+https://missing.com was not found in the list
+`, out.String())
+
+	out.Reset()
+	err = feed.ListFeeds("mylist")
+	assert.NoError(t, err)
+	assert.Equal(t, `2024-01-01 00:00:00  https://test.com/rss
+Total: 1 feed
+`, out.String())
+
+	// feeds can be unfollowed with the URL as it was typed before it was
+	// canonicalized
+	out.Reset()
+	err = feed.Follow([]string{"HTTP://Example.com:80/feed?utm_source=x"}, "mylist")
+	assert.NoError(t, err)
+	err = feed.Unfollow([]string{"HTTP://Example.com:80/feed?utm_source=x"}, "mylist")
+	assert.NoError(t, err)
+	assert.Equal(t, `added 1 feed to list: mylist
+HTTP://Example.com:80/feed?utm_source=x was removed from the list
+`, out.String())
+
+	items, err = s.GetFeedsFromList("mylist")
+	assert.NoError(t, err)
+	assert.Len(t, items, 1)
+}
+
+func Test_Lists_Merge_Rename(t *testing.T) {
+	feed, s, out := newTestFeed(t)
+
+	err := feed.Follow([]string{"https://example.com/feed"}, "a")
+	assert.NoError(t, err)
+	err = feed.Follow([]string{"https://test.com/rss"}, "b")
+	assert.NoError(t, err)
+	err = feed.PauseList("a")
+	assert.NoError(t, err)
+	err = feed.PauseList("b")
+	assert.NoError(t, err)
+
+	out.Reset()
+	err = feed.MergeLists("a", "b")
+	assert.NoError(t, err)
+	err = feed.RenameList("a", "c")
+	assert.NoError(t, err)
+
+	out.Reset()
+	err = feed.Lists()
+	assert.NoError(t, err)
+	assert.Equal(t, "c (paused)\n", out.String())
+
+	config, err := s.LoadConfig()
+	assert.NoError(t, err)
+	assert.Equal(t, []string{"c"}, config.PausedLists)
+}
+
+func Test_Lists_Merge_Undo(t *testing.T) {
+	feed, _, out := newTestFeed(t)
+
+	err := feed.Follow([]string{"https://example.com/feed"}, "a")
+	assert.NoError(t, err)
+	err = feed.Follow([]string{"https://test.com/rss"}, "b")
+	assert.NoError(t, err)
+	err = feed.MergeLists("a", "b")
+	assert.NoError(t, err)
+
+	out.Reset()
+	err = feed.Undo(false)
+	assert.NoError(t, err)
+	assert.Equal(t, "undone: merge b into a\n", out.String())
+
+	out.Reset()
+	err = feed.Lists()
+	assert.NoError(t, err)
+	assert.Equal(t, "a\nb\n", out.String())
+
+	out.Reset()
+	err = feed.ListFeeds("a")
+	assert.NoError(t, err)
+	assert.Equal(t, `2024-01-01 00:00:00  https://example.com/feed
+Total: 1 feed
+`, out.String())
+
+	out.Reset()
+	err = feed.Undo(false)
+	assert.NoError(t, err)
+	assert.Equal(t, "nothing to undo\n", out.String())
+}
+
+func Test_Lists_Storage_Error(t *testing.T) {
+	ctrl := gomock.NewController(t)
+
+	storageMock := mocks.NewMockStorage(ctrl)
+	storageMock.EXPECT().LoadLists().Return(nil, errors.New("disk error"))
+
+	out := new(bytes.Buffer)
+	feed := NewTerminalFeed(mocks.NewMockTime(ctrl), NewPrinter(nil, out, out), storageMock)
+
+	err := feed.Lists()
+	assert.EqualError(t, err, "failed to list lists: disk error")
+}
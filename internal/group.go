@@ -0,0 +1,107 @@
+package internal
+
+import (
+	"cmp"
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/radulucut/cleed/internal/storage"
+)
+
+type itemGroup struct {
+	Key   string
+	Name  string
+	Items []*FeedItem // items to display
+	Total int         // number of items in the group, including hidden ones
+	New   int
+}
+
+type groupKey struct {
+	Key  string
+	Name string
+}
+
+// collapsed reports whether the items of the group are hidden because none
+// of them is new.
+func (g *itemGroup) collapsed(opts *FeedOptions) bool {
+	return opts.GroupBy != "" && opts.Collapse && g.New == 0
+}
+
+// groupItems splits the sorted items into groups, keeping at most
+// opts.PerGroup items per group and opts.Limit items in total. Items that
+// belong to several lists or categories are part of each group. Groups are
+// sorted by name, or by date when grouping by day.
+func groupItems(items []*FeedItem, opts *FeedOptions) []*itemGroup {
+	groups := make([]*itemGroup, 0)
+	byKey := make(map[string]*itemGroup)
+	shown := 0
+	for _, fi := range items {
+		for _, key := range groupKeys(fi, opts.GroupBy) {
+			g, ok := byKey[key.Key]
+			if !ok {
+				g = &itemGroup{Key: key.Key, Name: key.Name}
+				byKey[key.Key] = g
+				groups = append(groups, g)
+			}
+			g.Total++
+			if fi.IsNew {
+				g.New++
+			}
+			if opts.PerGroup > 0 && len(g.Items) >= opts.PerGroup {
+				continue
+			}
+			if opts.Limit > 0 && shown >= opts.Limit {
+				continue
+			}
+			g.Items = append(g.Items, fi)
+			shown++
+		}
+	}
+	slices.SortFunc(groups, func(a, b *itemGroup) int {
+		switch opts.GroupBy {
+		case GroupByDay:
+			return strings.Compare(a.Key, b.Key)
+		case GroupByList:
+			return storage.CompareLists(a.Key, b.Key)
+		}
+		if c := strings.Compare(strings.ToLower(a.Name), strings.ToLower(b.Name)); c != 0 {
+			return c
+		}
+		return strings.Compare(a.Key, b.Key)
+	})
+	return groups
+}
+
+// groupKeys returns the groups the item belongs to.
+func groupKeys(fi *FeedItem, groupBy string) []groupKey {
+	switch groupBy {
+	case GroupByFeed:
+		return []groupKey{{Key: fi.FeedURL, Name: cmp.Or(strings.TrimSpace(fi.Feed.Title), fi.FeedURL)}}
+	case GroupByList:
+		keys := make([]groupKey, 0, len(fi.Lists))
+		for _, list := range fi.Lists {
+			keys = append(keys, groupKey{Key: list, Name: list})
+		}
+		return keys
+	case GroupByDay:
+		day := fi.Item.PublishedParsed.In(time.Local).Format(time.DateOnly)
+		return []groupKey{{Key: day, Name: day}}
+	case GroupByCategory:
+		keys := make([]groupKey, 0, len(fi.Item.Categories))
+		for _, category := range fi.Item.Categories {
+			category = strings.TrimSpace(category)
+			key := strings.ToLower(category)
+			if category != "" && !slices.ContainsFunc(keys, func(k groupKey) bool {
+				return k.Key == key
+			}) {
+				keys = append(keys, groupKey{Key: key, Name: category})
+			}
+		}
+		if len(keys) == 0 {
+			keys = append(keys, groupKey{Key: "", Name: "uncategorized"})
+		}
+		return keys
+	}
+	return nil
+}
@@ -2,13 +2,17 @@ package internal
 
 import (
 	"bufio"
+	"bytes"
+	"cmp"
 	"encoding/xml"
 	"fmt"
 	"io"
 	"os"
+	"slices"
 	"strings"
 	"time"
 
+	"github.com/radulucut/cleed/internal/storage"
 	"github.com/radulucut/cleed/internal/utils"
 )
 
@@ -30,11 +34,11 @@ func (f *TerminalFeed) ImportFromFile(path, list string) error {
 		}
 		urls = append(urls, line)
 	}
-	err = f.storage.AddToList(urls, list)
+	added, err := f.addToList(urls, list)
 	if err != nil {
 		return utils.NewInternalError("failed to save feeds: " + err.Error())
 	}
-	f.printer.Printf("added %s to list: %s\n", utils.Pluralize(int64(len(urls)), "feed"), list)
+	f.printer.Printf("added %s to list: %s\n", utils.Pluralize(int64(added), "feed"), list)
 	return nil
 }
 
@@ -98,60 +102,46 @@ func (f *TerminalFeed) writeOPML(fo io.Writer, list string, cachedOnly bool) (*O
 	fmt.Fprintf(fo, "    <dateCreated>%s</dateCreated>\n", f.time.Now().Format(time.RFC1123Z))
 	fmt.Fprint(fo, "  </head>\n  <body>\n")
 
-	var err error
-	lists := make([]string, 0)
+	lists, err := f.storage.LoadLists()
+	if err != nil {
+		return nil, utils.NewInternalError("failed to load lists: " + err.Error())
+	}
 	if list == "" {
-		lists, err = f.storage.LoadLists()
-		if err != nil {
-			return nil, utils.NewInternalError("failed to load lists: " + err.Error())
-		}
 		if len(lists) == 0 {
 			lists = append(lists, "default")
 		}
 	} else {
-		lists = append(lists, list)
+		lists = slices.DeleteFunc(lists, func(l string) bool {
+			return !storage.IsInList(l, list)
+		})
+		if !slices.Contains(lists, list) {
+			lists = append(lists, list)
+		}
+	}
+	slices.SortFunc(lists, storage.CompareLists)
+
+	// an exported list is the root folder, its nested lists are subfolders
+	root := &listOutline{}
+	for _, l := range lists {
+		var path []string
+		if list == "" {
+			path = strings.Split(l, storage.ListSeparator)
+		} else {
+			path = []string{list}
+			if l != list {
+				path = append(path, strings.Split(strings.TrimPrefix(l, list+storage.ListSeparator), storage.ListSeparator)...)
+			}
+		}
+		root.add(path, l)
 	}
 
 	feedCount := int64(0)
-	for i := range lists {
-		feeds, err := f.storage.GetFeedsFromList(lists[i])
+	for _, child := range root.children {
+		n, err := f.writeListOutline(fo, child, 4, cachedOnly)
 		if err != nil {
-			return nil, utils.NewInternalError("failed to list feeds: " + err.Error())
+			return nil, err
 		}
-		if len(feeds) == 0 {
-			continue
-		}
-		fmt.Fprint(fo, "    <outline text=\"")
-		xml.EscapeText(fo, []byte(lists[i]))
-		fmt.Fprint(fo, "\">\n")
-		for _, item := range feeds {
-			feed, err := f.parseFeed(item.Address)
-			if cachedOnly && feed == nil {
-				continue
-			}
-			fmt.Fprint(fo, "      <outline")
-			if err == nil {
-				if feed.Title != "" {
-					fmt.Fprint(fo, " text=\"")
-					xml.EscapeText(fo, []byte(strings.TrimSpace(feed.Title)))
-					fmt.Fprint(fo, "\"")
-				}
-				if feed.Description != "" {
-					feed.Description = strings.TrimSpace(feed.Description)
-					if len(feed.Description) > 200 {
-						feed.Description = feed.Description[:200] + "..."
-					}
-					fmt.Fprint(fo, " description=\"")
-					xml.EscapeText(fo, []byte(feed.Description))
-					fmt.Fprint(fo, "\"")
-				}
-			}
-			fmt.Fprint(fo, " xmlUrl=\"")
-			xml.EscapeText(fo, []byte(item.Address))
-			fmt.Fprint(fo, "\" />\n")
-			feedCount++
-		}
-		fmt.Fprint(fo, "    </outline>\n")
+		feedCount += n
 	}
 	fmt.Fprint(fo, "  </body>\n</opml>")
 
@@ -161,31 +151,138 @@ func (f *TerminalFeed) writeOPML(fo io.Writer, list string, cachedOnly bool) (*O
 	}, nil
 }
 
+type listOutline struct {
+	text     string
+	list     string // empty if there is no list for this folder
+	children []*listOutline
+}
+
+func (o *listOutline) add(path []string, list string) {
+	if len(path) == 0 {
+		o.list = list
+		return
+	}
+	for _, child := range o.children {
+		if child.text == path[0] {
+			child.add(path[1:], list)
+			return
+		}
+	}
+	child := &listOutline{text: path[0]}
+	o.children = append(o.children, child)
+	child.add(path[1:], list)
+}
+
+// writeListOutline writes a list and its nested lists as folders. Lists
+// without feeds are skipped.
+func (f *TerminalFeed) writeListOutline(fo io.Writer, o *listOutline, indent int, cachedOnly bool) (int64, error) {
+	b := new(bytes.Buffer)
+	feedCount := int64(0)
+	if o.list != "" {
+		feeds, err := f.storage.GetFeedsFromList(o.list)
+		if err != nil {
+			return 0, utils.NewInternalError("failed to list feeds: " + err.Error())
+		}
+		for _, item := range feeds {
+			if f.writeFeedOutline(b, item.Address, indent+2, cachedOnly) {
+				feedCount++
+			}
+		}
+	}
+	for _, child := range o.children {
+		n, err := f.writeListOutline(b, child, indent+2, cachedOnly)
+		if err != nil {
+			return 0, err
+		}
+		feedCount += n
+	}
+	if b.Len() == 0 {
+		return 0, nil
+	}
+	pad := strings.Repeat(" ", indent)
+	fmt.Fprint(fo, pad+"<outline text=\"")
+	xml.EscapeText(fo, []byte(o.text))
+	fmt.Fprint(fo, "\">\n")
+	b.WriteTo(fo)
+	fmt.Fprint(fo, pad+"</outline>\n")
+	return feedCount, nil
+}
+
+func (f *TerminalFeed) writeFeedOutline(fo io.Writer, address string, indent int, cachedOnly bool) bool {
+	feed, err := f.parseFeed(address)
+	if cachedOnly && feed == nil {
+		return false
+	}
+	fmt.Fprint(fo, strings.Repeat(" ", indent)+"<outline")
+	if err == nil {
+		if feed.Title != "" {
+			fmt.Fprint(fo, " text=\"")
+			xml.EscapeText(fo, []byte(strings.TrimSpace(feed.Title)))
+			fmt.Fprint(fo, "\"")
+		}
+		if feed.Description != "" {
+			feed.Description = strings.TrimSpace(feed.Description)
+			if len(feed.Description) > 200 {
+				feed.Description = feed.Description[:200] + "..."
+			}
+			fmt.Fprint(fo, " description=\"")
+			xml.EscapeText(fo, []byte(feed.Description))
+			fmt.Fprint(fo, "\"")
+		}
+	}
+	fmt.Fprint(fo, " xmlUrl=\"")
+	xml.EscapeText(fo, []byte(address))
+	fmt.Fprint(fo, "\" />\n")
+	return true
+}
+
+// importOPML adds the feeds of each top level folder to a list named after
+// the folder, or to list if set. Nested folders are imported as nested lists.
+// A list separator in a folder name is replaced, so that it does not nest.
 func (f *TerminalFeed) importOPML(opml *utils.OPML, list string) error {
 	if len(opml.Body.Outltines) == 0 {
 		return utils.NewInternalError("no feeds found in OPML")
 	}
-	for _, listOutline := range opml.Body.Outltines {
-		urls := make([]string, 0, len(listOutline.Outlines))
-		for _, feedOutline := range listOutline.Outlines {
-			urls = append(urls, feedOutline.XMLURL)
+	feeds := make(map[string][]string)
+	lists := make([]string, 0)
+	var collect func(outlines []*utils.OPMLOutline, listName string)
+	collect = func(outlines []*utils.OPMLOutline, listName string) {
+		for _, outline := range outlines {
+			if outline.XMLURL != "" {
+				if _, ok := feeds[listName]; !ok {
+					lists = append(lists, listName)
+				}
+				feeds[listName] = append(feeds[listName], outline.XMLURL)
+				continue
+			}
+			name := listName
+			if outline.Text != "" {
+				name += storage.ListSeparator + folderListName(outline.Text)
+			}
+			collect(outline.Outlines, name)
 		}
-		if len(urls) == 0 {
+	}
+	for _, outline := range opml.Body.Outltines {
+		listName := cmp.Or(list, "default")
+		if outline.XMLURL != "" {
+			collect([]*utils.OPMLOutline{outline}, listName)
 			continue
 		}
-		listName := list
-		if list == "" {
-			if listOutline.Text != "" {
-				listName = listOutline.Text
-			} else {
-				listName = "default"
-			}
+		if list == "" && outline.Text != "" {
+			listName = folderListName(outline.Text)
 		}
-		err := f.storage.AddToList(urls, listName)
+		collect(outline.Outlines, listName)
+	}
+	for _, listName := range lists {
+		added, err := f.addToList(feeds[listName], listName)
 		if err != nil {
 			return utils.NewInternalError("failed to save feeds: " + err.Error())
 		}
-		f.printer.Printf("added %s to list: %s\n", utils.Pluralize(int64(len(urls)), "feed"), listName)
+		f.printer.Printf("added %s to list: %s\n", utils.Pluralize(int64(added), "feed"), listName)
 	}
 	return nil
 }
+
+func folderListName(text string) string {
+	return strings.ReplaceAll(text, storage.ListSeparator, "-")
+}
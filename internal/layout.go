@@ -0,0 +1,355 @@
+package internal
+
+import (
+	"cmp"
+	"fmt"
+	"math"
+	"slices"
+	"strconv"
+	"strings"
+	"text/template"
+	"time"
+
+	"github.com/mattn/go-runewidth"
+	"github.com/radulucut/cleed/internal/storage"
+	"github.com/radulucut/cleed/internal/utils"
+)
+
+const (
+	LayoutCompact  = "compact"
+	LayoutDefault  = "default"
+	LayoutDetailed = "detailed"
+)
+
+var Layouts = []string{LayoutCompact, LayoutDefault, LayoutDetailed}
+
+const (
+	// summaryMaxLines and summaryMaxWidth limit the summary excerpt in the
+	// detailed layout.
+	summaryMaxLines = 3
+	summaryMaxWidth = 280
+	// minTitleWidth is the minimum width of titles when wrapping or
+	// truncating them.
+	minTitleWidth = 20
+)
+
+// ItemView is the data available to user-defined templates.
+type ItemView struct {
+	Feed       string
+	FeedURL    string
+	FeedColor  Color
+	Title      string
+	Link       string
+	Author     string
+	Summary    string // description without HTML
+	Published  time.Time
+	Relative   string // e.g. 2 hours ago
+	Categories []string
+	Lists      []string
+	Sources    []string // other feeds with the same item
+	Enclosures []*Enclosure
+	New        bool
+	ID         string // GUID or link, as accepted by cleed open
+	Index      int    // position in the timeline where 1 is the newest item, as accepted by cleed open. 0 in search results
+}
+
+type itemLayout struct {
+	name     string
+	template *template.Template
+	feed     *TerminalFeed
+	printer  *Printer
+	opts     *FeedOptions
+
+	width     int // terminal width
+	column    int // width of the feed title and date column
+	showLists bool
+	theme     *Theme
+}
+
+// newItemLayout returns the layout selected with opts.Layout or in the
+// config. A layout is either a built-in one or a template from the config.
+func (f *TerminalFeed) newItemLayout(opts *FeedOptions, config *storage.Config) (*itemLayout, error) {
+	name := cmp.Or(opts.Layout, config.Layout, LayoutDefault)
+	layout := &itemLayout{
+		name:    name,
+		feed:    f,
+		printer: f.printer,
+		opts:    opts,
+		theme:   configTheme(config),
+	}
+	if slices.Contains(Layouts, name) {
+		return layout, nil
+	}
+	text, ok := config.Templates[name]
+	if !ok {
+		return nil, utils.NewInternalError("layout not found: " + name + ". Use " + strings.Join(Layouts, ", ") + " or a template name")
+	}
+	tmpl, err := parseItemTemplate(name, text, f.printer, layout.theme)
+	if err != nil {
+		return nil, utils.NewInternalError("failed to parse template: " + err.Error())
+	}
+	layout.template = tmpl
+	return layout, nil
+}
+
+func parseItemTemplate(name, text string, printer *Printer, theme *Theme) (*template.Template, error) {
+	return template.New(name).Funcs(template.FuncMap{
+		"truncate": func(n int, s string) string {
+			return runewidth.Truncate(s, n, "...")
+		},
+		"pad": func(n int, s string) string {
+			return runewidth.FillRight(s, n)
+		},
+		"join": func(sep string, s []string) string {
+			return strings.Join(s, sep)
+		},
+		"color": func(color any, s string) (string, error) {
+			c, err := templateColor(color, theme)
+			if err != nil {
+				return "", err
+			}
+			return printer.Color(s, c), nil
+		},
+		"hyperlink": func(url, s string) string {
+			return printer.Hyperlink(s, url)
+		},
+	}).Parse(text)
+}
+
+// prepare sets the column widths for the items to display.
+func (l *itemLayout) prepare(items []*FeedItem) {
+	l.width, _ = l.printer.GetSize()
+	l.column = 0
+	for _, fi := range items {
+		l.column = max(l.column, runewidth.StringWidth(fi.Feed.Title), len(fi.PublishedRelative))
+	}
+	l.column = min(l.column, 30)
+	if l.width != math.MaxInt {
+		l.column = min(l.column, max(10, l.width/4))
+	}
+}
+
+// groupSeparator is printed after group headers and between groups.
+func (l *itemLayout) groupSeparator() string {
+	if l.name == LayoutCompact || l.template != nil {
+		return ""
+	}
+	return "\n"
+}
+
+func (l *itemLayout) printItem(fi *FeedItem) error {
+	switch {
+	case l.template != nil:
+		return l.printTemplate(fi)
+	case l.name == LayoutCompact:
+		l.printCompact(fi)
+	default:
+		l.printDefault(fi, l.name == LayoutDetailed)
+	}
+	return nil
+}
+
+func (l *itemLayout) newMark(fi *FeedItem) string {
+	if !fi.IsNew {
+		return ""
+	}
+	return l.printer.Color("• ", l.theme.New)
+}
+
+// title returns the title, with the search terms highlighted, as a hyperlink
+// to the item.
+func (l *itemLayout) title(s string, fi *FeedItem) string {
+	if l.opts.Query != nil {
+		s = l.feed.highlight(s, fi.Highlights, l.opts.Query.Analyzer(), l.theme.Highlight, l.theme.ItemTitle)
+	} else {
+		s = l.printer.Color(s, l.theme.ItemTitle)
+	}
+	return l.printer.Hyperlink(s, itemLink(fi))
+}
+
+// printCompact prints the item on a single line. The link is printed after
+// the date unless the title can be clicked instead.
+func (l *itemLayout) printCompact(fi *FeedItem) {
+	title := fi.Item.Title
+	newMarkWidth := 0
+	if fi.IsNew {
+		newMarkWidth = 2
+	}
+	link := ""
+	if !l.printer.GetHyperlinks() {
+		link = itemLink(fi)
+	}
+	available := l.width - l.column - newMarkWidth - len(fi.PublishedRelative) - 4
+	if link != "" {
+		available -= runewidth.StringWidth(link) + 2
+	}
+	title = runewidth.Truncate(title, max(available, minTitleWidth), "...")
+	l.printer.Print(
+		l.printer.Color(runewidth.FillRight(runewidth.Truncate(fi.Feed.Title, l.column, "..."), l.column), fi.FeedColor),
+		"  ",
+		l.newMark(fi)+l.title(title, fi),
+		"  ",
+		l.printer.Color(fi.PublishedRelative, l.theme.Date),
+	)
+	if link != "" {
+		l.printer.Print("  ", l.printer.Color(link, l.theme.Link))
+	}
+	l.printer.Print("\n")
+}
+
+// printDefault prints the feed title and the item title on the first line and
+// the date and the link on the second one. The detailed layout adds the
+// author and an excerpt of the summary.
+func (l *itemLayout) printDefault(fi *FeedItem, detailed bool) {
+	indent := strings.Repeat(" ", l.column+2)
+	newMarkWidth := 0
+	if fi.IsNew {
+		newMarkWidth = 2
+	}
+	lines := utils.Wrap(fi.Item.Title, max(l.width-l.column-2-newMarkWidth, minTitleWidth))
+	l.printer.Print(
+		l.printer.Color(runewidth.FillRight(runewidth.Truncate(fi.Feed.Title, l.column, "..."), l.column), fi.FeedColor),
+		"  ",
+		l.newMark(fi)+l.title(lines[0], fi),
+		"\n",
+	)
+	for _, line := range lines[1:] {
+		l.printer.Print(indent, strings.Repeat(" ", newMarkWidth), l.title(line, fi), "\n")
+	}
+	// the link is only hidden when the title can be clicked instead
+	if l.opts.HideLinks && l.printer.GetHyperlinks() {
+		l.printer.Print(l.printer.Color(fi.PublishedRelative, l.theme.Date))
+	} else {
+		l.printer.Print(
+			l.printer.Color(runewidth.FillRight(fi.PublishedRelative, l.column), l.theme.Date),
+			"  ",
+			l.printer.Color(itemLink(fi), l.theme.Link),
+		)
+	}
+	if l.showLists {
+		l.printer.Print("  ", l.printer.Color("("+strings.Join(fi.Lists, ", ")+")", l.theme.Secondary))
+	}
+	l.printer.Print("\n")
+	for _, e := range itemEnclosures(fi.Item) {
+		l.printer.Print(indent, l.printer.Color("["+e.String()+"]", l.theme.Secondary), "\n")
+	}
+	if sources := itemSources(fi); len(sources) > 0 {
+		l.printer.Print(indent, l.printer.Color("also in "+strings.Join(sources, ", "), l.theme.Secondary), "\n")
+	}
+	if detailed {
+		if author := itemAuthor(fi); author != "" {
+			l.printer.Print(indent, l.printer.Color("by "+author, l.theme.Secondary), "\n")
+		}
+		excerpt := runewidth.Truncate(itemSummary(fi), summaryMaxWidth, "...")
+		summary := utils.Wrap(excerpt, max(l.width-l.column-2, minTitleWidth))
+		if len(summary) > summaryMaxLines {
+			summary = summary[:summaryMaxLines]
+			summary[summaryMaxLines-1] += "..."
+		}
+		for _, line := range summary {
+			if line != "" {
+				l.printer.Print(indent, line, "\n")
+			}
+		}
+	}
+}
+
+func (l *itemLayout) printTemplate(fi *FeedItem) error {
+	view := &ItemView{
+		Feed:       fi.Feed.Title,
+		FeedURL:    fi.FeedURL,
+		FeedColor:  fi.FeedColor,
+		Title:      fi.Item.Title,
+		Link:       itemLink(fi),
+		Author:     itemAuthor(fi),
+		Summary:    itemSummary(fi),
+		Published:  *fi.Item.PublishedParsed,
+		Relative:   fi.PublishedRelative,
+		Categories: fi.Item.Categories,
+		Lists:      fi.Lists,
+		Sources:    itemSources(fi),
+		Enclosures: itemEnclosures(fi.Item),
+		New:        fi.IsNew,
+		ID:         storage.ItemID(fi.Item),
+		Index:      fi.Index,
+	}
+	b := strings.Builder{}
+	err := l.template.Execute(&b, view)
+	if err != nil {
+		return utils.NewInternalError("failed to execute template: " + err.Error())
+	}
+	s := b.String()
+	if !strings.HasSuffix(s, "\n") {
+		s += "\n"
+	}
+	l.printer.Print(s)
+	return nil
+}
+
+// templateColor returns the color of a role of the theme (e.g. date), a true
+// color (e.g. #268bd2), a color of the 256-color palette or the color of the
+// feed (.FeedColor).
+func templateColor(color any, theme *Theme) (Color, error) {
+	switch c := color.(type) {
+	case Color:
+		return c, nil
+	case int:
+		if c < 0 || c > 255 {
+			return Color{}, fmt.Errorf("invalid color: %d", c)
+		}
+		return IndexColor(uint8(c)), nil
+	case string:
+		if rgb, ok := strings.CutPrefix(c, "#"); ok {
+			v, err := strconv.ParseUint(rgb, 16, 32)
+			if err != nil || len(rgb) != 6 {
+				return Color{}, fmt.Errorf("invalid color: %s", c)
+			}
+			return RGBColor(uint32(v)), nil
+		}
+		role, ok := theme.Role(c)
+		if !ok {
+			return Color{}, fmt.Errorf("invalid color: %s. Use %s, #rrggbb or 0-255", c, strings.Join(ThemeRoles, ", "))
+		}
+		return role, nil
+	}
+	return Color{}, fmt.Errorf("invalid color: %v", color)
+}
+
+// itemSources returns the titles of the other feeds with the same item.
+func itemSources(fi *FeedItem) []string {
+	sources := make([]string, 0, len(fi.Duplicates))
+	for _, d := range fi.Duplicates {
+		if !slices.Contains(sources, d.Feed.Title) {
+			sources = append(sources, d.Feed.Title)
+		}
+	}
+	return sources
+}
+
+// itemLink returns the link of the item, resolved against the feed link when
+// it is relative.
+func itemLink(fi *FeedItem) string {
+	if strings.HasPrefix(fi.Item.Link, "/") {
+		return strings.TrimSuffix(fi.Feed.Link, "/") + fi.Item.Link
+	}
+	return fi.Item.Link
+}
+
+func itemAuthor(fi *FeedItem) string {
+	if fi.Item.Author != nil && fi.Item.Author.Name != "" {
+		return fi.Item.Author.Name
+	}
+	for _, author := range fi.Item.Authors {
+		if author != nil && author.Name != "" {
+			return author.Name
+		}
+	}
+	return ""
+}
+
+// itemSummary returns the description, or the content, as a single line of
+// plain text.
+func itemSummary(fi *FeedItem) string {
+	s := cmp.Or(fi.Item.Description, fi.Item.Content)
+	return strings.Join(strings.Fields(utils.StripHTML(s)), " ")
+}
@@ -0,0 +1,348 @@
+package internal
+
+import (
+	"fmt"
+	"os"
+	"os/exec"
+	"runtime"
+	"slices"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/mmcdole/gofeed"
+	"github.com/radulucut/cleed/internal/storage"
+	"github.com/radulucut/cleed/internal/utils"
+)
+
+// openConfirmCount is the number of items that can be opened at once without
+// asking for confirmation.
+const openConfirmCount = 10
+
+type OpenOptions struct {
+	Lists   []string
+	Targets []string // item IDs or links, or indexes in the last displayed timeline where 1 is the newest item
+	Unread  bool     // open the items that were not marked as read
+	Since   time.Time
+	Limit   int // maximum number of unread items to open, 0: unlimited
+}
+
+// Open opens items from the cached feeds with the configured command and
+// marks them as read.
+func (f *TerminalFeed) Open(opts *OpenOptions) error {
+	config, err := f.storage.LoadConfig()
+	if err != nil {
+		return utils.NewInternalError("failed to load config: " + err.Error())
+	}
+	if len(opts.Targets) == 0 && !opts.Unread {
+		return utils.NewInternalError("please provide an item ID or index, or use --unread")
+	}
+	if len(opts.Targets) > 0 && opts.Unread {
+		return utils.NewInternalError("--unread cannot be used with an item ID or index")
+	}
+	var selected []*FeedItem
+	if opts.Unread {
+		items, err := f.cachedItems(opts.Lists, opts.Since, config)
+		if err != nil {
+			return err
+		}
+		selected, err = f.unreadItems(items)
+		if err != nil {
+			return err
+		}
+		// open the oldest items first, in reading order
+		slices.Reverse(selected)
+		if opts.Limit > 0 && len(selected) > opts.Limit {
+			selected = selected[:opts.Limit]
+		}
+	} else {
+		selected, err = f.findItems(opts.Lists, opts.Since, opts.Targets, config)
+		if err != nil {
+			return err
+		}
+	}
+	if len(selected) == 0 {
+		f.printer.ErrPrintln("no items to open")
+		return nil
+	}
+	return f.openItems(selected, config)
+}
+
+// cachedItems returns the items of the cached feeds of the lists, newest
+// first. Unlike processFeeds, it neither fetches the feeds nor saves the cache
+// info and the search index.
+func (f *TerminalFeed) cachedItems(lists []string, since time.Time, config *storage.Config) ([]*FeedItem, error) {
+	sel, err := f.selectFeeds(&FeedOptions{Lists: lists}, nil)
+	if err != nil {
+		return nil, err
+	}
+	cacheInfo, err := f.storage.LoadCacheInfo()
+	if err != nil {
+		return nil, utils.NewInternalError("failed to load cache info: " + err.Error())
+	}
+	theme := configTheme(config)
+	feedColorMap := make(map[string]Color)
+	items := make([]*FeedItem, 0)
+	for url := range sel.feeds {
+		if config.IsFeedPaused(url, sel.feedLists[url]) {
+			continue
+		}
+		feed, err := f.parseFeed(url)
+		if err != nil {
+			continue
+		}
+		if !since.IsZero() {
+			feed = f.withArchivedItems(feed, url)
+		}
+		items = f.processFeedItems(feed, items, config, theme, since, &RunSummary{}, feedColorMap, cacheInfoItem(cacheInfo, url))
+	}
+	for _, item := range items {
+		item.Lists = sel.feedLists[item.FeedURL]
+	}
+	sortItems(items, SortDate)
+	return items, nil
+}
+
+// findItems returns the items with the given indexes in the last displayed
+// timeline, or the items of the cached feeds with the given IDs or links.
+// Indexes refer to the cached feeds when no timeline was displayed yet.
+func (f *TerminalFeed) findItems(lists []string, since time.Time, targets []string, config *storage.Config) ([]*FeedItem, error) {
+	timeline, err := f.storage.LoadTimeline()
+	if err != nil {
+		return nil, utils.NewInternalError("failed to load timeline: " + err.Error())
+	}
+	var items []*FeedItem
+	found := make([]*FeedItem, 0, len(targets))
+	for _, target := range targets {
+		var fi *FeedItem
+		if n, err := strconv.Atoi(target); err == nil && timeline != nil {
+			if n < 1 || n > len(timeline) {
+				return nil, utils.NewInternalError("item not found: " + target)
+			}
+			fi, err = f.timelineItem(timeline[n-1])
+			if err != nil {
+				return nil, err
+			}
+		} else {
+			if items == nil {
+				items, err = f.cachedItems(lists, since, config)
+				if err != nil {
+					return nil, err
+				}
+			}
+			fi, err = findItem(items, target)
+			if err != nil {
+				return nil, err
+			}
+		}
+		if !slices.ContainsFunc(found, func(other *FeedItem) bool { return sameItem(fi, other) }) {
+			found = append(found, fi)
+		}
+	}
+	return found, nil
+}
+
+// timelineItems returns the items of the timeline to save.
+func timelineItems(items []*FeedItem) []*storage.TimelineItem {
+	timeline := make([]*storage.TimelineItem, len(items))
+	for i, fi := range items {
+		timeline[i] = &storage.TimelineItem{
+			FeedURL: fi.FeedURL,
+			ID:      storage.ItemID(fi.Item),
+		}
+		if len(fi.Duplicates) > 0 {
+			timeline[i].Duplicates = timelineItems(fi.Duplicates)
+		}
+	}
+	return timeline
+}
+
+// timelineItem returns the item of the cached feed, with its duplicates.
+func (f *TerminalFeed) timelineItem(ti *storage.TimelineItem) (*FeedItem, error) {
+	feed, err := f.parseFeed(ti.FeedURL)
+	if err != nil {
+		return nil, utils.NewInternalError("failed to load feed: " + ti.FeedURL + ": " + err.Error())
+	}
+	feed = f.withArchivedItems(feed, ti.FeedURL)
+	i := slices.IndexFunc(feed.Items, func(item *gofeed.Item) bool {
+		return storage.ItemID(item) == ti.ID
+	})
+	if i < 0 {
+		return nil, utils.NewInternalError("item not found: " + ti.ID)
+	}
+	fi := &FeedItem{
+		Feed:    feed,
+		FeedURL: ti.FeedURL,
+		Item:    feed.Items[i],
+	}
+	for _, d := range ti.Duplicates {
+		// a duplicate is only marked as read, so it may be gone
+		if other, err := f.timelineItem(d); err == nil {
+			fi.Duplicates = append(fi.Duplicates, other)
+		}
+	}
+	return fi, nil
+}
+
+func sameItem(a, b *FeedItem) bool {
+	return a.FeedURL == b.FeedURL && storage.ItemID(a.Item) == storage.ItemID(b.Item)
+}
+
+// findItem returns the item with the given index, ID or link.
+func findItem(items []*FeedItem, target string) (*FeedItem, error) {
+	if n, err := strconv.Atoi(target); err == nil {
+		if n < 1 || n > len(items) {
+			return nil, utils.NewInternalError("item not found: " + target)
+		}
+		return items[n-1], nil
+	}
+	for _, fi := range items {
+		if storage.ItemID(fi.Item) == target || fi.Item.Link == target || itemLink(fi) == target {
+			return fi, nil
+		}
+	}
+	return nil, utils.NewInternalError("item not found: " + target)
+}
+
+func (f *TerminalFeed) unreadItems(items []*FeedItem) ([]*FeedItem, error) {
+	readStates := make(map[string]map[string]time.Time)
+	unread := make([]*FeedItem, 0)
+	for _, fi := range items {
+		state, ok := readStates[fi.FeedURL]
+		if !ok {
+			var err error
+			state, err = f.storage.LoadReadState(fi.FeedURL)
+			if err != nil {
+				return nil, utils.NewInternalError("failed to load read state: " + err.Error())
+			}
+			readStates[fi.FeedURL] = state
+		}
+		if _, ok := state[storage.ItemID(fi.Item)]; !ok {
+			unread = append(unread, fi)
+		}
+	}
+	return unread, nil
+}
+
+// openNewItems opens the new items of the timeline, the oldest first. It asks
+// for confirmation before opening many items.
+func (f *TerminalFeed) openNewItems(displayed []*FeedItem, config *storage.Config) error {
+	opened := make([]*FeedItem, 0)
+	for _, fi := range slices.Backward(displayed) {
+		if fi.IsNew {
+			opened = append(opened, fi)
+		}
+	}
+	if len(opened) == 0 {
+		f.printer.ErrPrintln("no new items to open")
+		return nil
+	}
+	if len(opened) > openConfirmCount && !f.printer.Confirm(fmt.Sprintf("open %d items?", len(opened))) {
+		return nil
+	}
+	return f.openItems(opened, config)
+}
+
+// openItems opens the links of the items and marks the opened items, and
+// their duplicates, as read.
+func (f *TerminalFeed) openItems(items []*FeedItem, config *storage.Config) error {
+	command := openCommand(config.OpenCommand)
+	read := make(map[string][]string)
+	feeds := make([]string, 0)
+	markRead := func(fi *FeedItem) {
+		if _, ok := read[fi.FeedURL]; !ok {
+			feeds = append(feeds, fi.FeedURL)
+		}
+		read[fi.FeedURL] = append(read[fi.FeedURL], storage.ItemID(fi.Item))
+	}
+	var openErr error
+	for _, fi := range items {
+		link := itemLink(fi)
+		if link == "" {
+			f.printer.ErrPrintf("item has no link: %s\n", fi.Item.Title)
+			continue
+		}
+		if err := f.startCommand(command, link); err != nil {
+			openErr = utils.NewInternalError("failed to open " + link + ": " + err.Error())
+			break
+		}
+		f.printer.Println("opened", link)
+		markRead(fi)
+		for _, d := range fi.Duplicates {
+			markRead(d)
+		}
+	}
+	for _, url := range feeds {
+		err := f.storage.MarkRead(read[url], url)
+		if err != nil {
+			return utils.NewInternalError("failed to mark items as read: " + err.Error())
+		}
+	}
+	return openErr
+}
+
+// openCommand returns the configured command, the first command of $BROWSER
+// or the system opener.
+func openCommand(configured string) []string {
+	if command := strings.Fields(configured); len(command) > 0 {
+		return command
+	}
+	browser, _, _ := strings.Cut(os.Getenv("BROWSER"), string(os.PathListSeparator))
+	if command := strings.Fields(browser); len(command) > 0 {
+		return command
+	}
+	return systemOpener()
+}
+
+// systemOpener returns the command that opens links and files with the
+// default application of the system.
+func systemOpener() []string {
+	switch runtime.GOOS {
+	case "darwin":
+		return []string{"open"}
+	case "windows":
+		return []string{"rundll32", "url.dll,FileProtocolHandler"}
+	}
+	return []string{"xdg-open"}
+}
+
+// startCommand starts the command with the given argument, see openArgs. It
+// does not wait for the command to exit, since a browser started by the
+// command may keep running.
+func (f *TerminalFeed) startCommand(command []string, arg string) error {
+	cmd := exec.Command(command[0], openArgs(command[1:], arg)...)
+	err := cmd.Start()
+	if err != nil {
+		return err
+	}
+	go cmd.Wait()
+	return nil
+}
+
+// runCommand runs the command with the given argument, see openArgs, in the
+// terminal.
+func (f *TerminalFeed) runCommand(command []string, arg string) error {
+	cmd := exec.Command(command[0], openArgs(command[1:], arg)...)
+	cmd.Stdin = f.printer.InReader
+	cmd.Stdout = f.printer.OutWriter
+	cmd.Stderr = f.printer.ErrWriter
+	return cmd.Run()
+}
+
+// openArgs replaces %s in the arguments with the link, or appends the link
+// if there is no %s.
+func openArgs(args []string, link string) []string {
+	replaced := false
+	result := make([]string, len(args))
+	for i, arg := range args {
+		if strings.Contains(arg, "%s") {
+			arg = strings.ReplaceAll(arg, "%s", link)
+			replaced = true
+		}
+		result[i] = arg
+	}
+	if !replaced {
+		result = append(result, link)
+	}
+	return result
+}
@@ -0,0 +1,166 @@
+package internal
+
+import (
+	"slices"
+	"strings"
+
+	"github.com/radulucut/cleed/internal/storage"
+	"github.com/radulucut/cleed/internal/utils"
+)
+
+func (f *TerminalFeed) PauseFeed(url string) error {
+	_, address, _, err := f.loadFeedLists(url)
+	if err != nil {
+		return err
+	}
+	err = f.updateConfig(func(config *storage.Config) error {
+		if indexURL(config.PausedFeeds, url) >= 0 {
+			return utils.NewInternalError("feed is already paused: " + url)
+		}
+		config.PausedFeeds = append(config.PausedFeeds, address)
+		return nil
+	})
+	if err != nil {
+		return err
+	}
+	f.printer.Printf("feed %s was paused\n", url)
+	return nil
+}
+
+func (f *TerminalFeed) ResumeFeed(url string) error {
+	_, _, _, err := f.loadFeedLists(url)
+	if err != nil {
+		return err
+	}
+	err = f.updateConfig(func(config *storage.Config) error {
+		i := indexURL(config.PausedFeeds, url)
+		if i < 0 {
+			return utils.NewInternalError("feed is not paused: " + url)
+		}
+		config.PausedFeeds = slices.Delete(config.PausedFeeds, i, i+1)
+		return nil
+	})
+	if err != nil {
+		return err
+	}
+	f.printer.Printf("feed %s was resumed\n", url)
+	return nil
+}
+
+// FeedStatus shows the lists a feed belongs to and whether it is paused.
+func (f *TerminalFeed) FeedStatus(url string) error {
+	config, address, lists, err := f.loadFeedLists(url)
+	if err != nil {
+		return err
+	}
+	status := "active"
+	if config.IsFeedPaused(address, lists) {
+		status = "paused"
+	}
+	f.printer.Println("URL:", address)
+	f.printer.Println("Lists:", strings.Join(lists, ", "))
+	f.printer.Println("Status:", status)
+	return nil
+}
+
+func (f *TerminalFeed) PauseList(list string) error {
+	_, err := f.loadListConfig(list)
+	if err != nil {
+		return err
+	}
+	err = f.updateConfig(func(config *storage.Config) error {
+		if slices.Contains(config.PausedLists, list) {
+			return utils.NewInternalError("list is already paused: " + list)
+		}
+		config.PausedLists = append(config.PausedLists, list)
+		return nil
+	})
+	if err != nil {
+		return err
+	}
+	f.printer.Printf("list %s was paused\n", list)
+	return nil
+}
+
+func (f *TerminalFeed) ResumeList(list string) error {
+	_, err := f.loadListConfig(list)
+	if err != nil {
+		return err
+	}
+	err = f.updateConfig(func(config *storage.Config) error {
+		i := slices.Index(config.PausedLists, list)
+		if i < 0 {
+			if config.IsListPaused(list) {
+				return utils.NewInternalError("list is paused by a parent list: " + list)
+			}
+			return utils.NewInternalError("list is not paused: " + list)
+		}
+		config.PausedLists = slices.Delete(config.PausedLists, i, i+1)
+		return nil
+	})
+	if err != nil {
+		return err
+	}
+	f.printer.Printf("list %s was resumed\n", list)
+	return nil
+}
+
+// loadFeedLists returns the config, the address under which the feed is
+// followed and the lists that follow it.
+func (f *TerminalFeed) loadFeedLists(url string) (*storage.Config, string, []string, error) {
+	config, err := f.storage.LoadConfig()
+	if err != nil {
+		return nil, "", nil, utils.NewInternalError("failed to load config: " + err.Error())
+	}
+	all, err := f.storage.LoadLists()
+	if err != nil {
+		return nil, "", nil, utils.NewInternalError("failed to load lists: " + err.Error())
+	}
+	slices.SortFunc(all, storage.CompareLists)
+	address := ""
+	lists := make([]string, 0)
+	for _, list := range all {
+		feeds, err := f.storage.GetFeedsFromList(list)
+		if err != nil {
+			return nil, "", nil, utils.NewInternalError("failed to list feeds: " + err.Error())
+		}
+		if i := slices.IndexFunc(feeds, func(item *storage.ListItem) bool {
+			return utils.URLKey(item.Address) == utils.URLKey(url)
+		}); i >= 0 {
+			address = feeds[i].Address
+			lists = append(lists, list)
+		}
+	}
+	if len(lists) == 0 {
+		i := indexURL(config.PausedFeeds, url)
+		if i < 0 {
+			return nil, "", nil, utils.NewInternalError("feed not found: " + url)
+		}
+		address = config.PausedFeeds[i]
+	}
+	return config, address, lists, nil
+}
+
+// indexURL returns the index of the URL in urls ignoring the differences
+// that URLKey ignores, or -1.
+func indexURL(urls []string, url string) int {
+	key := utils.URLKey(url)
+	return slices.IndexFunc(urls, func(u string) bool { return utils.URLKey(u) == key })
+}
+
+func (f *TerminalFeed) loadListConfig(list string) (*storage.Config, error) {
+	config, err := f.storage.LoadConfig()
+	if err != nil {
+		return nil, utils.NewInternalError("failed to load config: " + err.Error())
+	}
+	lists, err := f.storage.LoadLists()
+	if err != nil {
+		return nil, utils.NewInternalError("failed to load lists: " + err.Error())
+	}
+	if !slices.ContainsFunc(lists, func(l string) bool {
+		return storage.IsInList(l, list)
+	}) && !slices.Contains(config.PausedLists, list) {
+		return nil, utils.NewInternalError("list not found: " + list)
+	}
+	return config, nil
+}
@@ -1,11 +1,18 @@
 package internal
 
 import (
+	"bufio"
+	"bytes"
+	"cmp"
 	"fmt"
 	"io"
 	"math"
 	"os"
+	"os/exec"
+	"strconv"
+	"strings"
 
+	"github.com/radulucut/cleed/internal/utils"
 	"golang.org/x/term"
 )
 
@@ -15,6 +22,17 @@ type Printer struct {
 	ErrWriter io.Writer
 
 	disableStyling bool
+	noColor        bool // NO_COLOR is set
+	trueColor      bool
+	hyperlinks     bool
+	pager          *pager
+}
+
+// pager holds the output while it is buffered for the pager.
+type pager struct {
+	command []string
+	out     *os.File
+	buf     bytes.Buffer
 }
 
 func NewPrinter(
@@ -27,9 +45,13 @@ func NewPrinter(
 		OutWriter: outWriter,
 		ErrWriter: errWriter,
 	}
+	p.noColor = os.Getenv("NO_COLOR") != ""
+	colorTerm := os.Getenv("COLORTERM")
+	p.trueColor = colorTerm == "truecolor" || colorTerm == "24bit"
 	f, ok := outWriter.(*os.File)
 	if ok {
 		p.disableStyling = !term.IsTerminal(int(f.Fd()))
+		p.hyperlinks = !p.disableStyling && supportsHyperlinks()
 	} else {
 		p.disableStyling = true
 	}
@@ -60,6 +82,19 @@ func (p *Printer) ErrPrintf(format string, a ...any) {
 	fmt.Fprintf(p.ErrWriter, format, a...)
 }
 
+// Confirm asks a yes or no question and reports whether the answer is yes. No
+// is assumed when there is no input.
+func (p *Printer) Confirm(question string) bool {
+	p.ErrPrint(question, " [y/N] ")
+	if p.InReader == nil {
+		p.ErrPrintln()
+		return false
+	}
+	answer, _ := bufio.NewReader(p.InReader).ReadString('\n')
+	answer = strings.ToLower(strings.TrimSpace(answer))
+	return answer == "y" || answer == "yes"
+}
+
 func (p *Printer) ColorForeground(s string, color uint8) string {
 	if p.disableStyling {
 		return s
@@ -74,6 +109,19 @@ func (p *Printer) ColorBackground(s string, color uint8) string {
 	return fmt.Sprintf("\033[48;5;%dm%s\033[0m", color, s)
 }
 
+// Color returns s in the given color. True colors are approximated with the
+// 256-color palette when the terminal does not support them.
+func (p *Printer) Color(s string, color Color) string {
+	if p.disableStyling || p.noColor || color.kind == colorNone {
+		return s
+	}
+	return color.sequence(p.trueColor) + s + "\033[0m"
+}
+
+func (p *Printer) SetTrueColor(enable bool) {
+	p.trueColor = enable
+}
+
 func (p *Printer) GetStyling() bool {
 	return !p.disableStyling
 }
@@ -82,8 +130,98 @@ func (p *Printer) SetStyling(enable bool) {
 	p.disableStyling = !enable
 }
 
+// Hyperlink returns the text as an OSC 8 hyperlink to url. It returns the
+// text unchanged when styling or hyperlinks are disabled.
+func (p *Printer) Hyperlink(text, url string) string {
+	if p.disableStyling || !p.hyperlinks || url == "" {
+		return text
+	}
+	return fmt.Sprintf("\033]8;;%s\033\\%s\033]8;;\033\\", url, text)
+}
+
+// GetHyperlinks reports whether hyperlinks are printed.
+func (p *Printer) GetHyperlinks() bool {
+	return !p.disableStyling && p.hyperlinks
+}
+
+func (p *Printer) SetHyperlinks(enable bool) {
+	p.hyperlinks = enable
+}
+
+// supportsHyperlinks detects terminals known to support OSC 8 hyperlinks.
+func supportsHyperlinks() bool {
+	if os.Getenv("DOMTERM") != "" || os.Getenv("WT_SESSION") != "" || os.Getenv("KITTY_WINDOW_ID") != "" {
+		return true
+	}
+	switch os.Getenv("TERM_PROGRAM") {
+	case "iTerm.app", "WezTerm", "vscode", "ghostty", "Hyper":
+		return true
+	}
+	if v, err := strconv.Atoi(os.Getenv("VTE_VERSION")); err == nil && v >= 5000 {
+		return true
+	}
+	name := os.Getenv("TERM")
+	return strings.Contains(name, "kitty") || strings.Contains(name, "ghostty") || strings.HasPrefix(name, "foot") || name == "alacritty"
+}
+
+// PagerCommand returns the pager to use: the configured command, $PAGER or
+// less. It returns nil when the configured command is "-".
+func PagerCommand(configured string) []string {
+	if configured == "-" {
+		return nil
+	}
+	return strings.Fields(cmp.Or(configured, os.Getenv("PAGER"), "less -R"))
+}
+
+// StartPager buffers the output until StopPager is called. It does nothing
+// when the output is not a terminal.
+func (p *Printer) StartPager(command []string) {
+	if p.pager != nil || len(command) == 0 {
+		return
+	}
+	f, ok := p.OutWriter.(*os.File)
+	if !ok || !term.IsTerminal(int(f.Fd())) {
+		return
+	}
+	p.pager = &pager{command: command, out: f}
+	p.OutWriter = &p.pager.buf
+}
+
+// StopPager writes the buffered output, through the pager when it does not
+// fit in the terminal. The output is written directly if the pager cannot be
+// started.
+func (p *Printer) StopPager() {
+	if p.pager == nil {
+		return
+	}
+	pager := p.pager
+	p.pager = nil
+	p.OutWriter = pager.out
+	width, height := p.GetSize()
+	if utils.DisplayRows(pager.buf.String(), width) < height {
+		pager.out.Write(pager.buf.Bytes())
+		return
+	}
+	cmd := exec.Command(pager.command[0], pager.command[1:]...)
+	cmd.Stdin = &pager.buf
+	cmd.Stdout = pager.out
+	cmd.Stderr = p.ErrWriter
+	if os.Getenv("LESS") == "" {
+		// keep the colors when $PAGER is less without -R
+		cmd.Env = append(os.Environ(), "LESS=R")
+	}
+	if err := cmd.Start(); err != nil {
+		pager.out.Write(pager.buf.Bytes())
+		return
+	}
+	cmd.Wait()
+}
+
 func (p *Printer) GetSize() (width, height int) {
 	f, ok := p.OutWriter.(*os.File)
+	if p.pager != nil {
+		f, ok = p.pager.out, true
+	}
 	if !ok {
 		return math.MaxInt, math.MaxInt
 	}
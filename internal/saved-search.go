@@ -0,0 +1,176 @@
+package internal
+
+import (
+	"maps"
+	"slices"
+	"time"
+
+	"github.com/mattn/go-runewidth"
+	"github.com/radulucut/cleed/internal/search"
+	"github.com/radulucut/cleed/internal/storage"
+	"github.com/radulucut/cleed/internal/utils"
+)
+
+func (f *TerminalFeed) SaveSearch(name string, s *storage.SavedSearch) error {
+	if name == "" {
+		return utils.NewInternalError("please provide a name for the search")
+	}
+	err := f.updateConfig(func(config *storage.Config) error {
+		analyzer, err := newAnalyzer(config)
+		if err != nil {
+			return err
+		}
+		query, err := search.ParseQuery(s.Query, analyzer)
+		if err != nil {
+			return utils.NewInternalError("invalid query: " + err.Error())
+		}
+		if query.IsEmpty() {
+			return utils.NewInternalError("query is empty")
+		}
+		if config.SavedSearches == nil {
+			config.SavedSearches = make(map[string]*storage.SavedSearch)
+		}
+		config.SavedSearches[name] = s
+		return nil
+	})
+	if err != nil {
+		return err
+	}
+	f.printer.Printf("search %s was saved\n", name)
+	return nil
+}
+
+func (f *TerminalFeed) RemoveSavedSearch(name string) error {
+	err := f.updateConfig(func(config *storage.Config) error {
+		if _, ok := config.SavedSearches[name]; !ok {
+			return utils.NewInternalError("saved search not found: " + name)
+		}
+		delete(config.SavedSearches, name)
+		return nil
+	})
+	if err != nil {
+		return err
+	}
+	f.printer.Printf("search %s was removed\n", name)
+	return nil
+}
+
+func (f *TerminalFeed) ListSavedSearches() error {
+	config, err := f.storage.LoadConfig()
+	if err != nil {
+		return utils.NewInternalError("failed to load config: " + err.Error())
+	}
+	if len(config.SavedSearches) == 0 {
+		f.printer.Println("no saved searches")
+		return nil
+	}
+	names := slices.Sorted(maps.Keys(config.SavedSearches))
+	cellMax := [3]int{len("Name"), len("Query"), len("List")}
+	for _, name := range names {
+		s := config.SavedSearches[name]
+		cellMax[0] = max(cellMax[0], runewidth.StringWidth(name))
+		cellMax[1] = max(cellMax[1], runewidth.StringWidth(s.Query))
+		cellMax[2] = max(cellMax[2], runewidth.StringWidth(s.List))
+	}
+	f.printer.Printf("%s  %s  %s  Since\n",
+		runewidth.FillRight("Name", cellMax[0]),
+		runewidth.FillRight("Query", cellMax[1]),
+		runewidth.FillRight("List", cellMax[2]),
+	)
+	for _, name := range names {
+		s := config.SavedSearches[name]
+		f.printer.Printf("%s  %s  %s  %s\n",
+			runewidth.FillRight(name, cellMax[0]),
+			runewidth.FillRight(s.Query, cellMax[1]),
+			runewidth.FillRight(s.List, cellMax[2]),
+			s.Since,
+		)
+	}
+	return nil
+}
+
+func (f *TerminalFeed) SavedSearch(name string) (*storage.SavedSearch, error) {
+	config, err := f.storage.LoadConfig()
+	if err != nil {
+		return nil, utils.NewInternalError("failed to load config: " + err.Error())
+	}
+	s, ok := config.SavedSearches[name]
+	if !ok {
+		return nil, utils.NewInternalError("saved search not found: " + name)
+	}
+	return s, nil
+}
+
+// SavedSearchForList returns the saved search used in place of a list, or
+// nil if there is no saved search with that name or a list has the same name.
+func (f *TerminalFeed) SavedSearchForList(list string) (*storage.SavedSearch, error) {
+	config, err := f.storage.LoadConfig()
+	if err != nil {
+		return nil, utils.NewInternalError("failed to load config: " + err.Error())
+	}
+	s := config.SavedSearches[list]
+	if s == nil {
+		return nil, nil
+	}
+	lists, err := f.storage.LoadLists()
+	if err != nil {
+		return nil, utils.NewInternalError("failed to load lists: " + err.Error())
+	}
+	if slices.Contains(lists, list) {
+		return nil, nil
+	}
+	return s, nil
+}
+
+// matchSearches keeps the items of the lists of the run and the items
+// matching one of the saved searches, which are shown in a list named after
+// the search. The query of the run, if any, must match as well.
+func (f *TerminalFeed) matchSearches(fi *feedIndex, items []*FeedItem, opts *FeedOptions, sel *feedSelection, config *storage.Config) []*FeedItem {
+	scores := make(map[*FeedItem]float64)
+	highlights := make(map[*FeedItem][]string)
+	names := make(map[*FeedItem][]string)
+	match := func(feeds map[string]struct{}, since time.Time, q *search.Query, name string) {
+		selected := make([]*FeedItem, 0)
+		for _, item := range items {
+			if _, ok := feeds[item.FeedURL]; !ok || item.Item.PublishedParsed.Before(since) {
+				continue
+			}
+			item.Score = 0
+			item.Highlights = nil
+			selected = append(selected, item)
+		}
+		if q != nil && !q.IsEmpty() {
+			selected = f.searchItems(fi, selected, q, config)
+		}
+		for _, item := range selected {
+			scores[item] = max(scores[item], item.Score)
+			highlights[item] = append(highlights[item], item.Highlights...)
+			if name != "" {
+				names[item] = append(names[item], name)
+			}
+		}
+	}
+	if sel.listFeeds != nil {
+		match(sel.listFeeds, opts.Since, opts.Query, "")
+	}
+	for _, s := range sel.searches {
+		q := s.query
+		if opts.Query != nil {
+			q = q.And(opts.Query)
+		}
+		match(s.feeds, s.Since, q, s.Name)
+	}
+	matched := make([]*FeedItem, 0, len(scores))
+	for _, item := range items {
+		score, ok := scores[item]
+		if !ok {
+			continue
+		}
+		item.Score = score
+		item.Highlights = highlights[item]
+		// the lists are shared by the items of a feed
+		item.Lists = slices.Concat(item.Lists, names[item])
+		matched = append(matched, item)
+	}
+	return matched
+}
@@ -0,0 +1,154 @@
+package internal
+
+import (
+	"bytes"
+	"math"
+	"os"
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/mmcdole/gofeed"
+	"github.com/radulucut/cleed/internal/search"
+	"github.com/radulucut/cleed/internal/storage"
+	"github.com/radulucut/cleed/internal/utils"
+)
+
+const (
+	// an item loses half of its recency boost every recencyHalfLife
+	recencyHalfLife = 30 * 24 * time.Hour
+
+	// score multiplier for each level of feed priority
+	feedPriorityBoost = 1.25
+
+	MinFeedPriority = -5
+	MaxFeedPriority = 5
+)
+
+// feedIndex loads the search index only when a run needs it.
+type feedIndex struct {
+	analyzer *utils.Analyzer
+	idx      *search.Index
+	dirty    bool
+}
+
+func newAnalyzer(config *storage.Config) (*utils.Analyzer, error) {
+	analyzer, err := utils.NewAnalyzer(config.SearchLanguage)
+	if err != nil {
+		return nil, utils.NewInternalError("failed to load search language: " + err.Error())
+	}
+	return analyzer, nil
+}
+
+// loadIndex returns the saved index, or a new one if it was built with
+// another language.
+func (f *TerminalFeed) loadIndex(fi *feedIndex) *search.Index {
+	if fi.idx != nil {
+		return fi.idx
+	}
+	r, err := f.storage.OpenSearchIndex()
+	if err != nil {
+		if !os.IsNotExist(err) {
+			f.printer.ErrPrintln("failed to open search index:", err)
+		}
+		fi.idx = search.NewIndex(fi.analyzer)
+		return fi.idx
+	}
+	defer r.Close()
+	fi.idx, err = search.DecodeIndex(r)
+	if err != nil {
+		f.printer.ErrPrintln("failed to load search index, rebuilding it:", err)
+	}
+	if err != nil || fi.idx.Language != fi.analyzer.Language() {
+		fi.idx = search.NewIndex(fi.analyzer)
+		fi.dirty = true
+	}
+	return fi.idx
+}
+
+func (f *TerminalFeed) updateIndex(fi *feedIndex, url string, items []*gofeed.Item) {
+	f.loadIndex(fi).UpdateFeed(url, items)
+	fi.dirty = true
+}
+
+// tidyIndex removes the feeds that are no longer followed.
+func (f *TerminalFeed) tidyIndex(fi *feedIndex, feeds map[string]*storage.ListItem) {
+	if fi.idx == nil {
+		return
+	}
+	for _, url := range fi.idx.FeedNames() {
+		if _, ok := feeds[url]; !ok {
+			fi.idx.RemoveFeed(url)
+			fi.dirty = true
+		}
+	}
+}
+
+func (f *TerminalFeed) saveIndex(fi *feedIndex) {
+	if !fi.dirty {
+		return
+	}
+	b := new(bytes.Buffer)
+	err := fi.idx.Encode(b)
+	if err == nil {
+		err = f.storage.SaveSearchIndex(b)
+	}
+	if err != nil {
+		f.printer.ErrPrintln("failed to save search index:", err)
+	}
+}
+
+// searchItems keeps the items matching the query and ranks them. Feeds
+// that were cached before the index existed are indexed on the fly.
+func (f *TerminalFeed) searchItems(fi *feedIndex, items []*FeedItem, q *search.Query, config *storage.Config) []*FeedItem {
+	idx := f.loadIndex(fi)
+	for _, item := range items {
+		if !idx.HasFeed(item.FeedURL) {
+			f.updateIndex(fi, item.FeedURL, item.Feed.Items)
+		}
+	}
+	results := idx.Search(q)
+	matched := make([]*FeedItem, 0)
+	for _, item := range items {
+		r, ok := results[search.DocKey(item.FeedURL, item.Item)]
+		if !ok {
+			continue
+		}
+		item.Score = f.rank(item, r.Score, config)
+		item.Highlights = r.Terms
+		matched = append(matched, item)
+	}
+	return matched
+}
+
+// rank combines the match score with the age of the item and the priority
+// of its feed. Old items keep at least half of their score.
+func (f *TerminalFeed) rank(item *FeedItem, score float64, config *storage.Config) float64 {
+	recency := 1.0
+	age := f.time.Now().Sub(*item.Item.PublishedParsed)
+	if age > 0 {
+		recency = math.Pow(0.5, float64(age)/float64(recencyHalfLife))
+	}
+	priority := float64(config.FeedPriority[item.FeedURL])
+	return score * (0.5 + 0.5*recency) * math.Pow(feedPriorityBoost, priority)
+}
+
+// highlight colors the terms of s that matched a search and the rest of s
+// with the base color.
+func (f *TerminalFeed) highlight(s string, terms []string, analyzer *utils.Analyzer, color, base Color) string {
+	if len(terms) == 0 || !f.printer.GetStyling() {
+		return f.printer.Color(s, base)
+	}
+	b := strings.Builder{}
+	last := 0
+	for _, token := range analyzer.Tokens(s) {
+		if !slices.Contains(terms, token.Term) {
+			continue
+		}
+		b.WriteString(f.printer.Color(s[last:token.Start], base))
+		b.WriteString(f.printer.Color(s[token.Start:token.End], color))
+		last = token.End
+	}
+	b.WriteString(f.printer.Color(s[last:], base))
+	return b.String()
+}
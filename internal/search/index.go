@@ -0,0 +1,413 @@
+package search
+
+import (
+	"encoding/gob"
+	"io"
+	"math"
+	"strings"
+
+	"github.com/mmcdole/gofeed"
+	"github.com/radulucut/cleed/internal/storage"
+	"github.com/radulucut/cleed/internal/utils"
+)
+
+const (
+	bm25K1 = 1.2
+	bm25B  = 0.75
+
+	maxFuzzyDistance = 2
+
+	// weight of terms that only share the prefix of a prefix query
+	prefixWeight = 0.75
+)
+
+// FieldWeights boosts matches in short, descriptive fields over matches in
+// the body of an item.
+var FieldWeights = map[string]float64{
+	FieldTitle:       3,
+	FieldCategory:    2,
+	FieldAuthor:      1.5,
+	FieldDescription: 1,
+	FieldContent:     1,
+}
+
+type Document struct {
+	Feed    string
+	Lengths map[string]int      // number of terms per field
+	Terms   map[string][]string // distinct terms per field
+}
+
+type FieldIndex struct {
+	Postings    map[string]map[string][]int // term -> document -> positions
+	TotalLength int
+}
+
+// Index is an inverted index over feed items, updated one feed at a time.
+type Index struct {
+	Language string // language of the analyzer used to build the index
+	Docs     map[string]*Document
+	Fields   map[string]*FieldIndex
+	Feeds    map[string][]string // feed -> documents
+
+	analyzer *utils.Analyzer
+}
+
+type Result struct {
+	Score float64
+	Terms []string // matched terms, used for highlighting
+}
+
+func NewIndex(analyzer *utils.Analyzer) *Index {
+	idx := &Index{
+		Language: analyzer.Language(),
+		Docs:     make(map[string]*Document),
+		Fields:   make(map[string]*FieldIndex),
+		Feeds:    make(map[string][]string),
+		analyzer: analyzer,
+	}
+	for _, field := range Fields {
+		idx.Fields[field] = &FieldIndex{
+			Postings: make(map[string]map[string][]int),
+		}
+	}
+	return idx
+}
+
+func DecodeIndex(r io.Reader) (*Index, error) {
+	idx := &Index{}
+	err := gob.NewDecoder(r).Decode(idx)
+	if err != nil {
+		return nil, err
+	}
+	idx.analyzer, err = utils.NewAnalyzer(idx.Language)
+	if err != nil {
+		return nil, err
+	}
+	for _, field := range Fields {
+		if idx.Fields[field] == nil {
+			idx.Fields[field] = &FieldIndex{}
+		}
+		if idx.Fields[field].Postings == nil {
+			idx.Fields[field].Postings = make(map[string]map[string][]int)
+		}
+	}
+	if idx.Docs == nil {
+		idx.Docs = make(map[string]*Document)
+	}
+	if idx.Feeds == nil {
+		idx.Feeds = make(map[string][]string)
+	}
+	return idx, nil
+}
+
+func (idx *Index) Encode(w io.Writer) error {
+	return gob.NewEncoder(w).Encode(idx)
+}
+
+func (idx *Index) Analyzer() *utils.Analyzer {
+	return idx.analyzer
+}
+
+// DocKey identifies an item of a feed in the index.
+func DocKey(feed string, item *gofeed.Item) string {
+	return feed + "\x00" + storage.ItemID(item) + "\x00" + item.Title
+}
+
+func (idx *Index) HasFeed(feed string) bool {
+	_, ok := idx.Feeds[feed]
+	return ok
+}
+
+func (idx *Index) FeedNames() []string {
+	feeds := make([]string, 0, len(idx.Feeds))
+	for feed := range idx.Feeds {
+		feeds = append(feeds, feed)
+	}
+	return feeds
+}
+
+// Add indexes a document that does not belong to a feed.
+func (idx *Index) Add(key string, fields map[string]string) {
+	if _, ok := idx.Docs[key]; ok {
+		return
+	}
+	idx.addDocument(key, "", fields)
+}
+
+// UpdateFeed replaces all the documents of a feed.
+func (idx *Index) UpdateFeed(feed string, items []*gofeed.Item) {
+	idx.RemoveFeed(feed)
+	keys := make([]string, 0, len(items))
+	for _, item := range items {
+		key := DocKey(feed, item)
+		if _, ok := idx.Docs[key]; ok {
+			continue
+		}
+		idx.addDocument(key, feed, itemFields(item))
+		keys = append(keys, key)
+	}
+	idx.Feeds[feed] = keys
+}
+
+func (idx *Index) RemoveFeed(feed string) {
+	for _, key := range idx.Feeds[feed] {
+		doc, ok := idx.Docs[key]
+		if !ok {
+			continue
+		}
+		for field, terms := range doc.Terms {
+			fi := idx.Fields[field]
+			for _, term := range terms {
+				delete(fi.Postings[term], key)
+				if len(fi.Postings[term]) == 0 {
+					delete(fi.Postings, term)
+				}
+			}
+			fi.TotalLength -= doc.Lengths[field]
+		}
+		delete(idx.Docs, key)
+	}
+	delete(idx.Feeds, feed)
+}
+
+func itemFields(item *gofeed.Item) map[string]string {
+	fields := map[string]string{
+		FieldTitle:       item.Title,
+		FieldDescription: utils.StripHTML(item.Description),
+		FieldContent:     utils.StripHTML(item.Content),
+		FieldCategory:    strings.Join(item.Categories, " "),
+	}
+	authors := make([]string, 0, len(item.Authors)+1)
+	if item.Author != nil {
+		authors = append(authors, item.Author.Name)
+	}
+	for _, author := range item.Authors {
+		if author != nil && (item.Author == nil || author.Name != item.Author.Name) {
+			authors = append(authors, author.Name)
+		}
+	}
+	fields[FieldAuthor] = strings.Join(authors, " ")
+	return fields
+}
+
+func (idx *Index) addDocument(key, feed string, fields map[string]string) {
+	doc := &Document{
+		Feed:    feed,
+		Lengths: make(map[string]int),
+		Terms:   make(map[string][]string),
+	}
+	for field, text := range fields {
+		terms := idx.analyzer.Terms(text)
+		if len(terms) == 0 {
+			continue
+		}
+		fi := idx.Fields[field]
+		for pos, term := range terms {
+			postings, ok := fi.Postings[term]
+			if !ok {
+				postings = make(map[string][]int)
+				fi.Postings[term] = postings
+			}
+			if _, ok := postings[key]; !ok {
+				doc.Terms[field] = append(doc.Terms[field], term)
+			}
+			postings[key] = append(postings[key], pos)
+		}
+		doc.Lengths[field] = len(terms)
+		fi.TotalLength += len(terms)
+	}
+	idx.Docs[key] = doc
+}
+
+// Search returns the documents matching the query, scored with BM25.
+func (idx *Index) Search(q *Query) map[string]*Result {
+	var results map[string]*Result
+	for i, group := range q.Groups {
+		matched := make(map[string]*Result)
+		for _, clause := range group {
+			idx.searchClause(clause, q.Fuzzy, matched)
+		}
+		if i == 0 {
+			results = matched
+			continue
+		}
+		for key, r := range results {
+			m, ok := matched[key]
+			if !ok {
+				delete(results, key)
+				continue
+			}
+			r.Score += m.Score
+			r.Terms = append(r.Terms, m.Terms...)
+		}
+	}
+	if results == nil {
+		results = make(map[string]*Result, len(idx.Docs))
+		for key := range idx.Docs {
+			results[key] = &Result{}
+		}
+	}
+	for _, clause := range q.Excluded {
+		excluded := make(map[string]*Result)
+		idx.searchClause(clause, q.Fuzzy, excluded)
+		for key := range excluded {
+			delete(results, key)
+		}
+	}
+	return results
+}
+
+func (idx *Index) searchClause(clause *Clause, fuzzy bool, results map[string]*Result) {
+	for _, field := range clause.fields() {
+		idx.searchField(clause, field, fuzzy, results)
+	}
+}
+
+func (idx *Index) searchField(clause *Clause, field string, fuzzy bool, results map[string]*Result) {
+	fi := idx.Fields[field]
+	if fi == nil {
+		return
+	}
+	if len(clause.Terms) > 1 {
+		idx.searchPhrase(clause, field, results)
+		return
+	}
+	for term, weight := range idx.expand(clause, fuzzy, fi) {
+		postings := fi.Postings[term]
+		idf := idx.idf(len(postings))
+		for key, positions := range postings {
+			score := FieldWeights[field] * weight * idf * idx.tf(len(positions), field, key)
+			addResult(results, key, score, term)
+		}
+	}
+}
+
+// expand returns the terms of the index matching a single term clause with
+// their weight.
+func (idx *Index) expand(clause *Clause, fuzzy bool, fi *FieldIndex) map[string]float64 {
+	term := clause.Terms[0]
+	terms := make(map[string]float64)
+	if _, ok := fi.Postings[term]; ok {
+		terms[term] = 1
+	}
+	if !clause.Prefix && !fuzzy {
+		return terms
+	}
+	q := []rune(term)
+	distance := fuzzyDistance(len(q))
+	for t := range fi.Postings {
+		if t == term {
+			continue
+		}
+		if clause.Prefix {
+			if strings.HasPrefix(t, term) {
+				terms[t] = prefixWeight
+			}
+			continue
+		}
+		r := []rune(t)
+		if abs(len(r)-len(q)) > distance {
+			continue
+		}
+		d := utils.LevenshteinDistance(q, r)
+		if d <= distance {
+			terms[t] = 1 / float64(1+d)
+		}
+	}
+	return terms
+}
+
+func (idx *Index) searchPhrase(clause *Clause, field string, results map[string]*Result) {
+	fi := idx.Fields[field]
+	lists := make([]map[string][]int, len(clause.Terms))
+	idf := 0.0
+	for i, term := range clause.Terms {
+		lists[i] = fi.Postings[term]
+		if i == len(clause.Terms)-1 && clause.Prefix {
+			lists[i] = fi.prefixPostings(term)
+		}
+		if len(lists[i]) == 0 {
+			return
+		}
+		idf += idx.idf(len(lists[i]))
+	}
+	for key, positions := range lists[0] {
+		count := 0
+		for _, pos := range positions {
+			if matchesPhrase(lists, key, pos) {
+				count++
+			}
+		}
+		if count > 0 {
+			addResult(results, key, FieldWeights[field]*idf*idx.tf(count, field, key), clause.Terms...)
+		}
+	}
+}
+
+func matchesPhrase(lists []map[string][]int, key string, pos int) bool {
+	for i := 1; i < len(lists); i++ {
+		found := false
+		for _, p := range lists[i][key] {
+			if p == pos+i {
+				found = true
+				break
+			}
+		}
+		if !found {
+			return false
+		}
+	}
+	return true
+}
+
+func (fi *FieldIndex) prefixPostings(prefix string) map[string][]int {
+	postings := make(map[string][]int)
+	for term, p := range fi.Postings {
+		if !strings.HasPrefix(term, prefix) {
+			continue
+		}
+		for key, positions := range p {
+			postings[key] = append(postings[key], positions...)
+		}
+	}
+	return postings
+}
+
+func (idx *Index) idf(df int) float64 {
+	n := float64(len(idx.Docs))
+	return math.Log(1 + (n-float64(df)+0.5)/(float64(df)+0.5))
+}
+
+func (idx *Index) tf(freq int, field, key string) float64 {
+	fi := idx.Fields[field]
+	avg := 1.0
+	if len(idx.Docs) > 0 && fi.TotalLength > 0 {
+		avg = float64(fi.TotalLength) / float64(len(idx.Docs))
+	}
+	length := float64(idx.Docs[key].Lengths[field])
+	f := float64(freq)
+	return f * (bm25K1 + 1) / (f + bm25K1*(1-bm25B+bm25B*length/avg))
+}
+
+func addResult(results map[string]*Result, key string, score float64, terms ...string) {
+	r, ok := results[key]
+	if !ok {
+		r = &Result{}
+		results[key] = r
+	}
+	r.Score += score
+	r.Terms = append(r.Terms, terms...)
+}
+
+// fuzzyDistance allows fewer edits for short terms, which would otherwise
+// match almost anything.
+func fuzzyDistance(length int) int {
+	return min(maxFuzzyDistance, length/3)
+}
+
+func abs(v int) int {
+	if v < 0 {
+		return -v
+	}
+	return v
+}
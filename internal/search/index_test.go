@@ -0,0 +1,224 @@
+package search
+
+import (
+	"bytes"
+	"slices"
+	"strings"
+	"testing"
+
+	"github.com/mmcdole/gofeed"
+	"github.com/radulucut/cleed/internal/utils"
+	"github.com/stretchr/testify/assert"
+)
+
+var plain, _ = utils.NewAnalyzer("")
+
+func Test_ParseQuery(t *testing.T) {
+	q := mustParseQuery(`go title:"Release Notes" author:jane kube* body:x "unterminated phrase`, plain)
+	assert.Equal(t, [][]*Clause{
+		{{Terms: []string{"go"}}},
+		{{Field: FieldTitle, Terms: []string{"release", "notes"}}},
+		{{Field: FieldAuthor, Terms: []string{"jane"}}},
+		{{Terms: []string{"kube"}, Prefix: true}},
+		{{Terms: []string{"body", "x"}}},
+		{{Terms: []string{"unterminated", "phrase"}}},
+	}, q.Groups)
+	assert.Empty(t, q.Excluded)
+
+	assert.True(t, mustParseQuery(` "" * `, plain).IsEmpty())
+}
+
+func Test_ParseQuery_Boolean(t *testing.T) {
+	q := mustParseQuery(`OR go OR rust OR "c++" +linux -title:windows - or -`, plain)
+	assert.Equal(t, [][]*Clause{
+		{
+			{Terms: []string{"go"}},
+			{Terms: []string{"rust"}},
+			{Terms: []string{"c"}},
+		},
+		{{Terms: []string{"linux"}}},
+		{{Terms: []string{"or"}}},
+	}, q.Groups)
+	assert.Equal(t, []*Clause{
+		{Field: FieldTitle, Terms: []string{"windows"}},
+	}, q.Excluded)
+
+	for _, query := range []string{"-y OR z", "x -y OR z", "x OR -y", "-x OR -y"} {
+		_, err := ParseQuery(query, plain)
+		assert.Error(t, err, query)
+	}
+	q = mustParseQuery("x OR y -z", plain)
+	assert.Equal(t, [][]*Clause{
+		{{Terms: []string{"x"}}, {Terms: []string{"y"}}},
+	}, q.Groups)
+	assert.Equal(t, []*Clause{{Terms: []string{"z"}}}, q.Excluded)
+}
+
+func mustParseQuery(s string, analyzer *utils.Analyzer) *Query {
+	q, err := ParseQuery(s, analyzer)
+	if err != nil {
+		panic(err)
+	}
+	return q
+}
+
+func newTestIndex() *Index {
+	return newTestIndexWithAnalyzer(plain)
+}
+
+func newTestIndexWithAnalyzer(analyzer *utils.Analyzer) *Index {
+	idx := NewIndex(analyzer)
+	idx.UpdateFeed("feed-1", []*gofeed.Item{
+		{
+			GUID:        "1",
+			Title:       "A new release of Go",
+			Description: "<p>The <b>compiler</b> is faster</p>",
+			Categories:  []string{"golang"},
+		},
+		{
+			GUID:    "2",
+			Title:   "Release of the new Kubernetes",
+			Content: "Clusters everywhere",
+			Authors: []*gofeed.Person{{Name: "Jane Doe"}},
+		},
+	})
+	idx.UpdateFeed("feed-2", []*gofeed.Item{
+		{
+			GUID:  "3",
+			Title: "Weekly notes",
+		},
+	})
+	return idx
+}
+
+func searchTitles(idx *Index, query string) []string {
+	return searchTitlesFuzzy(idx, query, false)
+}
+
+func searchTitlesFuzzy(idx *Index, query string, fuzzy bool) []string {
+	q := mustParseQuery(query, idx.Analyzer())
+	q.Fuzzy = fuzzy
+	results := idx.Search(q)
+	keys := make([]string, 0, len(results))
+	for key := range results {
+		keys = append(keys, key)
+	}
+	slices.SortFunc(keys, func(a, b string) int {
+		if results[a].Score > results[b].Score {
+			return -1
+		}
+		if results[a].Score < results[b].Score {
+			return 1
+		}
+		return 0
+	})
+	titles := make([]string, len(keys))
+	for i, key := range keys {
+		titles[i] = key[strings.LastIndexByte(key, 0)+1:]
+	}
+	return titles
+}
+
+func Test_Index_Search(t *testing.T) {
+	idx := newTestIndex()
+
+	assert.Equal(t, []string{"A new release of Go"}, searchTitles(idx, `"new release"`))
+	assert.ElementsMatch(t, []string{"A new release of Go", "Release of the new Kubernetes"}, searchTitles(idx, "release"))
+	assert.Equal(t, []string{"A new release of Go"}, searchTitles(idx, "compiler"))
+	assert.Equal(t, []string{"Release of the new Kubernetes"}, searchTitles(idx, "clusters"))
+	assert.Equal(t, []string{"Release of the new Kubernetes"}, searchTitles(idx, "author:jane"))
+	assert.Equal(t, []string{"A new release of Go"}, searchTitles(idx, "category:golang"))
+	assert.Empty(t, searchTitles(idx, "title:golang"))
+	assert.Equal(t, []string{"Release of the new Kubernetes"}, searchTitles(idx, "kube*"))
+	assert.Empty(t, searchTitles(idx, "kubernets"))
+	assert.Equal(t, []string{"Release of the new Kubernetes"}, searchTitlesFuzzy(idx, "kubernets", true))
+	assert.Equal(t, []string{"Weekly notes"}, searchTitles(idx, "weekly"))
+}
+
+func Test_Index_Search_Boolean(t *testing.T) {
+	idx := newTestIndex()
+
+	assert.Equal(t, []string{"A new release of Go"}, searchTitles(idx, "release go"))
+	assert.Empty(t, searchTitles(idx, "release weekly"))
+	assert.ElementsMatch(t, []string{"A new release of Go", "Weekly notes"}, searchTitles(idx, "go OR weekly"))
+	assert.Equal(t, []string{"A new release of Go"}, searchTitles(idx, "release -kubernetes"))
+	assert.ElementsMatch(t, []string{"A new release of Go", "Weekly notes"}, searchTitles(idx, "-author:jane"))
+	assert.Equal(t, []string{"Release of the new Kubernetes"}, searchTitles(idx, "+new weekly OR clusters"))
+	assert.Empty(t, searchTitles(idx, "to"))
+	assert.Empty(t, searchTitlesFuzzy(idx, "to", true))
+}
+
+func Test_Index_Search_Language(t *testing.T) {
+	english, err := utils.NewAnalyzer("english")
+	assert.NoError(t, err)
+	idx := newTestIndexWithAnalyzer(english)
+
+	assert.ElementsMatch(t, []string{"A new release of Go", "Release of the new Kubernetes"}, searchTitles(idx, "releases"))
+	assert.Equal(t, []string{"Release of the new Kubernetes"}, searchTitles(idx, "the cluster"))
+	assert.Equal(t, []string{"Release of the new Kubernetes"}, searchTitles(idx, `"release of the new"`))
+	assert.ElementsMatch(t, []string{"A new release of Go", "Release of the new Kubernetes"}, searchTitles(idx, "of"))
+	assert.Equal(t, []string{"Release of the new Kubernetes"}, searchTitles(idx, "release title:the"))
+
+	q := mustParseQuery("the cluster -of", english)
+	assert.Equal(t, [][]*Clause{{{Terms: []string{"cluster"}}}}, q.Groups)
+	assert.Equal(t, []*Clause{{Terms: []string{"of"}}}, q.Excluded)
+}
+
+func Test_Index_Add(t *testing.T) {
+	idx := NewIndex(plain)
+	idx.Add("0", map[string]string{FieldTitle: "Atom Feed", FieldDescription: "An Atom feed"})
+	idx.Add("1", map[string]string{FieldTitle: "RSS Feed"})
+
+	results := idx.Search(mustParseQuery("atom", plain))
+	assert.Len(t, results, 1)
+	assert.Contains(t, results, "0")
+	assert.Empty(t, idx.Feeds)
+}
+
+func Test_Index_Update_Remove(t *testing.T) {
+	idx := newTestIndex()
+
+	idx.UpdateFeed("feed-2", []*gofeed.Item{
+		{
+			GUID:  "4",
+			Title: "Monthly notes",
+		},
+	})
+	assert.Empty(t, searchTitles(idx, "weekly"))
+	assert.Equal(t, []string{"Monthly notes"}, searchTitles(idx, "notes"))
+
+	idx.RemoveFeed("feed-2")
+	assert.False(t, idx.HasFeed("feed-2"))
+	assert.Empty(t, searchTitles(idx, "notes"))
+	assert.Len(t, idx.Docs, 2)
+}
+
+func Test_Index_Encode_Decode(t *testing.T) {
+	idx := newTestIndex()
+
+	b := new(bytes.Buffer)
+	err := idx.Encode(b)
+	assert.NoError(t, err)
+
+	decoded, err := DecodeIndex(b)
+	assert.NoError(t, err)
+	assert.Equal(t, idx, decoded)
+	assert.Equal(t, []string{"A new release of Go"}, searchTitles(decoded, `"new release"`))
+}
+
+func Test_Index_Field_Weights(t *testing.T) {
+	idx := NewIndex(plain)
+	idx.UpdateFeed("feed", []*gofeed.Item{
+		{GUID: "1", Title: "Weekly digest", Content: "Notes about rust"},
+		{GUID: "2", Title: "Weekly rust digest"},
+		{GUID: "3", Title: "Weekly digest", Categories: []string{"rust"}},
+		{GUID: "4", Title: "Weekly digest", Categories: []string{"rustacean"}},
+	})
+
+	assert.Equal(t, []string{"Weekly rust digest", "Weekly digest", "Weekly digest"}, searchTitles(idx, "rust"))
+	results := idx.Search(mustParseQuery("rust*", plain))
+	assert.Greater(t, results[DocKey("feed", &gofeed.Item{GUID: "3", Title: "Weekly digest"})].Score,
+		results[DocKey("feed", &gofeed.Item{GUID: "4", Title: "Weekly digest"})].Score)
+	assert.Greater(t, results[DocKey("feed", &gofeed.Item{GUID: "3", Title: "Weekly digest"})].Score,
+		results[DocKey("feed", &gofeed.Item{GUID: "1", Title: "Weekly digest"})].Score)
+}
@@ -0,0 +1,170 @@
+package search
+
+import (
+	"fmt"
+	"slices"
+	"strings"
+	"unicode"
+
+	"github.com/radulucut/cleed/internal/utils"
+)
+
+const (
+	FieldTitle       = "title"
+	FieldDescription = "description"
+	FieldContent     = "content"
+	FieldAuthor      = "author"
+	FieldCategory    = "category"
+)
+
+var Fields = []string{
+	FieldTitle,
+	FieldDescription,
+	FieldContent,
+	FieldAuthor,
+	FieldCategory,
+}
+
+type Clause struct {
+	Field  string   // empty matches any field
+	Terms  []string // more than one term is a phrase
+	Prefix bool     // the last term is a prefix
+}
+
+type Query struct {
+	Groups   [][]*Clause // every group must match one of its clauses
+	Excluded []*Clause
+	Fuzzy    bool // single terms also match similar terms
+
+	analyzer *utils.Analyzer
+}
+
+// ParseQuery parses a query made of terms, "quoted phrases", prefixes
+// (term*) and field qualifiers (title:term, author:"a phrase"). Clauses must
+// all match unless they are joined by OR. A clause prefixed with - must not
+// match and one prefixed with + is required. Excluded clauses cannot be
+// joined by OR. Terms are analyzed with the analyzer of the index and stop
+// words are ignored, unless the query is made only of stop words.
+func ParseQuery(s string, analyzer *utils.Analyzer) (*Query, error) {
+	q := &Query{analyzer: analyzer}
+	rs := []rune(s)
+	or := false
+	lastExcluded := "" // the text of the previous clause if it is excluded
+	stopWords := make([]*Clause, 0)
+	for i := 0; i < len(rs); {
+		if unicode.IsSpace(rs[i]) {
+			i++
+			continue
+		}
+		excluded := false
+		required := false
+		if (rs[i] == '-' || rs[i] == '+') && i+1 < len(rs) && !unicode.IsSpace(rs[i+1]) {
+			excluded = rs[i] == '-'
+			required = rs[i] == '+'
+			i++
+		}
+		clause := &Clause{}
+		if field, n := parseField(rs[i:]); n > 0 {
+			clause.Field = field
+			i += n
+		}
+		var text string
+		quoted := i < len(rs) && rs[i] == '"'
+		if quoted {
+			end := slices.Index(rs[i+1:], '"')
+			if end == -1 {
+				text = string(rs[i+1:])
+				i = len(rs)
+			} else {
+				text = string(rs[i+1 : i+1+end])
+				i += end + 2
+			}
+		} else {
+			start := i
+			for i < len(rs) && !unicode.IsSpace(rs[i]) {
+				i++
+			}
+			text = string(rs[start:i])
+			if text == "OR" && !excluded && !required && clause.Field == "" {
+				if lastExcluded != "" {
+					return nil, fmt.Errorf("excluded terms cannot be joined by OR: -%s", lastExcluded)
+				}
+				or = len(q.Groups) > 0
+				continue
+			}
+			if strings.HasSuffix(text, "*") {
+				clause.Prefix = true
+				text = strings.TrimRight(text, "*")
+			}
+		}
+		tokens := analyzer.Tokens(text)
+		if len(tokens) == 0 {
+			continue
+		}
+		for _, token := range tokens {
+			clause.Terms = append(clause.Terms, token.Term)
+		}
+		if len(tokens) == 1 && tokens[0].Stop && !clause.Prefix && !excluded && clause.Field == "" {
+			stopWords = append(stopWords, clause)
+			continue
+		}
+		lastExcluded = ""
+		switch {
+		case excluded:
+			if or {
+				return nil, fmt.Errorf("excluded terms cannot be joined by OR: -%s", text)
+			}
+			lastExcluded = text
+			q.Excluded = append(q.Excluded, clause)
+		case or && !required:
+			last := len(q.Groups) - 1
+			q.Groups[last] = append(q.Groups[last], clause)
+		default:
+			q.Groups = append(q.Groups, []*Clause{clause})
+		}
+		or = false
+	}
+	if q.IsEmpty() {
+		for _, clause := range stopWords {
+			q.Groups = append(q.Groups, []*Clause{clause})
+		}
+	}
+	return q, nil
+}
+
+func parseField(rs []rune) (string, int) {
+	i := slices.Index(rs, ':')
+	if i <= 0 {
+		return "", 0
+	}
+	field := strings.ToLower(string(rs[:i]))
+	if !slices.Contains(Fields, field) {
+		return "", 0
+	}
+	return field, i + 1
+}
+
+func (q *Query) Analyzer() *utils.Analyzer {
+	return q.analyzer
+}
+
+// And returns a query matching the items that match both queries.
+func (q *Query) And(other *Query) *Query {
+	return &Query{
+		Groups:   slices.Concat(q.Groups, other.Groups),
+		Excluded: slices.Concat(q.Excluded, other.Excluded),
+		Fuzzy:    q.Fuzzy || other.Fuzzy,
+		analyzer: q.analyzer,
+	}
+}
+
+func (q *Query) IsEmpty() bool {
+	return len(q.Groups) == 0 && len(q.Excluded) == 0
+}
+
+func (c *Clause) fields() []string {
+	if c.Field != "" {
+		return []string{c.Field}
+	}
+	return Fields
+}
@@ -0,0 +1,154 @@
+package internal
+
+import (
+	"fmt"
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/radulucut/cleed/internal/storage"
+	"github.com/radulucut/cleed/internal/utils"
+)
+
+// staleFailedFetches is the number of consecutive failed fetches after which
+// a feed is considered dead.
+const staleFailedFetches = 3
+
+type StaleFeed struct {
+	URL    string
+	Lists  []string
+	Reason string
+}
+
+// ShowStaleFeeds reports the feeds that did not publish anything for longer
+// than the threshold, that failed to fetch several times in a row or that
+// have no items. When archive is set, the feeds are moved to that list.
+// Without a list, all lists are checked except the archive list.
+func (f *TerminalFeed) ShowStaleFeeds(list string, threshold time.Duration, archive string, move bool) error {
+	if threshold <= 0 {
+		return utils.NewInternalError("please provide a duration greater than 0 (e.g. 180d)")
+	}
+	if move && archive == "" {
+		return utils.NewInternalError("please provide the archive list with --to")
+	}
+	lists, err := f.storage.LoadLists()
+	if err != nil {
+		return utils.NewInternalError("failed to load lists: " + err.Error())
+	}
+	slices.SortFunc(lists, storage.CompareLists)
+	if list != "" {
+		if !slices.ContainsFunc(lists, func(l string) bool {
+			return storage.IsInList(l, list)
+		}) {
+			return utils.NewInternalError("list not found: " + list)
+		}
+		if move && storage.IsInList(list, archive) {
+			return utils.NewInternalError("source and destination lists are the same: " + archive)
+		}
+	}
+	stale := make([]*StaleFeed, 0)
+	byURL := make(map[string]*StaleFeed)
+	checked := make(map[string]bool)
+	for _, l := range lists {
+		if list != "" && !storage.IsInList(l, list) {
+			continue
+		}
+		if list == "" && archive != "" && storage.IsInList(l, archive) {
+			continue
+		}
+		feeds, err := f.storage.GetFeedsFromList(l)
+		if err != nil {
+			return utils.NewInternalError("failed to list feeds: " + err.Error())
+		}
+		for _, item := range feeds {
+			if sf, ok := byURL[item.Address]; ok {
+				sf.Lists = append(sf.Lists, l)
+				continue
+			}
+			if checked[item.Address] {
+				continue
+			}
+			checked[item.Address] = true
+			reason := f.staleReason(item.Address, threshold)
+			if reason == "" {
+				continue
+			}
+			sf := &StaleFeed{
+				URL:    item.Address,
+				Lists:  []string{l},
+				Reason: reason,
+			}
+			byURL[item.Address] = sf
+			stale = append(stale, sf)
+		}
+	}
+	if len(stale) == 0 {
+		f.printer.Println("no stale feeds found")
+		return nil
+	}
+	slices.SortFunc(stale, func(a, b *StaleFeed) int {
+		return strings.Compare(a.URL, b.URL)
+	})
+	rows := make([][]string, 0, len(stale))
+	for _, sf := range stale {
+		rows = append(rows, []string{sf.URL, strings.Join(sf.Lists, ", "), sf.Reason})
+	}
+	f.printTable([]string{"URL", "Lists", "Reason"}, rows)
+	f.printer.Printf("Total: %s\n", utils.Pluralize(int64(len(stale)), "stale feed"))
+	if !move {
+		return nil
+	}
+	urlsByList := make(map[string][]string)
+	for _, sf := range stale {
+		for _, l := range sf.Lists {
+			urlsByList[l] = append(urlsByList[l], sf.URL)
+		}
+	}
+	for _, l := range lists {
+		urls, ok := urlsByList[l]
+		if !ok {
+			continue
+		}
+		err = f.storage.MoveFeeds(urls, l, archive)
+		if err != nil {
+			return utils.NewInternalError("failed to save feeds: " + err.Error())
+		}
+	}
+	f.printer.Printf("moved %s to %s\n", utils.Pluralize(int64(len(stale)), "feed"), archive)
+	return nil
+}
+
+// staleReason returns why the feed is stale or an empty string if it is not.
+// Feeds that were never fetched are not reported.
+func (f *TerminalFeed) staleReason(url string, threshold time.Duration) string {
+	history, err := f.storage.LoadFetchHistory(url)
+	if err == nil {
+		failed := 0
+		for i := len(history) - 1; i >= 0 && history[i].Error != ""; i-- {
+			failed++
+		}
+		if failed >= staleFailedFetches {
+			return fmt.Sprintf("failed %d times in a row: %s", failed, history[len(history)-1].Error)
+		}
+	}
+	feed, err := f.parseFeed(url)
+	if err != nil {
+		return ""
+	}
+	if len(feed.Items) == 0 {
+		return "no items"
+	}
+	newest := time.Time{}
+	for _, item := range feed.Items {
+		if item.PublishedParsed != nil && item.PublishedParsed.After(newest) {
+			newest = *item.PublishedParsed
+		}
+	}
+	if newest.IsZero() {
+		return ""
+	}
+	if age := f.time.Now().Sub(newest); age > threshold {
+		return "last item " + utils.Relative(int64(age.Seconds()))
+	}
+	return ""
+}
@@ -0,0 +1,284 @@
+package internal
+
+import (
+	"cmp"
+	"fmt"
+	"slices"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/mattn/go-runewidth"
+	"github.com/radulucut/cleed/internal/storage"
+	"github.com/radulucut/cleed/internal/utils"
+)
+
+var (
+	ListStatsSortOptions = []string{"name", "feeds", "items", "unread", "newest", "failing"}
+	FeedStatsSortOptions = []string{"added", "title", "url", "fetched", "frequency", "newest"}
+)
+
+type FeedStats struct {
+	URL       string
+	Title     string
+	AddedAt   time.Time
+	LastFetch time.Time
+	Items     int
+	Unread    int       // cached items that were not marked as read
+	Newest    time.Time // publish date of the newest cached item
+	PerWeek   float64   // average number of items published per week
+	Failing   bool      // the last fetch failed
+	Paused    bool
+}
+
+type ListStats struct {
+	Name    string
+	Feeds   int
+	Items   int
+	Unread  int
+	Newest  time.Time
+	Failing int
+}
+
+// ShowListStats prints statistics for every list.
+func (f *TerminalFeed) ShowListStats(sort string) error {
+	if err := validateStatsSort(sort, ListStatsSortOptions); err != nil {
+		return err
+	}
+	lists, err := f.storage.LoadLists()
+	if err != nil {
+		return utils.NewInternalError("failed to load lists: " + err.Error())
+	}
+	cacheInfo, err := f.storage.LoadCacheInfo()
+	if err != nil {
+		return utils.NewInternalError("failed to load cache info: " + err.Error())
+	}
+	config, err := f.storage.LoadConfig()
+	if err != nil {
+		return utils.NewInternalError("failed to load config: " + err.Error())
+	}
+	stats := make([]*ListStats, 0, len(lists))
+	feedStats := make(map[string]*FeedStats)
+	for _, list := range lists {
+		feeds, err := f.storage.GetFeedsFromList(list)
+		if err != nil {
+			return utils.NewInternalError("failed to list feeds: " + err.Error())
+		}
+		ls := &ListStats{
+			Name:  list,
+			Feeds: len(feeds),
+		}
+		for _, item := range feeds {
+			fs, ok := feedStats[item.Address]
+			if !ok {
+				fs = f.feedStats(item, cacheInfo, config, list)
+				feedStats[item.Address] = fs
+			}
+			ls.Items += fs.Items
+			ls.Unread += fs.Unread
+			if fs.Newest.After(ls.Newest) {
+				ls.Newest = fs.Newest
+			}
+			if fs.Failing {
+				ls.Failing++
+			}
+		}
+		stats = append(stats, ls)
+	}
+	slices.SortStableFunc(stats, func(a, b *ListStats) int {
+		switch sort {
+		case "feeds":
+			return cmp.Compare(b.Feeds, a.Feeds)
+		case "items":
+			return cmp.Compare(b.Items, a.Items)
+		case "unread":
+			return cmp.Compare(b.Unread, a.Unread)
+		case "newest":
+			return b.Newest.Compare(a.Newest)
+		case "failing":
+			return cmp.Compare(b.Failing, a.Failing)
+		}
+		return storage.CompareLists(a.Name, b.Name)
+	})
+	rows := make([][]string, 0, len(stats))
+	for _, ls := range stats {
+		rows = append(rows, []string{
+			ls.Name,
+			strconv.Itoa(ls.Feeds),
+			strconv.Itoa(ls.Items),
+			strconv.Itoa(ls.Unread),
+			formatStatsTime(ls.Newest),
+			strconv.Itoa(ls.Failing),
+		})
+	}
+	f.printTable([]string{"List", "Feeds", "Items", "Unread", "Newest item", "Failing"}, rows)
+	// feeds in several lists are counted once
+	items, unread := 0, 0
+	for _, fs := range feedStats {
+		items += fs.Items
+		unread += fs.Unread
+	}
+	f.printer.Printf("Total: %s, %s, %s, %d unread\n",
+		utils.Pluralize(int64(len(stats)), "list"),
+		utils.Pluralize(int64(len(feedStats)), "feed"),
+		utils.Pluralize(int64(items), "item"),
+		unread,
+	)
+	return nil
+}
+
+// ShowFeedStats prints statistics for every feed in a list.
+func (f *TerminalFeed) ShowFeedStats(list, sort string) error {
+	if err := validateStatsSort(sort, FeedStatsSortOptions); err != nil {
+		return err
+	}
+	feeds, err := f.storage.GetFeedsFromList(list)
+	if err != nil {
+		return utils.NewInternalError("failed to list feeds: " + err.Error())
+	}
+	cacheInfo, err := f.storage.LoadCacheInfo()
+	if err != nil {
+		return utils.NewInternalError("failed to load cache info: " + err.Error())
+	}
+	config, err := f.storage.LoadConfig()
+	if err != nil {
+		return utils.NewInternalError("failed to load config: " + err.Error())
+	}
+	stats := make([]*FeedStats, 0, len(feeds))
+	for _, item := range feeds {
+		stats = append(stats, f.feedStats(item, cacheInfo, config, list))
+	}
+	slices.SortStableFunc(stats, func(a, b *FeedStats) int {
+		switch sort {
+		case "title":
+			return strings.Compare(strings.ToLower(a.Title), strings.ToLower(b.Title))
+		case "url":
+			return strings.Compare(a.URL, b.URL)
+		case "fetched":
+			return b.LastFetch.Compare(a.LastFetch)
+		case "frequency":
+			return cmp.Compare(b.PerWeek, a.PerWeek)
+		case "newest":
+			return b.Newest.Compare(a.Newest)
+		}
+		return a.AddedAt.Compare(b.AddedAt)
+	})
+	rows := make([][]string, 0, len(stats))
+	items, unread := 0, 0
+	for _, fs := range stats {
+		status := ""
+		if fs.Paused {
+			status = "(paused)"
+		} else if fs.Failing {
+			status = "(failing)"
+		}
+		rows = append(rows, []string{
+			runewidth.Truncate(cmp.Or(fs.Title, "-"), 30, "..."),
+			fs.URL,
+			formatStatsTime(fs.LastFetch),
+			formatFrequency(fs.PerWeek),
+			formatStatsTime(fs.Newest),
+			status,
+		})
+		items += fs.Items
+		unread += fs.Unread
+	}
+	f.printTable([]string{"Title", "URL", "Last fetch", "Frequency", "Last item", ""}, rows)
+	f.printer.Printf("Total: %s, %s, %d unread\n",
+		utils.Pluralize(int64(len(stats)), "feed"),
+		utils.Pluralize(int64(items), "item"),
+		unread,
+	)
+	return nil
+}
+
+func (f *TerminalFeed) feedStats(
+	item *storage.ListItem,
+	cacheInfo map[string]*storage.CacheInfoItem,
+	config *storage.Config,
+	list string,
+) *FeedStats {
+	fs := &FeedStats{
+		URL:     item.Address,
+		AddedAt: item.AddedAt,
+		Paused:  config.IsFeedPaused(item.Address, []string{list}),
+	}
+	if ci := cacheInfo[item.Address]; ci != nil && ci.LastFetch.Unix() > 0 {
+		fs.LastFetch = ci.LastFetch
+	}
+	history, err := f.storage.LoadFetchHistory(item.Address)
+	if err == nil && len(history) > 0 {
+		fs.Failing = history[len(history)-1].Error != ""
+	}
+	feed, err := f.parseFeed(item.Address)
+	if err != nil {
+		return fs
+	}
+	fs.Title = strings.TrimSpace(feed.Title)
+	fs.Items = len(feed.Items)
+	readState, _ := f.storage.LoadReadState(item.Address)
+	oldest := time.Time{}
+	for _, feedItem := range feed.Items {
+		if _, ok := readState[storage.ItemID(feedItem)]; !ok {
+			fs.Unread++
+		}
+		if feedItem.PublishedParsed == nil {
+			continue
+		}
+		published := *feedItem.PublishedParsed
+		if published.After(fs.Newest) {
+			fs.Newest = published
+		}
+		if oldest.IsZero() || published.Before(oldest) {
+			oldest = published
+		}
+	}
+	if span := fs.Newest.Sub(oldest); fs.Items > 1 && span > 0 {
+		fs.PerWeek = float64(fs.Items-1) / (span.Hours() / (24 * 7))
+	}
+	return fs
+}
+
+// printTable prints rows with the columns aligned to the widest cell.
+func (f *TerminalFeed) printTable(header []string, rows [][]string) {
+	widths := make([]int, len(header))
+	for _, row := range append([][]string{header}, rows...) {
+		for i, cell := range row {
+			widths[i] = max(widths[i], runewidth.StringWidth(cell))
+		}
+	}
+	for _, row := range append([][]string{header}, rows...) {
+		cells := make([]string, len(row))
+		for i, cell := range row {
+			if i < len(row)-1 {
+				cell = runewidth.FillRight(cell, widths[i])
+			}
+			cells[i] = cell
+		}
+		f.printer.Println(strings.TrimRight(strings.Join(cells, "  "), " "))
+	}
+}
+
+func validateStatsSort(sort string, options []string) error {
+	if sort != "" && !slices.Contains(options, sort) {
+		return utils.NewInternalError(fmt.Sprintf("invalid sort: %s. Use %s", sort, strings.Join(options, ", ")))
+	}
+	return nil
+}
+
+func formatStatsTime(t time.Time) string {
+	if t.IsZero() {
+		return "-"
+	}
+	return t.Format("2006-01-02 15:04:05")
+}
+
+func formatFrequency(perWeek float64) string {
+	if perWeek == 0 {
+		return "-"
+	}
+	if perWeek >= 7 {
+		return fmt.Sprintf("%.1f/day", perWeek/7)
+	}
+	return fmt.Sprintf("%.1f/week", perWeek)
+}
@@ -0,0 +1,146 @@
+package storage
+
+import (
+	"slices"
+	"time"
+
+	"github.com/mmcdole/gofeed"
+)
+
+type ArchiveItem struct {
+	ID        string
+	FirstSeen time.Time
+	Item      *gofeed.Item
+}
+
+type ArchiveRetention struct {
+	MaxAge   time.Duration // 0 keeps items regardless of age
+	MaxCount int           // 0 keeps any number of items
+}
+
+// ItemID identifies an item across fetches by GUID, falling back to link
+// and then title.
+func ItemID(item *gofeed.Item) string {
+	if item.GUID != "" {
+		return item.GUID
+	}
+	if item.Link != "" {
+		return item.Link
+	}
+	return item.Title
+}
+
+// LoadArchive returns the archived items within the configured retention.
+// Items beyond it are only removed from the cache when it is compacted.
+func (s *LocalStorage) LoadArchive(name string) ([]*ArchiveItem, error) {
+	archive, err := s.cacheStore().LoadArchive(name)
+	if err != nil {
+		return nil, err
+	}
+	config, err := s.LoadConfig()
+	if err != nil {
+		return nil, err
+	}
+	archive, _ = pruneArchive(archive, config.ArchiveRetention(), s.time.Now())
+	return archive, nil
+}
+
+// AddToArchive stores items that were not seen before and returns the
+// archived items within the retention. New items are appended to the cache,
+// which is compacted once the items beyond the retention outnumber the kept
+// ones.
+func (s *LocalStorage) AddToArchive(items []*gofeed.Item, name string, retention *ArchiveRetention) ([]*ArchiveItem, error) {
+	unlock, err := s.lock()
+	if err != nil {
+		return nil, err
+	}
+	defer unlock()
+	archive, err := s.cacheStore().LoadArchive(name)
+	if err != nil {
+		return nil, err
+	}
+	l := len(archive)
+	archive, added := mergeArchive(archive, items, s.time.Now())
+	kept, removed := pruneArchive(slices.Clone(archive), retention, s.time.Now())
+	if removed > 0 && removed >= len(kept) {
+		err = s.cacheStore().SaveArchive(kept, name)
+	} else if added > 0 {
+		err = s.cacheStore().AppendArchive(archive[l:], name)
+	}
+	if err != nil {
+		return nil, err
+	}
+	return kept, nil
+}
+
+// PruneArchive applies the retention and returns the number of removed items.
+func (s *LocalStorage) PruneArchive(name string, retention *ArchiveRetention) (int, error) {
+	unlock, err := s.lock()
+	if err != nil {
+		return 0, err
+	}
+	defer unlock()
+	archive, err := s.cacheStore().LoadArchive(name)
+	if err != nil {
+		return 0, err
+	}
+	archive, removed := pruneArchive(archive, retention, s.time.Now())
+	if removed == 0 {
+		return 0, nil
+	}
+	return removed, s.cacheStore().SaveArchive(archive, name)
+}
+
+func mergeArchive(archive []*ArchiveItem, items []*gofeed.Item, now time.Time) ([]*ArchiveItem, int) {
+	seen := make(map[string]struct{}, len(archive))
+	for i := range archive {
+		seen[archive[i].ID] = struct{}{}
+	}
+	added := 0
+	for _, item := range items {
+		id := ItemID(item)
+		if id == "" {
+			continue
+		}
+		if _, ok := seen[id]; ok {
+			continue
+		}
+		seen[id] = struct{}{}
+		archive = append(archive, &ArchiveItem{
+			ID:        id,
+			FirstSeen: now,
+			Item:      item,
+		})
+		added++
+	}
+	return archive, added
+}
+
+// pruneArchive keeps the newest items, ordered by published date and
+// falling back to the first seen date.
+func pruneArchive(archive []*ArchiveItem, retention *ArchiveRetention, now time.Time) ([]*ArchiveItem, int) {
+	if retention == nil {
+		return archive, 0
+	}
+	l := len(archive)
+	if retention.MaxAge > 0 {
+		since := now.Add(-retention.MaxAge)
+		archive = slices.DeleteFunc(archive, func(item *ArchiveItem) bool {
+			return item.Date().Before(since)
+		})
+	}
+	if retention.MaxCount > 0 && len(archive) > retention.MaxCount {
+		slices.SortStableFunc(archive, func(a, b *ArchiveItem) int {
+			return b.Date().Compare(a.Date())
+		})
+		archive = archive[:retention.MaxCount]
+	}
+	return archive, l - len(archive)
+}
+
+func (a *ArchiveItem) Date() time.Time {
+	if a.Item != nil && a.Item.PublishedParsed != nil && !a.Item.PublishedParsed.IsZero() {
+		return *a.Item.PublishedParsed
+	}
+	return a.FirstSeen
+}
@@ -1,12 +1,9 @@
 package storage
 
 import (
-	"bufio"
-	"encoding/gob"
 	"fmt"
 	"io"
 	"net/url"
-	"os"
 	"strconv"
 	"strings"
 	"time"
@@ -15,7 +12,14 @@ import (
 )
 
 const (
-	cacheInfoFile = "cache_info"
+	cacheInfoFile   = "cache_info"
+	searchIndexFile = "search_index"
+
+	CacheBackendFile = "file"
+	CacheBackendBolt = "bolt"
+
+	// maximum number of fetch history entries kept per feed
+	fetchHistoryLimit = 50
 )
 
 type CacheInfoItem struct {
@@ -25,137 +29,204 @@ type CacheInfoItem struct {
 	URL        string
 }
 
-func (s *LocalStorage) LoadCacheInfo() (map[string]*CacheInfoItem, error) {
-	cacheinfo := make(map[string]*CacheInfoItem)
-	path, err := s.JoinCacheDir(cacheInfoFile)
-	if err != nil {
-		return nil, err
+type FetchHistoryItem struct {
+	Time   time.Time
+	Status int // HTTP status code, 0 if the request failed
+	Error  string
+	Items  int
+}
+
+// CacheStore persists everything related to fetched feeds: cache metadata,
+// raw and parsed feeds, read state, downloaded items, fetch history, the item
+// archive and the search index.
+// Write operations are serialized by the caller (LocalStorage).
+type CacheStore interface {
+	LoadCacheInfo() (map[string]*CacheInfoItem, error)
+	SaveCacheInfo(cacheinfo map[string]*CacheInfoItem) error
+	SaveFeedCache(r io.Reader, name string) error
+	OpenFeedCache(name string) (io.ReadCloser, error)
+	SaveParsedFeedCache(feed *gofeed.Feed, name string) error
+	LoadParsedFeedCache(name string) (*gofeed.Feed, error)
+	HasParsedFeedCache(name string) bool
+	LoadReadState(name string) (map[string]time.Time, error)
+	SaveReadState(state map[string]time.Time, name string) error
+	LoadDownloads(name string) (map[string]time.Time, error)
+	SaveDownloads(downloads map[string]time.Time, name string) error
+	LoadFetchHistory(name string) ([]*FetchHistoryItem, error)
+	SaveFetchHistory(history []*FetchHistoryItem, name string) error
+	LoadArchive(name string) ([]*ArchiveItem, error)
+	SaveArchive(archive []*ArchiveItem, name string) error
+	AppendArchive(archive []*ArchiveItem, name string) error
+	OpenSearchIndex() (io.ReadCloser, error)
+	SaveSearchIndex(r io.Reader) error
+	RemoveFeedCaches(names []string) error
+}
+
+func (s *LocalStorage) cacheStore() CacheStore {
+	s.cacheMx.Lock()
+	defer s.cacheMx.Unlock()
+	if s.cache != nil {
+		return s.cache
 	}
-	f, err := os.Open(path)
-	if err != nil {
-		if os.IsNotExist(err) {
-			return cacheinfo, nil
-		}
-		return nil, err
+	backend := CacheBackendFile
+	config, err := s.LoadConfig()
+	if err == nil && config.CacheBackend != "" {
+		backend = config.CacheBackend
 	}
-	defer f.Close()
-	scanner := bufio.NewScanner(f)
-	for scanner.Scan() {
-		item, err := parseCacheInfoItem(scanner.Text())
-		if err != nil {
-			return nil, err
-		}
-		cacheinfo[item.URL] = item
+	s.cache = s.newCacheStore(backend)
+	return s.cache
+}
+
+func (s *LocalStorage) newCacheStore(backend string) CacheStore {
+	if backend == CacheBackendBolt {
+		return &boltCache{storage: s}
 	}
-	return cacheinfo, scanner.Err()
+	return &fileCache{storage: s}
+}
+
+func (s *LocalStorage) LoadCacheInfo() (map[string]*CacheInfoItem, error) {
+	return s.cacheStore().LoadCacheInfo()
 }
 
+// SaveCacheInfo replaces the cache info, overwriting the changes saved by
+// other processes since it was loaded. Use UpdateCacheInfo to change it.
 func (s *LocalStorage) SaveCacheInfo(cacheinfo map[string]*CacheInfoItem) error {
-	path, err := s.JoinCacheDir(cacheInfoFile)
+	unlock, err := s.lock()
 	if err != nil {
 		return err
 	}
-	f, err := os.Create(path)
+	defer unlock()
+	return s.cacheStore().SaveCacheInfo(cacheinfo)
+}
+
+// UpdateCacheInfo applies update to the cache info saved on disk and saves
+// it, so that the changes saved by other processes since it was loaded are
+// kept. Nothing is saved if update returns an error.
+func (s *LocalStorage) UpdateCacheInfo(update func(cacheinfo map[string]*CacheInfoItem) error) error {
+	unlock, err := s.lock()
 	if err != nil {
 		return err
 	}
-	defer f.Close()
-	for _, item := range cacheinfo {
-		_, err = f.Write(getCacheInfoItemLine(item))
-		if err != nil {
-			return err
-		}
-	}
-	return nil
-}
-
-func (s *LocalStorage) SaveFeedCache(r io.Reader, name string) error {
-	path, err := s.JoinCacheDir("feed_" + url.QueryEscape(name))
+	defer unlock()
+	cacheinfo, err := s.cacheStore().LoadCacheInfo()
 	if err != nil {
 		return err
 	}
-	f, err := os.Create(path)
+	err = update(cacheinfo)
 	if err != nil {
 		return err
 	}
-	defer f.Close()
-	_, err = io.Copy(f, r)
-	return err
+	return s.cacheStore().SaveCacheInfo(cacheinfo)
+}
+
+func (s *LocalStorage) SaveFeedCache(r io.Reader, name string) error {
+	return s.cacheStore().SaveFeedCache(r, name)
 }
 
 func (s *LocalStorage) OpenFeedCache(name string) (io.ReadCloser, error) {
-	path, err := s.JoinCacheDir("feed_" + url.QueryEscape(name))
-	if err != nil {
-		return nil, err
-	}
-	return os.Open(path)
+	return s.cacheStore().OpenFeedCache(name)
 }
 
 func (s *LocalStorage) SaveParsedFeedCache(feed *gofeed.Feed, name string) error {
-	path, err := s.JoinCacheDir("parsed_" + url.QueryEscape(name))
+	return s.cacheStore().SaveParsedFeedCache(feed, name)
+}
+
+func (s *LocalStorage) LoadParsedFeedCache(name string) (*gofeed.Feed, error) {
+	return s.cacheStore().LoadParsedFeedCache(name)
+}
+
+func (s *LocalStorage) HasParsedFeedCache(name string) bool {
+	return s.cacheStore().HasParsedFeedCache(name)
+}
+
+// LoadReadState returns the read items of a feed keyed by item GUID or link.
+func (s *LocalStorage) LoadReadState(name string) (map[string]time.Time, error) {
+	return s.cacheStore().LoadReadState(name)
+}
+
+func (s *LocalStorage) MarkRead(ids []string, name string) error {
+	unlock, err := s.lock()
 	if err != nil {
 		return err
 	}
-	f, err := os.Create(path)
+	defer unlock()
+	state, err := s.cacheStore().LoadReadState(name)
 	if err != nil {
 		return err
 	}
-	defer f.Close()
-	encoder := gob.NewEncoder(f)
-	return encoder.Encode(feed)
+	now := s.time.Now()
+	for i := range ids {
+		state[ids[i]] = now
+	}
+	return s.cacheStore().SaveReadState(state, name)
 }
 
-func (s *LocalStorage) LoadParsedFeedCache(name string) (*gofeed.Feed, error) {
-	path, err := s.JoinCacheDir("parsed_" + url.QueryEscape(name))
+// LoadDownloads returns the items of a feed whose enclosures were downloaded,
+// keyed by item GUID or link.
+func (s *LocalStorage) LoadDownloads(name string) (map[string]time.Time, error) {
+	return s.cacheStore().LoadDownloads(name)
+}
+
+func (s *LocalStorage) MarkDownloaded(ids []string, name string) error {
+	unlock, err := s.lock()
 	if err != nil {
-		return nil, err
+		return err
 	}
-	f, err := os.Open(path)
+	defer unlock()
+	downloads, err := s.cacheStore().LoadDownloads(name)
 	if err != nil {
-		return nil, err
+		return err
 	}
-	defer f.Close()
-	decoder := gob.NewDecoder(f)
-	var feed gofeed.Feed
-	err = decoder.Decode(&feed)
-	if err != nil {
-		return nil, err
+	now := s.time.Now()
+	for i := range ids {
+		downloads[ids[i]] = now
 	}
-	return &feed, nil
+	return s.cacheStore().SaveDownloads(downloads, name)
 }
 
-func (s *LocalStorage) HasParsedFeedCache(name string) bool {
-	path, err := s.JoinCacheDir("parsed_" + url.QueryEscape(name))
+func (s *LocalStorage) LoadFetchHistory(name string) ([]*FetchHistoryItem, error) {
+	return s.cacheStore().LoadFetchHistory(name)
+}
+
+// AddFetchHistory records a fetch attempt, keeping only the most recent entries.
+func (s *LocalStorage) AddFetchHistory(item *FetchHistoryItem, name string) error {
+	unlock, err := s.lock()
+	if err != nil {
+		return err
+	}
+	defer unlock()
+	history, err := s.cacheStore().LoadFetchHistory(name)
 	if err != nil {
-		return false
+		return err
+	}
+	history = append(history, item)
+	if len(history) > fetchHistoryLimit {
+		history = history[len(history)-fetchHistoryLimit:]
 	}
-	_, err = os.Stat(path)
-	return err == nil
+	return s.cacheStore().SaveFetchHistory(history, name)
 }
 
-func (s *LocalStorage) RemoveFeedCaches(names []string) error {
-	cacheinfo, err := s.LoadCacheInfo()
+// OpenSearchIndex returns fs.ErrNotExist if the index was never saved.
+func (s *LocalStorage) OpenSearchIndex() (io.ReadCloser, error) {
+	return s.cacheStore().OpenSearchIndex()
+}
+
+func (s *LocalStorage) SaveSearchIndex(r io.Reader) error {
+	unlock, err := s.lock()
 	if err != nil {
 		return err
 	}
-	for i := range names {
-		delete(cacheinfo, names[i])
-	}
-	err = s.SaveCacheInfo(cacheinfo)
+	defer unlock()
+	return s.cacheStore().SaveSearchIndex(r)
+}
+
+func (s *LocalStorage) RemoveFeedCaches(names []string) error {
+	unlock, err := s.lock()
 	if err != nil {
 		return err
 	}
-	for i := range names {
-		rawPath, err := s.JoinCacheDir("feed_" + url.QueryEscape(names[i]))
-		if err == nil {
-			os.Remove(rawPath)
-		}
-		parsedPath, err := s.JoinCacheDir("parsed_" + url.QueryEscape(names[i]))
-		if err == nil {
-			os.Remove(parsedPath)
-		}
-	}
-	return nil
+	defer unlock()
+	return s.cacheStore().RemoveFeedCaches(names)
 }
 
 func getCacheInfoItemLine(item *CacheInfoItem) []byte {
@@ -0,0 +1,396 @@
+package storage
+
+import (
+	"bytes"
+	"encoding/gob"
+	"io"
+	"io/fs"
+	"os"
+	"time"
+
+	"github.com/mmcdole/gofeed"
+	bolt "go.etcd.io/bbolt"
+)
+
+const (
+	cacheDBFile = "cache.db"
+)
+
+var (
+	cacheInfoBucket    = []byte("cache_info")
+	feedBucket         = []byte("feed")
+	parsedBucket       = []byte("parsed")
+	readStateBucket    = []byte("read")
+	downloadsBucket    = []byte("downloads")
+	fetchHistoryBucket = []byte("history")
+	archiveBucket      = []byte("archive")
+	metaBucket         = []byte("meta")
+
+	boltBuckets = [][]byte{
+		cacheInfoBucket,
+		feedBucket,
+		parsedBucket,
+		readStateBucket,
+		downloadsBucket,
+		fetchHistoryBucket,
+		archiveBucket,
+		metaBucket,
+	}
+)
+
+// boltCache keeps the cache in a single bbolt database. The database is
+// opened for each operation and closed before returning, read-only for
+// reads, so that other cleed processes are only blocked while a write is in
+// progress. The database file lock is always taken after the storage lock
+// and never held while waiting for it.
+type boltCache struct {
+	storage *LocalStorage
+}
+
+func (c *boltCache) open(readOnly bool) (*bolt.DB, error) {
+	path, err := c.storage.JoinCacheDir(cacheDBFile)
+	if err != nil {
+		return nil, err
+	}
+	return bolt.Open(path, 0600, &bolt.Options{Timeout: 30 * time.Second, ReadOnly: readOnly})
+}
+
+func (c *boltCache) update(fn func(tx *bolt.Tx) error) error {
+	db, err := c.open(false)
+	if err != nil {
+		return err
+	}
+	defer db.Close()
+	return db.Update(func(tx *bolt.Tx) error {
+		for _, name := range boltBuckets {
+			_, err := tx.CreateBucketIfNotExists(name)
+			if err != nil {
+				return err
+			}
+		}
+		return fn(tx)
+	})
+}
+
+// view does not call fn if the database does not exist yet.
+func (c *boltCache) view(fn func(tx *bolt.Tx) error) error {
+	db, err := c.open(true)
+	if err != nil {
+		if os.IsNotExist(err) {
+			return nil
+		}
+		return err
+	}
+	defer db.Close()
+	return db.View(fn)
+}
+
+// get copies the value because it is only valid during the transaction.
+func (c *boltCache) get(bucket []byte, key string) ([]byte, error) {
+	var value []byte
+	err := c.view(func(tx *bolt.Tx) error {
+		b := tx.Bucket(bucket)
+		if b == nil {
+			return nil
+		}
+		if v := b.Get([]byte(key)); v != nil {
+			value = bytes.Clone(v)
+		}
+		return nil
+	})
+	return value, err
+}
+
+func (c *boltCache) put(bucket []byte, key string, value []byte) error {
+	return c.update(func(tx *bolt.Tx) error {
+		return tx.Bucket(bucket).Put([]byte(key), value)
+	})
+}
+
+func (c *boltCache) putGob(bucket []byte, key string, v any) error {
+	b := new(bytes.Buffer)
+	err := gob.NewEncoder(b).Encode(v)
+	if err != nil {
+		return err
+	}
+	return c.put(bucket, key, b.Bytes())
+}
+
+// getGob returns fs.ErrNotExist if the key is missing.
+func (c *boltCache) getGob(bucket []byte, key string, v any) error {
+	value, err := c.get(bucket, key)
+	if err != nil {
+		return err
+	}
+	if value == nil {
+		return fs.ErrNotExist
+	}
+	return gob.NewDecoder(bytes.NewReader(value)).Decode(v)
+}
+
+func (c *boltCache) LoadCacheInfo() (map[string]*CacheInfoItem, error) {
+	cacheinfo := make(map[string]*CacheInfoItem)
+	err := c.view(func(tx *bolt.Tx) error {
+		b := tx.Bucket(cacheInfoBucket)
+		if b == nil {
+			return nil
+		}
+		return b.ForEach(func(k, v []byte) error {
+			item := &CacheInfoItem{}
+			err := gob.NewDecoder(bytes.NewReader(v)).Decode(item)
+			if err != nil {
+				return err
+			}
+			cacheinfo[string(k)] = item
+			return nil
+		})
+	})
+	return cacheinfo, err
+}
+
+func (c *boltCache) SaveCacheInfo(cacheinfo map[string]*CacheInfoItem) error {
+	return c.update(func(tx *bolt.Tx) error {
+		err := tx.DeleteBucket(cacheInfoBucket)
+		if err != nil {
+			return err
+		}
+		b, err := tx.CreateBucket(cacheInfoBucket)
+		if err != nil {
+			return err
+		}
+		for url, item := range cacheinfo {
+			buf := new(bytes.Buffer)
+			err = gob.NewEncoder(buf).Encode(item)
+			if err != nil {
+				return err
+			}
+			err = b.Put([]byte(url), buf.Bytes())
+			if err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+}
+
+func (c *boltCache) SaveFeedCache(r io.Reader, name string) error {
+	b, err := io.ReadAll(r)
+	if err != nil {
+		return err
+	}
+	return c.put(feedBucket, name, b)
+}
+
+func (c *boltCache) OpenFeedCache(name string) (io.ReadCloser, error) {
+	b, err := c.get(feedBucket, name)
+	if err != nil {
+		return nil, err
+	}
+	if b == nil {
+		return nil, fs.ErrNotExist
+	}
+	return io.NopCloser(bytes.NewReader(b)), nil
+}
+
+func (c *boltCache) SaveParsedFeedCache(feed *gofeed.Feed, name string) error {
+	return c.putGob(parsedBucket, name, feed)
+}
+
+func (c *boltCache) LoadParsedFeedCache(name string) (*gofeed.Feed, error) {
+	var feed gofeed.Feed
+	err := c.getGob(parsedBucket, name, &feed)
+	if err != nil {
+		return nil, err
+	}
+	return &feed, nil
+}
+
+func (c *boltCache) HasParsedFeedCache(name string) bool {
+	b, err := c.get(parsedBucket, name)
+	return err == nil && b != nil
+}
+
+func (c *boltCache) LoadReadState(name string) (map[string]time.Time, error) {
+	state := make(map[string]time.Time)
+	err := c.getGob(readStateBucket, name, &state)
+	if err != nil && err != fs.ErrNotExist {
+		return nil, err
+	}
+	return state, nil
+}
+
+func (c *boltCache) SaveReadState(state map[string]time.Time, name string) error {
+	return c.putGob(readStateBucket, name, state)
+}
+
+func (c *boltCache) LoadDownloads(name string) (map[string]time.Time, error) {
+	downloads := make(map[string]time.Time)
+	err := c.getGob(downloadsBucket, name, &downloads)
+	if err != nil && err != fs.ErrNotExist {
+		return nil, err
+	}
+	return downloads, nil
+}
+
+func (c *boltCache) SaveDownloads(downloads map[string]time.Time, name string) error {
+	return c.putGob(downloadsBucket, name, downloads)
+}
+
+func (c *boltCache) LoadFetchHistory(name string) ([]*FetchHistoryItem, error) {
+	history := make([]*FetchHistoryItem, 0)
+	err := c.getGob(fetchHistoryBucket, name, &history)
+	if err != nil && err != fs.ErrNotExist {
+		return nil, err
+	}
+	return history, nil
+}
+
+func (c *boltCache) SaveFetchHistory(history []*FetchHistoryItem, name string) error {
+	return c.putGob(fetchHistoryBucket, name, history)
+}
+
+// The archived items of a feed are kept in a nested bucket, keyed by the
+// item ID, so that new items are added without rewriting the others.
+func (c *boltCache) LoadArchive(name string) ([]*ArchiveItem, error) {
+	archive := make([]*ArchiveItem, 0)
+	err := c.view(func(tx *bolt.Tx) error {
+		b := tx.Bucket(archiveBucket)
+		if b == nil {
+			return nil
+		}
+		b = b.Bucket([]byte(name))
+		if b == nil {
+			return nil
+		}
+		return b.ForEach(func(_, v []byte) error {
+			item := &ArchiveItem{}
+			err := gob.NewDecoder(bytes.NewReader(v)).Decode(item)
+			if err != nil {
+				return err
+			}
+			archive = append(archive, item)
+			return nil
+		})
+	})
+	return archive, err
+}
+
+func (c *boltCache) SaveArchive(archive []*ArchiveItem, name string) error {
+	return c.update(func(tx *bolt.Tx) error {
+		err := tx.Bucket(archiveBucket).DeleteBucket([]byte(name))
+		if err != nil && err != bolt.ErrBucketNotFound {
+			return err
+		}
+		return putArchiveItems(tx, archive, name)
+	})
+}
+
+func (c *boltCache) AppendArchive(archive []*ArchiveItem, name string) error {
+	return c.update(func(tx *bolt.Tx) error {
+		return putArchiveItems(tx, archive, name)
+	})
+}
+
+func putArchiveItems(tx *bolt.Tx, archive []*ArchiveItem, name string) error {
+	b, err := tx.Bucket(archiveBucket).CreateBucketIfNotExists([]byte(name))
+	if err != nil {
+		return err
+	}
+	for _, item := range archive {
+		buf := new(bytes.Buffer)
+		err = gob.NewEncoder(buf).Encode(item)
+		if err != nil {
+			return err
+		}
+		err = b.Put([]byte(item.ID), buf.Bytes())
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (c *boltCache) OpenSearchIndex() (io.ReadCloser, error) {
+	b, err := c.get(metaBucket, searchIndexFile)
+	if err != nil {
+		return nil, err
+	}
+	if b == nil {
+		return nil, fs.ErrNotExist
+	}
+	return io.NopCloser(bytes.NewReader(b)), nil
+}
+
+func (c *boltCache) SaveSearchIndex(r io.Reader) error {
+	b, err := io.ReadAll(r)
+	if err != nil {
+		return err
+	}
+	return c.put(metaBucket, searchIndexFile, b)
+}
+
+func (c *boltCache) RemoveFeedCaches(names []string) error {
+	return c.update(func(tx *bolt.Tx) error {
+		for _, bucket := range boltBuckets {
+			b := tx.Bucket(bucket)
+			for i := range names {
+				var err error
+				if bytes.Equal(bucket, archiveBucket) {
+					err = b.DeleteBucket([]byte(names[i]))
+					if err == bolt.ErrBucketNotFound {
+						err = nil
+					}
+				} else {
+					err = b.Delete([]byte(names[i]))
+				}
+				if err != nil {
+					return err
+				}
+			}
+		}
+		return nil
+	})
+}
+
+func (c *boltCache) exists() bool {
+	path, err := c.storage.JoinCacheDir(cacheDBFile)
+	if err != nil {
+		return false
+	}
+	_, err = os.Stat(path)
+	return err == nil
+}
+
+// names returns the feeds that have at least one cache entry.
+func (c *boltCache) names() ([]string, error) {
+	seen := make(map[string]struct{})
+	names := make([]string, 0)
+	err := c.view(func(tx *bolt.Tx) error {
+		for _, bucket := range boltBuckets {
+			b := tx.Bucket(bucket)
+			if b == nil || bytes.Equal(bucket, metaBucket) {
+				continue
+			}
+			err := b.ForEach(func(k, _ []byte) error {
+				if _, ok := seen[string(k)]; !ok {
+					seen[string(k)] = struct{}{}
+					names = append(names, string(k))
+				}
+				return nil
+			})
+			if err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+	return names, err
+}
+
+func (c *boltCache) clear(_ []string) error {
+	path, err := c.storage.JoinCacheDir(cacheDBFile)
+	if err != nil {
+		return err
+	}
+	return os.Remove(path)
+}
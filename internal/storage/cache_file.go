@@ -0,0 +1,369 @@
+package storage
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/binary"
+	"encoding/gob"
+	"io"
+	"net/url"
+	"os"
+	"strings"
+	"time"
+
+	"github.com/mmcdole/gofeed"
+)
+
+const (
+	feedCachePrefix    = "feed_"
+	parsedCachePrefix  = "parsed_"
+	readStatePrefix    = "read_"
+	downloadsPrefix    = "downloads_"
+	fetchHistoryPrefix = "history_"
+	archivePrefix      = "archive_"
+)
+
+var fileCachePrefixes = []string{
+	feedCachePrefix,
+	parsedCachePrefix,
+	readStatePrefix,
+	downloadsPrefix,
+	fetchHistoryPrefix,
+	archivePrefix,
+}
+
+// fileCache keeps the cache as flat files in the cache directory.
+type fileCache struct {
+	storage *LocalStorage
+}
+
+func (c *fileCache) join(prefix, name string) (string, error) {
+	return c.storage.JoinCacheDir(prefix + url.QueryEscape(name))
+}
+
+func (c *fileCache) LoadCacheInfo() (map[string]*CacheInfoItem, error) {
+	cacheinfo := make(map[string]*CacheInfoItem)
+	path, err := c.storage.JoinCacheDir(cacheInfoFile)
+	if err != nil {
+		return nil, err
+	}
+	f, err := os.Open(path)
+	if err != nil {
+		if os.IsNotExist(err) {
+			return cacheinfo, nil
+		}
+		return nil, err
+	}
+	defer f.Close()
+	scanner := bufio.NewScanner(f)
+	for scanner.Scan() {
+		item, err := parseCacheInfoItem(scanner.Text())
+		if err != nil {
+			return nil, err
+		}
+		cacheinfo[item.URL] = item
+	}
+	return cacheinfo, scanner.Err()
+}
+
+func (c *fileCache) SaveCacheInfo(cacheinfo map[string]*CacheInfoItem) error {
+	path, err := c.storage.JoinCacheDir(cacheInfoFile)
+	if err != nil {
+		return err
+	}
+	b := new(bytes.Buffer)
+	for _, item := range cacheinfo {
+		b.Write(getCacheInfoItemLine(item))
+	}
+	return writeFileAtomic(path, b, 0644)
+}
+
+func (c *fileCache) SaveFeedCache(r io.Reader, name string) error {
+	path, err := c.join(feedCachePrefix, name)
+	if err != nil {
+		return err
+	}
+	return writeFileAtomic(path, r, 0644)
+}
+
+func (c *fileCache) OpenFeedCache(name string) (io.ReadCloser, error) {
+	path, err := c.join(feedCachePrefix, name)
+	if err != nil {
+		return nil, err
+	}
+	return os.Open(path)
+}
+
+func (c *fileCache) SaveParsedFeedCache(feed *gofeed.Feed, name string) error {
+	return c.saveGob(parsedCachePrefix, name, feed)
+}
+
+func (c *fileCache) LoadParsedFeedCache(name string) (*gofeed.Feed, error) {
+	var feed gofeed.Feed
+	err := c.loadGob(parsedCachePrefix, name, &feed)
+	if err != nil {
+		return nil, err
+	}
+	return &feed, nil
+}
+
+func (c *fileCache) HasParsedFeedCache(name string) bool {
+	path, err := c.join(parsedCachePrefix, name)
+	if err != nil {
+		return false
+	}
+	_, err = os.Stat(path)
+	return err == nil
+}
+
+func (c *fileCache) LoadReadState(name string) (map[string]time.Time, error) {
+	state := make(map[string]time.Time)
+	err := c.loadGob(readStatePrefix, name, &state)
+	if err != nil && !os.IsNotExist(err) {
+		return nil, err
+	}
+	return state, nil
+}
+
+func (c *fileCache) SaveReadState(state map[string]time.Time, name string) error {
+	return c.saveGob(readStatePrefix, name, state)
+}
+
+func (c *fileCache) LoadDownloads(name string) (map[string]time.Time, error) {
+	downloads := make(map[string]time.Time)
+	err := c.loadGob(downloadsPrefix, name, &downloads)
+	if err != nil && !os.IsNotExist(err) {
+		return nil, err
+	}
+	return downloads, nil
+}
+
+func (c *fileCache) SaveDownloads(downloads map[string]time.Time, name string) error {
+	return c.saveGob(downloadsPrefix, name, downloads)
+}
+
+func (c *fileCache) LoadFetchHistory(name string) ([]*FetchHistoryItem, error) {
+	history := make([]*FetchHistoryItem, 0)
+	err := c.loadGob(fetchHistoryPrefix, name, &history)
+	if err != nil && !os.IsNotExist(err) {
+		return nil, err
+	}
+	return history, nil
+}
+
+func (c *fileCache) SaveFetchHistory(history []*FetchHistoryItem, name string) error {
+	return c.saveGob(fetchHistoryPrefix, name, history)
+}
+
+// The archive file is a sequence of gob encoded batches of items, each
+// prefixed by its length, so that new items are appended without rewriting
+// the file.
+func (c *fileCache) LoadArchive(name string) ([]*ArchiveItem, error) {
+	archive := make([]*ArchiveItem, 0)
+	path, err := c.join(archivePrefix, name)
+	if err != nil {
+		return nil, err
+	}
+	f, err := os.Open(path)
+	if err != nil {
+		if os.IsNotExist(err) {
+			return archive, nil
+		}
+		return nil, err
+	}
+	defer f.Close()
+	r := bufio.NewReader(f)
+	for {
+		n, err := binary.ReadUvarint(r)
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			return nil, err
+		}
+		b := make([]byte, n)
+		_, err = io.ReadFull(r, b)
+		if err == io.ErrUnexpectedEOF {
+			// an interrupted append
+			break
+		}
+		if err != nil {
+			return nil, err
+		}
+		batch := make([]*ArchiveItem, 0)
+		err = gob.NewDecoder(bytes.NewReader(b)).Decode(&batch)
+		if err != nil {
+			return nil, err
+		}
+		archive = append(archive, batch...)
+	}
+	return archive, nil
+}
+
+func (c *fileCache) SaveArchive(archive []*ArchiveItem, name string) error {
+	path, err := c.join(archivePrefix, name)
+	if err != nil {
+		return err
+	}
+	b, err := encodeArchiveBatch(archive)
+	if err != nil {
+		return err
+	}
+	return writeFileAtomic(path, bytes.NewReader(b), 0644)
+}
+
+func (c *fileCache) AppendArchive(archive []*ArchiveItem, name string) error {
+	path, err := c.join(archivePrefix, name)
+	if err != nil {
+		return err
+	}
+	b, err := encodeArchiveBatch(archive)
+	if err != nil {
+		return err
+	}
+	f, err := os.OpenFile(path, os.O_CREATE|os.O_APPEND|os.O_WRONLY, 0644)
+	if err != nil {
+		return err
+	}
+	_, err = f.Write(b)
+	if closeErr := f.Close(); err == nil {
+		err = closeErr
+	}
+	return err
+}
+
+func encodeArchiveBatch(archive []*ArchiveItem) ([]byte, error) {
+	b := new(bytes.Buffer)
+	err := gob.NewEncoder(b).Encode(archive)
+	if err != nil {
+		return nil, err
+	}
+	return append(binary.AppendUvarint(nil, uint64(b.Len())), b.Bytes()...), nil
+}
+
+func (c *fileCache) OpenSearchIndex() (io.ReadCloser, error) {
+	path, err := c.storage.JoinCacheDir(searchIndexFile)
+	if err != nil {
+		return nil, err
+	}
+	return os.Open(path)
+}
+
+func (c *fileCache) SaveSearchIndex(r io.Reader) error {
+	path, err := c.storage.JoinCacheDir(searchIndexFile)
+	if err != nil {
+		return err
+	}
+	return writeFileAtomic(path, r, 0644)
+}
+
+func (c *fileCache) RemoveFeedCaches(names []string) error {
+	cacheinfo, err := c.LoadCacheInfo()
+	if err != nil {
+		return err
+	}
+	for i := range names {
+		delete(cacheinfo, names[i])
+	}
+	err = c.SaveCacheInfo(cacheinfo)
+	if err != nil {
+		return err
+	}
+	for i := range names {
+		for _, prefix := range fileCachePrefixes {
+			path, err := c.join(prefix, names[i])
+			if err == nil {
+				os.Remove(path)
+			}
+		}
+	}
+	return nil
+}
+
+// names returns the feeds that have at least one cache file.
+func (c *fileCache) names() ([]string, error) {
+	dir, err := c.storage.JoinCacheDir("")
+	if err != nil {
+		return nil, err
+	}
+	files, err := os.ReadDir(dir)
+	if err != nil {
+		return nil, err
+	}
+	seen := make(map[string]struct{})
+	names := make([]string, 0)
+	for _, file := range files {
+		for _, prefix := range fileCachePrefixes {
+			if !strings.HasPrefix(file.Name(), prefix) {
+				continue
+			}
+			name, err := url.QueryUnescape(strings.TrimPrefix(file.Name(), prefix))
+			if err != nil {
+				break
+			}
+			if _, ok := seen[name]; !ok {
+				seen[name] = struct{}{}
+				names = append(names, name)
+			}
+			break
+		}
+	}
+	return names, nil
+}
+
+func (c *fileCache) saveGob(prefix, name string, v any) error {
+	path, err := c.join(prefix, name)
+	if err != nil {
+		return err
+	}
+	b := new(bytes.Buffer)
+	err = gob.NewEncoder(b).Encode(v)
+	if err != nil {
+		return err
+	}
+	return writeFileAtomic(path, b, 0644)
+}
+
+func (c *fileCache) loadGob(prefix, name string, v any) error {
+	path, err := c.join(prefix, name)
+	if err != nil {
+		return err
+	}
+	f, err := os.Open(path)
+	if err != nil {
+		return err
+	}
+	defer f.Close()
+	return gob.NewDecoder(f).Decode(v)
+}
+
+func (c *fileCache) exists() bool {
+	path, err := c.storage.JoinCacheDir(cacheInfoFile)
+	if err != nil {
+		return false
+	}
+	_, err = os.Stat(path)
+	if err == nil {
+		return true
+	}
+	names, err := c.names()
+	return err == nil && len(names) > 0
+}
+
+func (c *fileCache) clear(names []string) error {
+	err := c.RemoveFeedCaches(names)
+	if err != nil {
+		return err
+	}
+	for _, file := range []string{cacheInfoFile, searchIndexFile} {
+		path, err := c.storage.JoinCacheDir(file)
+		if err != nil {
+			return err
+		}
+		err = os.Remove(path)
+		if err != nil && !os.IsNotExist(err) {
+			return err
+		}
+	}
+	return nil
+}
@@ -1,9 +1,14 @@
 package storage
 
 import (
+	"bytes"
 	"encoding/json"
 	"os"
+	"slices"
+	"strings"
 	"time"
+
+	"github.com/radulucut/cleed/internal/utils"
 )
 
 const (
@@ -17,19 +22,65 @@ type Config struct {
 	Timeout   uint   `json:"timeout"`   // in seconds
 	BatchSize uint   `json:"batchSize"` // number of feeds to fetch in a batch
 
+	CacheBackend string `json:"cacheBackend"` // file (default) or bolt
+
+	ArchiveMaxAge   uint `json:"archiveMaxAge"`   // in days, 0: unlimited
+	ArchiveMaxCount uint `json:"archiveMaxCount"` // items per feed, 0: unlimited
+
+	SearchLanguage string         `json:"searchLanguage"` // stemming and stop words, empty: none
+	FeedPriority   map[string]int `json:"feedPriority"`   // search ranking boost per feed URL
+
+	SavedSearches map[string]*SavedSearch `json:"savedSearches"`
+
+	PausedFeeds []string `json:"pausedFeeds"` // feed URLs that are not fetched or displayed
+	PausedLists []string `json:"pausedLists"` // lists that are not fetched or displayed, including nested lists
+
 	LastRun         time.Time       `json:"lastRun"`
-	Styling         uint8           `json:"styling"` // 0: default, 1: enabled, 2: disabled
-	Summary         uint8           `json:"summary"` // 0: disabled, 1: enabled
+	Styling         uint8           `json:"styling"`    // 0: default, 1: enabled, 2: disabled
+	Hyperlinks      uint8           `json:"hyperlinks"` // 0: detected, 1: enabled, 2: disabled
+	Summary         uint8           `json:"summary"`    // 0: disabled, 1: enabled
 	ColorMap        map[uint8]uint8 `json:"colorMap"`
+	Theme           string          `json:"theme"` // empty: default
 	HideFutureItems bool            `json:"hideFutureItems"`
+	Dedupe          bool            `json:"dedupe"` // collapse the same item from different feeds
+
+	Layout    string            `json:"layout"`    // built-in layout or template name, empty: default
+	Templates map[string]string `json:"templates"` // item templates by name
+
+	Pager       string `json:"pager"`       // command, empty: $PAGER or less -R, -: disabled
+	OpenCommand string `json:"openCommand"` // command to open links, empty: $BROWSER or the system opener
+
+	DownloadDir   string         `json:"downloadDir"`   // directory for enclosures, empty: ~/Downloads/cleed
+	AutoDownload  map[string]int `json:"autoDownload"`  // number of newest enclosures to download per feed URL
+	PlayerCommand string         `json:"playerCommand"` // command to play enclosures, empty: the system opener
 
 	MinifluxToken string `json:"minifluxToken"`
 }
 
+// SavedSearch is a named search that can be used in place of a list.
+type SavedSearch struct {
+	Query string `json:"query"`
+	List  string `json:"list,omitempty"`
+	Since string `json:"since,omitempty"` // as given on the command line, e.g. 7d or last
+	Sort  string `json:"sort,omitempty"`
+	Fuzzy bool   `json:"fuzzy,omitempty"`
+}
+
+// LoadConfig returns the config, which is read once and then shared by the
+// callers.
 func (s *LocalStorage) LoadConfig() (*Config, error) {
 	if s.config != nil {
 		return s.config, nil
 	}
+	config, err := s.readConfig()
+	if err != nil {
+		return nil, err
+	}
+	s.config = config
+	return s.config, nil
+}
+
+func (s *LocalStorage) readConfig() (*Config, error) {
 	configPath, err := s.JoinConfigDir(configFile)
 	if err != nil {
 		return nil, err
@@ -38,34 +89,148 @@ func (s *LocalStorage) LoadConfig() (*Config, error) {
 	if err != nil {
 		return nil, err
 	}
-	s.config = &Config{}
-	err = json.Unmarshal(b, s.config)
+	config := &Config{}
+	err = json.Unmarshal(b, config)
 	if err != nil {
 		return nil, err
 	}
-	if s.config.ColorMap == nil {
-		s.config.ColorMap = make(map[uint8]uint8)
+	if config.ColorMap == nil {
+		config.ColorMap = make(map[uint8]uint8)
 	}
-	if s.config.BatchSize == 0 {
-		s.config.BatchSize = 100
+	if config.BatchSize == 0 {
+		config.BatchSize = 100
 	}
-	if s.config.Timeout == 0 {
-		s.config.Timeout = 30
+	if config.Timeout == 0 {
+		config.Timeout = 30
 	}
-	return s.config, nil
+	return config, nil
 }
 
+// SaveConfig writes the loaded config as it is, overwriting the changes saved
+// by other processes since it was loaded. Use UpdateConfig to change it.
 func (s *LocalStorage) SaveConfig() error {
 	if s.config == nil {
 		return nil
 	}
+	unlock, err := s.lock()
+	if err != nil {
+		return err
+	}
+	defer unlock()
+	return s.writeConfig(s.config)
+}
+
+// UpdateConfig applies update to the config saved on disk and saves it, so
+// that the changes saved by other processes since the config was loaded are
+// kept. The loaded config is updated too. Nothing is saved if update returns
+// an error.
+func (s *LocalStorage) UpdateConfig(update func(config *Config) error) error {
+	unlock, err := s.lock()
+	if err != nil {
+		return err
+	}
+	defer unlock()
+	return s.updateConfig(update)
+}
+
+// updateConfig must be called while holding the storage lock.
+func (s *LocalStorage) updateConfig(update func(config *Config) error) error {
+	config, err := s.readConfig()
+	if err != nil {
+		return err
+	}
+	err = update(config)
+	if err != nil {
+		return err
+	}
+	err = s.writeConfig(config)
+	if err != nil {
+		return err
+	}
+	if s.config == nil {
+		s.config = config
+	} else {
+		*s.config = *config
+	}
+	return nil
+}
+
+// writeConfig must be called while holding the storage lock.
+func (s *LocalStorage) writeConfig(config *Config) error {
 	configPath, err := s.JoinConfigDir(configFile)
 	if err != nil {
 		return err
 	}
-	b, err := json.Marshal(s.config)
+	b, err := json.Marshal(config)
 	if err != nil {
 		return err
 	}
-	return os.WriteFile(configPath, b, 0600)
+	return writeFileAtomic(configPath, bytes.NewReader(b), 0600)
+}
+
+func (c *Config) ArchiveRetention() *ArchiveRetention {
+	return &ArchiveRetention{
+		MaxAge:   time.Duration(c.ArchiveMaxAge) * 24 * time.Hour,
+		MaxCount: int(c.ArchiveMaxCount),
+	}
+}
+
+// IsListPaused reports whether the list or one of its parents is paused.
+func (c *Config) IsListPaused(list string) bool {
+	return slices.ContainsFunc(c.PausedLists, func(paused string) bool {
+		return IsInList(list, paused)
+	})
+}
+
+// renamePausedLists renames the paused entries of a list and its nested
+// lists. It reports whether an entry was renamed.
+func (c *Config) renamePausedLists(oldName, newName string) bool {
+	renamed := false
+	paused := make([]string, 0, len(c.PausedLists))
+	for _, list := range c.PausedLists {
+		if IsInList(list, oldName) {
+			list = newName + strings.TrimPrefix(list, oldName)
+			renamed = true
+		}
+		if !slices.Contains(paused, list) {
+			paused = append(paused, list)
+		}
+	}
+	c.PausedLists = paused
+	return renamed
+}
+
+// removePausedList removes the paused entry of a list that no longer exists.
+// Its remaining nested lists are paused on their own so that they stay
+// paused. It reports whether the entry was removed.
+func (c *Config) removePausedList(list string, lists []string) bool {
+	i := slices.Index(c.PausedLists, list)
+	if i < 0 {
+		return false
+	}
+	c.PausedLists = slices.Delete(c.PausedLists, i, i+1)
+	for _, nested := range lists {
+		if nested != list && IsInList(nested, list) && !c.IsListPaused(nested) {
+			c.PausedLists = append(c.PausedLists, nested)
+		}
+	}
+	return true
+}
+
+// IsFeedPaused reports whether the feed is paused, either directly or
+// because all the given lists it belongs to are paused.
+func (c *Config) IsFeedPaused(url string, lists []string) bool {
+	key := utils.URLKey(url)
+	if slices.ContainsFunc(c.PausedFeeds, func(paused string) bool { return utils.URLKey(paused) == key }) {
+		return true
+	}
+	if len(lists) == 0 {
+		return false
+	}
+	for _, list := range lists {
+		if !c.IsListPaused(list) {
+			return false
+		}
+	}
+	return true
 }
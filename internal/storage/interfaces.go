@@ -0,0 +1,70 @@
+package storage
+
+import (
+	"io"
+	"time"
+
+	"github.com/mmcdole/gofeed"
+)
+
+type ListStorage interface {
+	AddToList(urls []string, list string) error
+	RemoveFromList(urls []string, list string) ([]bool, error)
+	GetFeedsFromList(list string) ([]*ListItem, error)
+	LoadFeedsFromList(m map[string]*ListItem, list string) error
+	LoadLists() ([]string, error)
+	RenameList(oldName, newName string) error
+	MergeLists(list, otherList string) error
+	MoveFeeds(urls []string, from, to string) error
+	CopyFeeds(urls []string, from, to string) error
+	RemoveList(list string) error
+	LoadJournal() ([]*JournalEntry, error)
+	Undo() (*JournalEntry, error)
+}
+
+type CacheStorage interface {
+	LoadCacheInfo() (map[string]*CacheInfoItem, error)
+	UpdateCacheInfo(update func(cacheinfo map[string]*CacheInfoItem) error) error
+	SaveFeedCache(r io.Reader, name string) error
+	OpenFeedCache(name string) (io.ReadCloser, error)
+	SaveParsedFeedCache(feed *gofeed.Feed, name string) error
+	LoadParsedFeedCache(name string) (*gofeed.Feed, error)
+	HasParsedFeedCache(name string) bool
+	LoadReadState(name string) (map[string]time.Time, error)
+	MarkRead(ids []string, name string) error
+	LoadDownloads(name string) (map[string]time.Time, error)
+	MarkDownloaded(ids []string, name string) error
+	LoadFetchHistory(name string) ([]*FetchHistoryItem, error)
+	AddFetchHistory(item *FetchHistoryItem, name string) error
+	LoadArchive(name string) ([]*ArchiveItem, error)
+	AddToArchive(items []*gofeed.Item, name string, retention *ArchiveRetention) ([]*ArchiveItem, error)
+	PruneArchive(name string, retention *ArchiveRetention) (int, error)
+	OpenSearchIndex() (io.ReadCloser, error)
+	SaveSearchIndex(r io.Reader) error
+	LoadTimeline() ([]*TimelineItem, error)
+	SaveTimeline(timeline []*TimelineItem) error
+	RemoveFeedCaches(names []string) error
+}
+
+type ConfigStorage interface {
+	LoadConfig() (*Config, error)
+	UpdateConfig(update func(config *Config) error) error
+	SetCacheBackend(backend string) error
+}
+
+type ExploreStorage interface {
+	GetExploreRepositoryPath(name string, update bool) (string, error)
+	RemoveExploreRepository(name string) error
+}
+
+// Storage is everything TerminalFeed needs to persist its state.
+type Storage interface {
+	ListStorage
+	CacheStorage
+	ConfigStorage
+	ExploreStorage
+
+	Init(version string) error
+	JoinConfigDir(file string) (string, error)
+	JoinCacheDir(file string) (string, error)
+}
@@ -0,0 +1,180 @@
+package storage
+
+import (
+	"bytes"
+	"encoding/json"
+	"os"
+	"slices"
+	"time"
+)
+
+const (
+	journalFile = "journal.json"
+
+	// maxJournalEntries is the number of changes that can be undone.
+	maxJournalEntries = 50
+	// journalMaxAge is how long a change can be undone.
+	journalMaxAge = 30 * 24 * time.Hour
+)
+
+// JournalEntry records the content of the lists before a destructive change,
+// so that the change can be undone.
+type JournalEntry struct {
+	Time        time.Time              `json:"time"`
+	Description string                 `json:"description"`
+	Lists       map[string][]*ListItem `json:"lists"` // nil if the list did not exist
+	// Feeds are the feeds removed by the change. Their caches are kept until
+	// the entry expires, so that undoing the change restores them.
+	Feeds []string `json:"feeds,omitempty"`
+	// Removed are the lists removed by the change. A list created again with
+	// the same name is merged with the restored one.
+	Removed []string `json:"removed,omitempty"`
+}
+
+func (s *LocalStorage) LoadJournal() ([]*JournalEntry, error) {
+	journalPath, err := s.JoinConfigDir(journalFile)
+	if err != nil {
+		return nil, err
+	}
+	b, err := os.ReadFile(journalPath)
+	if err != nil {
+		if os.IsNotExist(err) {
+			return nil, nil
+		}
+		return nil, err
+	}
+	journal := make([]*JournalEntry, 0)
+	err = json.Unmarshal(b, &journal)
+	if err != nil {
+		return nil, err
+	}
+	return journal, nil
+}
+
+// Undo restores the lists changed by the last journal entry and returns it,
+// or nil if there is nothing to undo.
+func (s *LocalStorage) Undo() (*JournalEntry, error) {
+	unlock, err := s.lock()
+	if err != nil {
+		return nil, err
+	}
+	defer unlock()
+	journal, err := s.LoadJournal()
+	if err != nil {
+		return nil, err
+	}
+	if len(journal) == 0 {
+		return nil, nil
+	}
+	entry := journal[len(journal)-1]
+	for list, items := range entry.Lists {
+		if items != nil {
+			if slices.Contains(entry.Removed, list) {
+				current, err := s.GetFeedsFromList(list)
+				if err != nil {
+					return nil, err
+				}
+				items = unionListItems(items, current)
+			}
+			err = s.saveList(items, list)
+			if err != nil {
+				return nil, err
+			}
+			continue
+		}
+		path, err := s.joinListsDir(list)
+		if err != nil {
+			return nil, err
+		}
+		err = os.Remove(path)
+		if err != nil && !os.IsNotExist(err) {
+			return nil, err
+		}
+	}
+	return entry, s.saveJournal(journal[:len(journal)-1])
+}
+
+// addToJournal records the entry with the current content of the lists. It
+// must be called while holding the storage lock, before the lists are
+// changed.
+func (s *LocalStorage) addToJournal(entry *JournalEntry, lists ...string) error {
+	entry.Time = s.time.Now()
+	entry.Lists = make(map[string][]*ListItem, len(lists))
+	for _, list := range lists {
+		items, err := s.GetFeedsFromList(list)
+		if err != nil {
+			return err
+		}
+		entry.Lists[list] = items
+	}
+	journal, err := s.LoadJournal()
+	if err != nil {
+		return err
+	}
+	journal, expired := expireEntries(append(journal, entry), entry.Time)
+	err = s.saveJournal(journal)
+	if err != nil {
+		return err
+	}
+	s.removeUnfollowedCaches(expiredFeeds(expired))
+	return nil
+}
+
+// expireJournal removes the entries that can no longer be undone, and the
+// caches of the feeds they removed.
+func (s *LocalStorage) expireJournal() error {
+	unlock, err := s.lock()
+	if err != nil {
+		return err
+	}
+	defer unlock()
+	journal, err := s.LoadJournal()
+	if err != nil {
+		return err
+	}
+	journal, expired := expireEntries(journal, s.time.Now())
+	if len(expired) == 0 {
+		return nil
+	}
+	err = s.saveJournal(journal)
+	if err != nil {
+		return err
+	}
+	s.removeUnfollowedCaches(expiredFeeds(expired))
+	return nil
+}
+
+func (s *LocalStorage) saveJournal(journal []*JournalEntry) error {
+	journalPath, err := s.JoinConfigDir(journalFile)
+	if err != nil {
+		return err
+	}
+	b, err := json.Marshal(journal)
+	if err != nil {
+		return err
+	}
+	return writeFileAtomic(journalPath, bytes.NewReader(b), 0600)
+}
+
+// expireEntries returns the entries that can still be undone and the ones
+// that expired, because they are too old or too many changes followed them.
+func expireEntries(journal []*JournalEntry, now time.Time) ([]*JournalEntry, []*JournalEntry) {
+	n := max(len(journal)-maxJournalEntries, 0)
+	for n < len(journal) && journal[n].Time.Before(now.Add(-journalMaxAge)) {
+		n++
+	}
+	return journal[n:], journal[:n]
+}
+
+// expiredFeeds returns the feeds removed by the expired entries.
+func expiredFeeds(expired []*JournalEntry) []string {
+	feeds := make([]string, 0)
+	for _, entry := range expired {
+		for _, feed := range entry.Feeds {
+			if !slices.Contains(feeds, feed) {
+				feeds = append(feeds, feed)
+			}
+		}
+	}
+	return feeds
+}
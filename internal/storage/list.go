@@ -4,33 +4,48 @@ import (
 	"bufio"
 	"bytes"
 	"fmt"
+	"maps"
 	"net/url"
 	"os"
 	"slices"
 	"strconv"
 	"strings"
 	"time"
+
+	"github.com/radulucut/cleed/internal/utils"
 )
 
+// ListSeparator separates the names of nested lists (e.g. tech/go).
+const ListSeparator = "/"
+
 type ListItem struct {
 	AddedAt time.Time
 	Address string
 }
 
+// IsInList reports whether list is parent or one of its nested lists.
+func IsInList(list, parent string) bool {
+	return list == parent || strings.HasPrefix(list, parent+ListSeparator)
+}
+
+// CompareLists orders lists so that nested lists follow their parent.
+func CompareLists(a, b string) int {
+	return slices.Compare(strings.Split(a, ListSeparator), strings.Split(b, ListSeparator))
+}
+
 func (s *LocalStorage) AddToList(urls []string, list string) error {
-	path, err := s.joinListsDir(list)
+	unlock, err := s.lock()
 	if err != nil {
 		return err
 	}
-	f, err := os.OpenFile(path, os.O_APPEND|os.O_CREATE|os.O_WRONLY, 0600)
+	defer unlock()
+	l, err := s.GetFeedsFromList(list)
 	if err != nil {
 		return err
 	}
-	defer f.Close()
-	m := make(map[string]*ListItem)
-	err = s.LoadFeedsFromList(m, list)
-	if err != nil {
-		return err
+	m := make(map[string]struct{}, len(l))
+	for i := range l {
+		m[l[i].Address] = struct{}{}
 	}
 	now := s.time.Now()
 	for _, url := range urls {
@@ -38,15 +53,21 @@ func (s *LocalStorage) AddToList(urls []string, list string) error {
 		if ok {
 			continue
 		}
-		_, err := f.Write(getListItemLine(now, url))
-		if err != nil {
-			return err
-		}
+		m[url] = struct{}{}
+		l = append(l, &ListItem{
+			AddedAt: now,
+			Address: url,
+		})
 	}
-	return nil
+	return s.saveList(l, list)
 }
 
 func (s *LocalStorage) RemoveFromList(urls []string, list string) ([]bool, error) {
+	unlock, err := s.lock()
+	if err != nil {
+		return nil, err
+	}
+	defer unlock()
 	l, err := s.GetFeedsFromList(list)
 	if err != nil {
 		return nil, err
@@ -69,19 +90,25 @@ func (s *LocalStorage) RemoveFromList(urls []string, list string) ([]bool, error
 			remaining = append(remaining, l[i])
 		}
 	}
-	path, err := s.joinListsDir(list)
-	if err != nil {
-		return nil, err
-	}
-	b := new(bytes.Buffer)
-	for i := range remaining {
-		b.Write(getListItemLine(remaining[i].AddedAt, remaining[i].Address))
+	if len(remaining) < len(l) {
+		removed := make([]string, 0, len(l)-len(remaining))
+		for i := range urls {
+			if results[i] {
+				removed = append(removed, urls[i])
+			}
+		}
+		err = s.addToJournal(&JournalEntry{
+			Description: fmt.Sprintf("unfollow %s from %s", utils.Pluralize(int64(len(l)-len(remaining)), "feed"), list),
+			Feeds:       removed,
+		}, list)
+		if err != nil {
+			return nil, err
+		}
 	}
-	err = os.WriteFile(path, b.Bytes(), 0600)
+	err = s.saveList(remaining, list)
 	if err != nil {
 		return nil, err
 	}
-	s.tidyCachesAfterRemove(urls, list)
 	return results, nil
 }
 
@@ -145,7 +172,7 @@ func (s *LocalStorage) LoadLists() ([]string, error) {
 	}
 	lists := make([]string, 0)
 	for _, file := range files {
-		if file.IsDir() {
+		if file.IsDir() || strings.HasPrefix(file.Name(), ".") {
 			continue
 		}
 		name, err := url.QueryUnescape(file.Name())
@@ -157,26 +184,69 @@ func (s *LocalStorage) LoadLists() ([]string, error) {
 	return lists, nil
 }
 
+// RenameList renames a list together with its nested lists.
 func (s *LocalStorage) RenameList(oldName, newName string) error {
-	oldPath, err := s.joinListsDir(oldName)
+	unlock, err := s.lock()
+	if err != nil {
+		return err
+	}
+	defer unlock()
+	if IsInList(newName, oldName) {
+		return fmt.Errorf("cannot rename list into itself: %s", newName)
+	}
+	lists, err := s.LoadLists()
 	if err != nil {
 		return err
 	}
-	newPath, err := s.joinListsDir(newName)
+	renames := make(map[string]string)
+	for _, list := range lists {
+		if IsInList(list, oldName) {
+			renames[list] = newName + strings.TrimPrefix(list, oldName)
+		}
+	}
+	if len(renames) == 0 {
+		return os.ErrNotExist
+	}
+	paths := make(map[string]string, len(renames))
+	for oldList, newList := range renames {
+		oldPath, err := s.joinListsDir(oldList)
+		if err != nil {
+			return err
+		}
+		newPath, err := s.joinListsDir(newList)
+		if err != nil {
+			return err
+		}
+		if _, err := os.Stat(newPath); err == nil {
+			return fmt.Errorf("list already exists: %s", newList)
+		}
+		paths[oldPath] = newPath
+	}
+	err = s.addToJournal(&JournalEntry{
+		Description: fmt.Sprintf("rename list %s to %s", oldName, newName),
+		Removed:     slices.Collect(maps.Keys(renames)),
+	}, slices.Concat(slices.Collect(maps.Keys(renames)), slices.Collect(maps.Values(renames)))...)
 	if err != nil {
 		return err
 	}
-	if _, err := os.Stat(newPath); err == nil {
-		return fmt.Errorf("list already exists: %s", newName)
+	for oldPath, newPath := range paths {
+		err = os.Rename(oldPath, newPath)
+		if err != nil {
+			return err
+		}
 	}
-	return os.Rename(oldPath, newPath)
+	return s.updateConfig(func(config *Config) error {
+		config.renamePausedLists(oldName, newName)
+		return nil
+	})
 }
 
 func (s *LocalStorage) MergeLists(list, otherList string) error {
-	listPath, err := s.joinListsDir(list)
+	unlock, err := s.lock()
 	if err != nil {
 		return err
 	}
+	defer unlock()
 	otherListPath, err := s.joinListsDir(otherList)
 	if err != nil {
 		return err
@@ -204,56 +274,165 @@ func (s *LocalStorage) MergeLists(list, otherList string) error {
 		}
 		return 0
 	})
-	b := new(bytes.Buffer)
-	for i := range listItems {
-		b.Write(getListItemLine(listItems[i].AddedAt, listItems[i].Address))
+	err = s.addToJournal(&JournalEntry{
+		Description: fmt.Sprintf("merge %s into %s", otherList, list),
+		Removed:     []string{otherList},
+	}, list, otherList)
+	if err != nil {
+		return err
+	}
+	err = s.saveList(listItems, list)
+	if err != nil {
+		return err
+	}
+	err = os.Remove(otherListPath)
+	if err != nil {
+		return err
+	}
+	lists, err := s.LoadLists()
+	if err != nil {
+		return err
+	}
+	return s.updateConfig(func(config *Config) error {
+		config.removePausedList(otherList, lists)
+		return nil
+	})
+}
+
+// MoveFeeds moves feeds to another list, keeping the time they were added
+// and their caches.
+func (s *LocalStorage) MoveFeeds(urls []string, from, to string) error {
+	return s.transferFeeds(urls, from, to, true)
+}
+
+// CopyFeeds copies feeds to another list, keeping the time they were added.
+func (s *LocalStorage) CopyFeeds(urls []string, from, to string) error {
+	return s.transferFeeds(urls, from, to, false)
+}
+
+func (s *LocalStorage) transferFeeds(urls []string, from, to string, move bool) error {
+	unlock, err := s.lock()
+	if err != nil {
+		return err
 	}
-	err = os.WriteFile(listPath, b.Bytes(), 0600)
+	defer unlock()
+	src, err := s.GetFeedsFromList(from)
 	if err != nil {
 		return err
 	}
-	return os.Remove(otherListPath)
+	dst, err := s.GetFeedsFromList(to)
+	if err != nil {
+		return err
+	}
+	description := fmt.Sprintf("copy %s from %s to %s", utils.Pluralize(int64(len(urls)), "feed"), from, to)
+	lists := []string{to}
+	if move {
+		description = fmt.Sprintf("move %s from %s to %s", utils.Pluralize(int64(len(urls)), "feed"), from, to)
+		lists = append(lists, from)
+	}
+	err = s.addToJournal(&JournalEntry{Description: description}, lists...)
+	if err != nil {
+		return err
+	}
+	src, dst = transferListItems(urls, src, dst, move)
+	err = s.saveList(dst, to)
+	if err != nil {
+		return err
+	}
+	if !move {
+		return nil
+	}
+	return s.saveList(src, from)
+}
+
+// transferListItems returns the source and destination lists after moving or
+// copying the feeds. Feeds already in the destination keep their entry there.
+func transferListItems(urls []string, src, dst []*ListItem, move bool) ([]*ListItem, []*ListItem) {
+	keys := make([]string, len(urls))
+	for i := range urls {
+		keys[i] = utils.URLKey(urls[i])
+	}
+	remaining := make([]*ListItem, 0, len(src))
+	for _, item := range src {
+		key := utils.URLKey(item.Address)
+		if !slices.Contains(keys, key) {
+			remaining = append(remaining, item)
+			continue
+		}
+		if !slices.ContainsFunc(dst, func(d *ListItem) bool { return utils.URLKey(d.Address) == key }) {
+			dst = append(dst, item)
+		}
+		if !move {
+			remaining = append(remaining, item)
+		}
+	}
+	slices.SortStableFunc(dst, func(a, b *ListItem) int {
+		return a.AddedAt.Compare(b.AddedAt)
+	})
+	return remaining, dst
 }
 
 func (s *LocalStorage) RemoveList(list string) error {
-	path, err := s.joinListsDir(list)
+	unlock, err := s.lock()
 	if err != nil {
 		return err
 	}
-	items, err := s.GetFeedsFromList(list)
+	defer unlock()
+	path, err := s.joinListsDir(list)
 	if err != nil {
 		return err
 	}
-	err = os.Remove(path)
+	items, err := s.GetFeedsFromList(list)
 	if err != nil {
 		return err
 	}
-	if len(items) == 0 {
-		return nil
+	if items != nil {
+		urls := make([]string, len(items))
+		for i := range items {
+			urls[i] = items[i].Address
+		}
+		err = s.addToJournal(&JournalEntry{
+			Description: "remove list " + list,
+			Feeds:       urls,
+			Removed:     []string{list},
+		}, list)
+		if err != nil {
+			return err
+		}
 	}
-	urls := make([]string, len(items))
-	for i := range items {
-		urls[i] = items[i].Address
+	return os.Remove(path)
+}
+
+// unionListItems returns the items of both lists ordered by the time they were
+// added. A feed in both lists keeps its item from a.
+func unionListItems(a, b []*ListItem) []*ListItem {
+	items := slices.Clone(a)
+	for _, item := range b {
+		if !slices.ContainsFunc(items, func(i *ListItem) bool { return i.Address == item.Address }) {
+			items = append(items, item)
+		}
 	}
-	s.tidyCachesAfterRemove(urls, list)
-	return nil
+	slices.SortStableFunc(items, func(a, b *ListItem) int {
+		return a.AddedAt.Compare(b.AddedAt)
+	})
+	return items
 }
 
-func (s *LocalStorage) tidyCachesAfterRemove(urls []string, list string) {
+// removeUnfollowedCaches removes the caches of the feeds that are not in any
+// list. It must be called while holding the storage lock.
+func (s *LocalStorage) removeUnfollowedCaches(urls []string) {
+	if len(urls) == 0 {
+		return
+	}
 	lists, err := s.LoadLists()
 	if err != nil {
 		return
 	}
 	m := make(map[string]*ListItem)
-	if len(lists) != 0 {
-		for i := range lists {
-			if lists[i] == list {
-				continue
-			}
-			err = s.LoadFeedsFromList(m, lists[i])
-			if err != nil {
-				return
-			}
+	for i := range lists {
+		err = s.LoadFeedsFromList(m, lists[i])
+		if err != nil {
+			return
 		}
 	}
 	feedsToRemove := make([]string, 0)
@@ -263,7 +442,20 @@ func (s *LocalStorage) tidyCachesAfterRemove(urls []string, list string) {
 			feedsToRemove = append(feedsToRemove, urls[i])
 		}
 	}
-	s.RemoveFeedCaches(feedsToRemove)
+	s.cacheStore().RemoveFeedCaches(feedsToRemove)
+}
+
+// saveList must be called while holding the storage lock.
+func (s *LocalStorage) saveList(items []*ListItem, list string) error {
+	path, err := s.joinListsDir(list)
+	if err != nil {
+		return err
+	}
+	b := new(bytes.Buffer)
+	for i := range items {
+		b.Write(getListItemLine(items[i].AddedAt, items[i].Address))
+	}
+	return writeFileAtomic(path, b, 0600)
 }
 
 func getListItemLine(
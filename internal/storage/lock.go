@@ -0,0 +1,66 @@
+package storage
+
+import (
+	"io"
+	"os"
+	"path"
+	"path/filepath"
+)
+
+const (
+	lockFile = "lock"
+)
+
+// lock acquires an exclusive lock on the data directory, shared between
+// cleed processes. The returned function releases the lock.
+func (s *LocalStorage) lock() (func(), error) {
+	s.mx.Lock()
+	lockPath, err := s.JoinConfigDir(lockFile)
+	if err != nil {
+		s.mx.Unlock()
+		return nil, err
+	}
+	f, err := os.OpenFile(lockPath, os.O_CREATE|os.O_RDWR, 0600)
+	if err != nil {
+		s.mx.Unlock()
+		return nil, err
+	}
+	err = lockFileExclusive(f)
+	if err != nil {
+		f.Close()
+		s.mx.Unlock()
+		return nil, err
+	}
+	return func() {
+		unlockFile(f)
+		f.Close()
+		s.mx.Unlock()
+	}, nil
+}
+
+// writeFileAtomic writes r to a temporary file in the same directory
+// and renames it over the destination, so readers never see a partial file.
+func writeFileAtomic(name string, r io.Reader, perm os.FileMode) error {
+	f, err := os.CreateTemp(path.Dir(name), "."+filepath.Base(name)+".*.tmp")
+	if err != nil {
+		return err
+	}
+	tmpName := f.Name()
+	_, err = io.Copy(f, r)
+	if err == nil {
+		err = f.Sync()
+	}
+	if closeErr := f.Close(); err == nil {
+		err = closeErr
+	}
+	if err == nil {
+		err = os.Chmod(tmpName, perm)
+	}
+	if err == nil {
+		err = os.Rename(tmpName, name)
+	}
+	if err != nil {
+		os.Remove(tmpName)
+	}
+	return err
+}
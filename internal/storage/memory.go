@@ -0,0 +1,597 @@
+package storage
+
+import (
+	"bytes"
+	"fmt"
+	"io"
+	"io/fs"
+	"maps"
+	"path"
+	"slices"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/mmcdole/gofeed"
+	"github.com/radulucut/cleed/internal/utils"
+)
+
+var _ Storage = (*MemoryStorage)(nil)
+
+// MemoryStorage keeps everything in memory. It is meant for tests.
+type MemoryStorage struct {
+	time utils.Time
+	mx   sync.Mutex
+
+	config    *Config
+	lists     map[string][]*ListItem
+	cacheInfo map[string]*CacheInfoItem
+	feeds     map[string][]byte
+	parsed    map[string]*gofeed.Feed
+	readState map[string]map[string]time.Time
+	downloads map[string]map[string]time.Time
+	history   map[string][]*FetchHistoryItem
+	archive   map[string][]*ArchiveItem
+	index     []byte
+	journal   []*JournalEntry
+	timeline  []*TimelineItem
+
+	// ExploreRepositories maps a repository URL to a local directory.
+	ExploreRepositories map[string]string
+}
+
+func NewMemoryStorage(_time utils.Time) *MemoryStorage {
+	return &MemoryStorage{
+		time:                _time,
+		lists:               make(map[string][]*ListItem),
+		cacheInfo:           make(map[string]*CacheInfoItem),
+		feeds:               make(map[string][]byte),
+		parsed:              make(map[string]*gofeed.Feed),
+		readState:           make(map[string]map[string]time.Time),
+		downloads:           make(map[string]map[string]time.Time),
+		history:             make(map[string][]*FetchHistoryItem),
+		archive:             make(map[string][]*ArchiveItem),
+		ExploreRepositories: make(map[string]string),
+	}
+}
+
+func (s *MemoryStorage) Init(version string) error {
+	s.mx.Lock()
+	defer s.mx.Unlock()
+	if s.config == nil {
+		s.config = &Config{
+			Version:   version,
+			Timeout:   30,
+			BatchSize: 100,
+			ColorMap:  make(map[uint8]uint8),
+		}
+	}
+	var expired []*JournalEntry
+	s.journal, expired = expireEntries(s.journal, s.time.Now())
+	s.removeUnfollowedCaches(expiredFeeds(expired))
+	return nil
+}
+
+func (s *MemoryStorage) JoinConfigDir(file string) (string, error) {
+	return path.Join("/memory/config", file), nil
+}
+
+func (s *MemoryStorage) JoinCacheDir(file string) (string, error) {
+	return path.Join("/memory/cache", file), nil
+}
+
+func (s *MemoryStorage) LoadConfig() (*Config, error) {
+	s.mx.Lock()
+	defer s.mx.Unlock()
+	if s.config == nil {
+		return nil, fs.ErrNotExist
+	}
+	return s.config, nil
+}
+
+func (s *MemoryStorage) UpdateConfig(update func(config *Config) error) error {
+	s.mx.Lock()
+	defer s.mx.Unlock()
+	if s.config == nil {
+		return fs.ErrNotExist
+	}
+	config := *s.config
+	err := update(&config)
+	if err != nil {
+		return err
+	}
+	*s.config = config
+	return nil
+}
+
+func (s *MemoryStorage) SetCacheBackend(backend string) error {
+	if backend != CacheBackendFile && backend != CacheBackendBolt {
+		return fmt.Errorf("invalid cache backend: %s", backend)
+	}
+	s.mx.Lock()
+	defer s.mx.Unlock()
+	if s.config != nil {
+		s.config.CacheBackend = backend
+	}
+	return nil
+}
+
+func (s *MemoryStorage) AddToList(urls []string, list string) error {
+	s.mx.Lock()
+	defer s.mx.Unlock()
+	l := s.lists[list]
+	now := s.time.Now()
+	for _, url := range urls {
+		if slices.ContainsFunc(l, func(item *ListItem) bool { return item.Address == url }) {
+			continue
+		}
+		l = append(l, &ListItem{
+			AddedAt: time.Unix(now.Unix(), 0),
+			Address: url,
+		})
+	}
+	s.lists[list] = l
+	return nil
+}
+
+func (s *MemoryStorage) RemoveFromList(urls []string, list string) ([]bool, error) {
+	s.mx.Lock()
+	defer s.mx.Unlock()
+	l := s.lists[list]
+	if len(l) == 0 {
+		return nil, fmt.Errorf("no items in list: %s", list)
+	}
+	results := make([]bool, len(urls))
+	remaining := make([]*ListItem, 0)
+	for i := range l {
+		j := slices.Index(urls, l[i].Address)
+		if j == -1 {
+			remaining = append(remaining, l[i])
+			continue
+		}
+		results[j] = true
+	}
+	if len(remaining) < len(l) {
+		removed := make([]string, 0, len(l)-len(remaining))
+		for i := range urls {
+			if results[i] {
+				removed = append(removed, urls[i])
+			}
+		}
+		s.addToJournal(&JournalEntry{
+			Description: fmt.Sprintf("unfollow %s from %s", utils.Pluralize(int64(len(l)-len(remaining)), "feed"), list),
+			Feeds:       removed,
+		}, list)
+	}
+	s.lists[list] = remaining
+	return results, nil
+}
+
+func (s *MemoryStorage) GetFeedsFromList(list string) ([]*ListItem, error) {
+	s.mx.Lock()
+	defer s.mx.Unlock()
+	l, ok := s.lists[list]
+	if !ok {
+		return nil, nil
+	}
+	return slices.Clone(l), nil
+}
+
+func (s *MemoryStorage) LoadFeedsFromList(m map[string]*ListItem, list string) error {
+	s.mx.Lock()
+	defer s.mx.Unlock()
+	for _, item := range s.lists[list] {
+		m[item.Address] = item
+	}
+	return nil
+}
+
+func (s *MemoryStorage) LoadLists() ([]string, error) {
+	s.mx.Lock()
+	defer s.mx.Unlock()
+	lists := make([]string, 0, len(s.lists))
+	for name := range s.lists {
+		lists = append(lists, name)
+	}
+	return lists, nil
+}
+
+func (s *MemoryStorage) RenameList(oldName, newName string) error {
+	s.mx.Lock()
+	defer s.mx.Unlock()
+	if IsInList(newName, oldName) {
+		return fmt.Errorf("cannot rename list into itself: %s", newName)
+	}
+	renames := make(map[string]string)
+	for list := range s.lists {
+		if IsInList(list, oldName) {
+			renames[list] = newName + strings.TrimPrefix(list, oldName)
+		}
+	}
+	if len(renames) == 0 {
+		return fs.ErrNotExist
+	}
+	for _, newList := range renames {
+		if _, ok := s.lists[newList]; ok {
+			return fmt.Errorf("list already exists: %s", newList)
+		}
+	}
+	s.addToJournal(&JournalEntry{
+		Description: fmt.Sprintf("rename list %s to %s", oldName, newName),
+		Removed:     slices.Collect(maps.Keys(renames)),
+	}, slices.Concat(slices.Collect(maps.Keys(renames)), slices.Collect(maps.Values(renames)))...)
+	for oldList, newList := range renames {
+		s.lists[newList] = s.lists[oldList]
+		delete(s.lists, oldList)
+	}
+	if s.config != nil {
+		s.config.renamePausedLists(oldName, newName)
+	}
+	return nil
+}
+
+func (s *MemoryStorage) MergeLists(list, otherList string) error {
+	s.mx.Lock()
+	defer s.mx.Unlock()
+	other, ok := s.lists[otherList]
+	if !ok {
+		return fs.ErrNotExist
+	}
+	items := make(map[string]*ListItem)
+	for _, item := range other {
+		items[item.Address] = item
+	}
+	for _, item := range s.lists[list] {
+		items[item.Address] = item
+	}
+	merged := make([]*ListItem, 0, len(items))
+	for _, item := range items {
+		merged = append(merged, item)
+	}
+	slices.SortFunc(merged, func(a, b *ListItem) int {
+		return a.AddedAt.Compare(b.AddedAt)
+	})
+	s.addToJournal(&JournalEntry{
+		Description: fmt.Sprintf("merge %s into %s", otherList, list),
+		Removed:     []string{otherList},
+	}, list, otherList)
+	s.lists[list] = merged
+	delete(s.lists, otherList)
+	if s.config != nil {
+		s.config.removePausedList(otherList, slices.Collect(maps.Keys(s.lists)))
+	}
+	return nil
+}
+
+func (s *MemoryStorage) MoveFeeds(urls []string, from, to string) error {
+	s.mx.Lock()
+	defer s.mx.Unlock()
+	s.addToJournal(&JournalEntry{
+		Description: fmt.Sprintf("move %s from %s to %s", utils.Pluralize(int64(len(urls)), "feed"), from, to),
+	}, to, from)
+	s.lists[from], s.lists[to] = transferListItems(urls, s.lists[from], s.lists[to], true)
+	return nil
+}
+
+func (s *MemoryStorage) CopyFeeds(urls []string, from, to string) error {
+	s.mx.Lock()
+	defer s.mx.Unlock()
+	s.addToJournal(&JournalEntry{
+		Description: fmt.Sprintf("copy %s from %s to %s", utils.Pluralize(int64(len(urls)), "feed"), from, to),
+	}, to)
+	_, s.lists[to] = transferListItems(urls, s.lists[from], s.lists[to], false)
+	return nil
+}
+
+func (s *MemoryStorage) RemoveList(list string) error {
+	s.mx.Lock()
+	defer s.mx.Unlock()
+	l, ok := s.lists[list]
+	if !ok {
+		return fs.ErrNotExist
+	}
+	urls := make([]string, len(l))
+	for i := range l {
+		urls[i] = l[i].Address
+	}
+	s.addToJournal(&JournalEntry{
+		Description: "remove list " + list,
+		Feeds:       urls,
+		Removed:     []string{list},
+	}, list)
+	delete(s.lists, list)
+	return nil
+}
+
+func (s *MemoryStorage) LoadJournal() ([]*JournalEntry, error) {
+	s.mx.Lock()
+	defer s.mx.Unlock()
+	return slices.Clone(s.journal), nil
+}
+
+func (s *MemoryStorage) Undo() (*JournalEntry, error) {
+	s.mx.Lock()
+	defer s.mx.Unlock()
+	if len(s.journal) == 0 {
+		return nil, nil
+	}
+	entry := s.journal[len(s.journal)-1]
+	for list, items := range entry.Lists {
+		if items == nil {
+			delete(s.lists, list)
+		} else if slices.Contains(entry.Removed, list) {
+			s.lists[list] = unionListItems(items, s.lists[list])
+		} else {
+			s.lists[list] = slices.Clone(items)
+		}
+	}
+	s.journal = s.journal[:len(s.journal)-1]
+	return entry, nil
+}
+
+// addToJournal must be called while holding the mutex.
+func (s *MemoryStorage) addToJournal(entry *JournalEntry, lists ...string) {
+	entry.Time = s.time.Now()
+	entry.Lists = make(map[string][]*ListItem, len(lists))
+	for _, list := range lists {
+		if l, ok := s.lists[list]; ok {
+			entry.Lists[list] = slices.Clone(l)
+		} else {
+			entry.Lists[list] = nil
+		}
+	}
+	var expired []*JournalEntry
+	s.journal, expired = expireEntries(append(s.journal, entry), entry.Time)
+	s.removeUnfollowedCaches(expiredFeeds(expired))
+}
+
+// removeUnfollowedCaches must be called while holding the mutex.
+func (s *MemoryStorage) removeUnfollowedCaches(urls []string) {
+	for _, url := range urls {
+		followed := false
+		for _, l := range s.lists {
+			if slices.ContainsFunc(l, func(item *ListItem) bool { return item.Address == url }) {
+				followed = true
+				break
+			}
+		}
+		if !followed {
+			s.removeFeedCache(url)
+		}
+	}
+}
+
+func (s *MemoryStorage) LoadCacheInfo() (map[string]*CacheInfoItem, error) {
+	s.mx.Lock()
+	defer s.mx.Unlock()
+	cacheInfo := make(map[string]*CacheInfoItem, len(s.cacheInfo))
+	for k, v := range s.cacheInfo {
+		item := *v
+		cacheInfo[k] = &item
+	}
+	return cacheInfo, nil
+}
+
+func (s *MemoryStorage) UpdateCacheInfo(update func(cacheinfo map[string]*CacheInfoItem) error) error {
+	s.mx.Lock()
+	defer s.mx.Unlock()
+	cacheinfo := make(map[string]*CacheInfoItem, len(s.cacheInfo))
+	for k, v := range s.cacheInfo {
+		item := *v
+		cacheinfo[k] = &item
+	}
+	err := update(cacheinfo)
+	if err != nil {
+		return err
+	}
+	s.cacheInfo = make(map[string]*CacheInfoItem, len(cacheinfo))
+	for k, v := range cacheinfo {
+		item := *v
+		s.cacheInfo[k] = &item
+	}
+	return nil
+}
+
+func (s *MemoryStorage) SaveFeedCache(r io.Reader, name string) error {
+	b, err := io.ReadAll(r)
+	if err != nil {
+		return err
+	}
+	s.mx.Lock()
+	defer s.mx.Unlock()
+	s.feeds[name] = b
+	return nil
+}
+
+func (s *MemoryStorage) OpenFeedCache(name string) (io.ReadCloser, error) {
+	s.mx.Lock()
+	defer s.mx.Unlock()
+	b, ok := s.feeds[name]
+	if !ok {
+		return nil, fs.ErrNotExist
+	}
+	return io.NopCloser(bytes.NewReader(b)), nil
+}
+
+func (s *MemoryStorage) SaveParsedFeedCache(feed *gofeed.Feed, name string) error {
+	s.mx.Lock()
+	defer s.mx.Unlock()
+	s.parsed[name] = feed
+	return nil
+}
+
+func (s *MemoryStorage) LoadParsedFeedCache(name string) (*gofeed.Feed, error) {
+	s.mx.Lock()
+	defer s.mx.Unlock()
+	feed, ok := s.parsed[name]
+	if !ok {
+		return nil, fs.ErrNotExist
+	}
+	return feed, nil
+}
+
+func (s *MemoryStorage) HasParsedFeedCache(name string) bool {
+	s.mx.Lock()
+	defer s.mx.Unlock()
+	_, ok := s.parsed[name]
+	return ok
+}
+
+func (s *MemoryStorage) LoadReadState(name string) (map[string]time.Time, error) {
+	s.mx.Lock()
+	defer s.mx.Unlock()
+	state := make(map[string]time.Time, len(s.readState[name]))
+	for k, v := range s.readState[name] {
+		state[k] = v
+	}
+	return state, nil
+}
+
+func (s *MemoryStorage) MarkRead(ids []string, name string) error {
+	s.mx.Lock()
+	defer s.mx.Unlock()
+	state, ok := s.readState[name]
+	if !ok {
+		state = make(map[string]time.Time)
+		s.readState[name] = state
+	}
+	now := s.time.Now()
+	for i := range ids {
+		state[ids[i]] = now
+	}
+	return nil
+}
+
+func (s *MemoryStorage) LoadDownloads(name string) (map[string]time.Time, error) {
+	s.mx.Lock()
+	defer s.mx.Unlock()
+	downloads := make(map[string]time.Time, len(s.downloads[name]))
+	for k, v := range s.downloads[name] {
+		downloads[k] = v
+	}
+	return downloads, nil
+}
+
+func (s *MemoryStorage) MarkDownloaded(ids []string, name string) error {
+	s.mx.Lock()
+	defer s.mx.Unlock()
+	downloads, ok := s.downloads[name]
+	if !ok {
+		downloads = make(map[string]time.Time)
+		s.downloads[name] = downloads
+	}
+	now := s.time.Now()
+	for i := range ids {
+		downloads[ids[i]] = now
+	}
+	return nil
+}
+
+func (s *MemoryStorage) LoadFetchHistory(name string) ([]*FetchHistoryItem, error) {
+	s.mx.Lock()
+	defer s.mx.Unlock()
+	return slices.Clone(s.history[name]), nil
+}
+
+func (s *MemoryStorage) AddFetchHistory(item *FetchHistoryItem, name string) error {
+	s.mx.Lock()
+	defer s.mx.Unlock()
+	history := append(s.history[name], item)
+	if len(history) > fetchHistoryLimit {
+		history = history[len(history)-fetchHistoryLimit:]
+	}
+	s.history[name] = history
+	return nil
+}
+
+func (s *MemoryStorage) LoadArchive(name string) ([]*ArchiveItem, error) {
+	s.mx.Lock()
+	defer s.mx.Unlock()
+	return slices.Clone(s.archive[name]), nil
+}
+
+func (s *MemoryStorage) AddToArchive(items []*gofeed.Item, name string, retention *ArchiveRetention) ([]*ArchiveItem, error) {
+	s.mx.Lock()
+	defer s.mx.Unlock()
+	archive, _ := mergeArchive(s.archive[name], items, s.time.Now())
+	archive, _ = pruneArchive(archive, retention, s.time.Now())
+	s.archive[name] = archive
+	return slices.Clone(archive), nil
+}
+
+func (s *MemoryStorage) PruneArchive(name string, retention *ArchiveRetention) (int, error) {
+	s.mx.Lock()
+	defer s.mx.Unlock()
+	archive, removed := pruneArchive(s.archive[name], retention, s.time.Now())
+	s.archive[name] = archive
+	return removed, nil
+}
+
+func (s *MemoryStorage) OpenSearchIndex() (io.ReadCloser, error) {
+	s.mx.Lock()
+	defer s.mx.Unlock()
+	if s.index == nil {
+		return nil, fs.ErrNotExist
+	}
+	return io.NopCloser(bytes.NewReader(s.index)), nil
+}
+
+func (s *MemoryStorage) SaveSearchIndex(r io.Reader) error {
+	b, err := io.ReadAll(r)
+	if err != nil {
+		return err
+	}
+	s.mx.Lock()
+	defer s.mx.Unlock()
+	s.index = b
+	return nil
+}
+
+func (s *MemoryStorage) LoadTimeline() ([]*TimelineItem, error) {
+	s.mx.Lock()
+	defer s.mx.Unlock()
+	return slices.Clone(s.timeline), nil
+}
+
+func (s *MemoryStorage) SaveTimeline(timeline []*TimelineItem) error {
+	s.mx.Lock()
+	defer s.mx.Unlock()
+	s.timeline = slices.Clone(timeline)
+	return nil
+}
+
+func (s *MemoryStorage) RemoveFeedCaches(names []string) error {
+	s.mx.Lock()
+	defer s.mx.Unlock()
+	for i := range names {
+		s.removeFeedCache(names[i])
+	}
+	return nil
+}
+
+func (s *MemoryStorage) removeFeedCache(name string) {
+	delete(s.cacheInfo, name)
+	delete(s.feeds, name)
+	delete(s.parsed, name)
+	delete(s.readState, name)
+	delete(s.downloads, name)
+	delete(s.history, name)
+	delete(s.archive, name)
+}
+
+func (s *MemoryStorage) GetExploreRepositoryPath(name string, update bool) (string, error) {
+	s.mx.Lock()
+	defer s.mx.Unlock()
+	p, ok := s.ExploreRepositories[name]
+	if !ok {
+		return "", utils.NewInternalError("repository not found: " + name)
+	}
+	return p, nil
+}
+
+func (s *MemoryStorage) RemoveExploreRepository(name string) error {
+	s.mx.Lock()
+	defer s.mx.Unlock()
+	delete(s.ExploreRepositories, name)
+	return nil
+}
@@ -0,0 +1,117 @@
+package storage
+
+import (
+	"os"
+)
+
+type cacheMigrator interface {
+	CacheStore
+	exists() bool
+	names() ([]string, error)
+	clear(names []string) error
+}
+
+// migrateCache copies every cache entry from one backend to another and
+// removes the source once everything was copied.
+func migrateCache(from, to cacheMigrator) error {
+	names, err := from.names()
+	if err != nil {
+		return err
+	}
+	cacheinfo, err := from.LoadCacheInfo()
+	if err != nil {
+		return err
+	}
+	existing, err := to.LoadCacheInfo()
+	if err != nil {
+		return err
+	}
+	for url, item := range cacheinfo {
+		existing[url] = item
+	}
+	err = to.SaveCacheInfo(existing)
+	if err != nil {
+		return err
+	}
+	for _, name := range names {
+		err = migrateFeedCache(from, to, name)
+		if err != nil {
+			return err
+		}
+	}
+	r, err := from.OpenSearchIndex()
+	if err == nil {
+		err = to.SaveSearchIndex(r)
+		r.Close()
+		if err != nil {
+			return err
+		}
+	} else if !os.IsNotExist(err) {
+		return err
+	}
+	return from.clear(names)
+}
+
+func migrateFeedCache(from, to CacheStore, name string) error {
+	r, err := from.OpenFeedCache(name)
+	if err == nil {
+		err = to.SaveFeedCache(r, name)
+		r.Close()
+		if err != nil {
+			return err
+		}
+	} else if !os.IsNotExist(err) {
+		return err
+	}
+	if from.HasParsedFeedCache(name) {
+		feed, err := from.LoadParsedFeedCache(name)
+		if err != nil {
+			return err
+		}
+		err = to.SaveParsedFeedCache(feed, name)
+		if err != nil {
+			return err
+		}
+	}
+	state, err := from.LoadReadState(name)
+	if err != nil {
+		return err
+	}
+	if len(state) > 0 {
+		err = to.SaveReadState(state, name)
+		if err != nil {
+			return err
+		}
+	}
+	downloads, err := from.LoadDownloads(name)
+	if err != nil {
+		return err
+	}
+	if len(downloads) > 0 {
+		err = to.SaveDownloads(downloads, name)
+		if err != nil {
+			return err
+		}
+	}
+	history, err := from.LoadFetchHistory(name)
+	if err != nil {
+		return err
+	}
+	if len(history) > 0 {
+		err = to.SaveFetchHistory(history, name)
+		if err != nil {
+			return err
+		}
+	}
+	archive, err := from.LoadArchive(name)
+	if err != nil {
+		return err
+	}
+	if len(archive) > 0 {
+		err = to.SaveArchive(archive, name)
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
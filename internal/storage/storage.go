@@ -1,9 +1,11 @@
 package storage
 
 import (
+	"fmt"
 	"net/url"
 	"os"
 	"path"
+	"sync"
 
 	"github.com/radulucut/cleed/internal/utils"
 )
@@ -15,11 +17,16 @@ const (
 	exploreDir = "explore"
 )
 
+var _ Storage = (*LocalStorage)(nil)
+
 type LocalStorage struct {
 	name string
 	time utils.Time
+	mx   sync.Mutex
 
-	config *Config
+	config  *Config
+	cache   CacheStore
+	cacheMx sync.Mutex
 }
 
 func NewLocalStorage(
@@ -66,12 +73,52 @@ func (s *LocalStorage) Init(version string) error {
 		}
 		return err
 	}
-	return s.Migrate()
+	err = s.Migrate()
+	if err != nil {
+		return err
+	}
+	return s.expireJournal()
 }
 
+// Migrate moves the cache into the configured backend if another
+// backend still holds data.
 func (s *LocalStorage) Migrate() error {
-	// handle migration here
-	return nil
+	config, err := s.LoadConfig()
+	if err != nil {
+		return err
+	}
+	var from, to cacheMigrator
+	if config.CacheBackend == CacheBackendBolt {
+		from, to = &fileCache{storage: s}, &boltCache{storage: s}
+	} else {
+		from, to = &boltCache{storage: s}, &fileCache{storage: s}
+	}
+	if !from.exists() {
+		return nil
+	}
+	unlock, err := s.lock()
+	if err != nil {
+		return err
+	}
+	defer unlock()
+	return migrateCache(from, to)
+}
+
+func (s *LocalStorage) SetCacheBackend(backend string) error {
+	if backend != CacheBackendFile && backend != CacheBackendBolt {
+		return fmt.Errorf("invalid cache backend: %s", backend)
+	}
+	err := s.UpdateConfig(func(config *Config) error {
+		config.CacheBackend = backend
+		return nil
+	})
+	if err != nil {
+		return err
+	}
+	s.cacheMx.Lock()
+	s.cache = nil
+	s.cacheMx.Unlock()
+	return s.Migrate()
 }
 
 func (s *LocalStorage) ClearAll() error {
@@ -0,0 +1,373 @@
+package storage
+
+import (
+	"bytes"
+	"fmt"
+	"io"
+	"os"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/mmcdole/gofeed"
+	"github.com/radulucut/cleed/internal/utils"
+	"github.com/stretchr/testify/assert"
+)
+
+func newTestStorage(t *testing.T) *LocalStorage {
+	s := NewLocalStorage("cleed_test", utils.NewTime())
+	err := s.Init("0.1.0")
+	if err != nil {
+		t.Fatal(err)
+	}
+	return s
+}
+
+func Test_Concurrent_AddToList(t *testing.T) {
+	t.Setenv(dataPathOverrideName, t.TempDir())
+	newTestStorage(t)
+
+	wg := sync.WaitGroup{}
+	for i := 0; i < 20; i++ {
+		wg.Add(1)
+		go func(i int) {
+			defer wg.Done()
+			// separate instances to simulate separate processes
+			s := NewLocalStorage("cleed_test", utils.NewTime())
+			err := s.AddToList([]string{fmt.Sprintf("https://example.com/%d", i)}, "default")
+			assert.NoError(t, err)
+		}(i)
+	}
+	wg.Wait()
+
+	s := NewLocalStorage("cleed_test", utils.NewTime())
+	items, err := s.GetFeedsFromList("default")
+	assert.NoError(t, err)
+	assert.Len(t, items, 20)
+	lists, err := s.LoadLists()
+	assert.NoError(t, err)
+	assert.Equal(t, []string{"default"}, lists)
+}
+
+func Test_Concurrent_Add_And_Remove(t *testing.T) {
+	t.Setenv(dataPathOverrideName, t.TempDir())
+	s := newTestStorage(t)
+
+	urls := make([]string, 10)
+	for i := range urls {
+		urls[i] = fmt.Sprintf("https://example.com/%d", i)
+	}
+	err := s.AddToList(urls, "default")
+	assert.NoError(t, err)
+
+	wg := sync.WaitGroup{}
+	for i := range urls {
+		wg.Add(2)
+		go func(i int) {
+			defer wg.Done()
+			s := NewLocalStorage("cleed_test", utils.NewTime())
+			_, err := s.RemoveFromList([]string{urls[i]}, "default")
+			assert.NoError(t, err)
+		}(i)
+		go func(i int) {
+			defer wg.Done()
+			s := NewLocalStorage("cleed_test", utils.NewTime())
+			err := s.AddToList([]string{fmt.Sprintf("https://other.com/%d", i)}, "default")
+			assert.NoError(t, err)
+		}(i)
+	}
+	wg.Wait()
+
+	items, err := s.GetFeedsFromList("default")
+	assert.NoError(t, err)
+	assert.Len(t, items, 10)
+	for i := range items {
+		assert.Contains(t, items[i].Address, "https://other.com/")
+	}
+}
+
+func Test_Concurrent_SaveCacheInfo(t *testing.T) {
+	t.Setenv(dataPathOverrideName, t.TempDir())
+	newTestStorage(t)
+
+	wg := sync.WaitGroup{}
+	for i := 0; i < 20; i++ {
+		wg.Add(1)
+		go func(i int) {
+			defer wg.Done()
+			s := NewLocalStorage("cleed_test", utils.NewTime())
+			cacheInfo := make(map[string]*CacheInfoItem)
+			for j := 0; j < 50; j++ {
+				url := fmt.Sprintf("https://example.com/%d/%d", i, j)
+				cacheInfo[url] = &CacheInfoItem{
+					URL:        url,
+					LastFetch:  time.Unix(int64(i), 0),
+					FetchAfter: time.Unix(int64(j), 0),
+					ETag:       "etag",
+				}
+			}
+			err := s.SaveCacheInfo(cacheInfo)
+			assert.NoError(t, err)
+		}(i)
+	}
+	wg.Wait()
+
+	s := NewLocalStorage("cleed_test", utils.NewTime())
+	cacheInfo, err := s.LoadCacheInfo()
+	assert.NoError(t, err)
+	assert.Len(t, cacheInfo, 50)
+
+	cacheDir, err := s.JoinCacheDir("")
+	assert.NoError(t, err)
+	files, err := os.ReadDir(cacheDir)
+	assert.NoError(t, err)
+	assert.Len(t, files, 1)
+}
+
+func Test_Concurrent_UpdateConfig(t *testing.T) {
+	t.Setenv(dataPathOverrideName, t.TempDir())
+	newTestStorage(t)
+
+	wg := sync.WaitGroup{}
+	for i := 0; i < 20; i++ {
+		wg.Add(1)
+		go func(i int) {
+			defer wg.Done()
+			s := NewLocalStorage("cleed_test", utils.NewTime())
+			_, err := s.LoadConfig()
+			assert.NoError(t, err)
+			err = s.UpdateConfig(func(config *Config) error {
+				config.PausedFeeds = append(config.PausedFeeds, fmt.Sprintf("https://example.com/%d", i))
+				return nil
+			})
+			assert.NoError(t, err)
+		}(i)
+	}
+	wg.Wait()
+
+	s := NewLocalStorage("cleed_test", utils.NewTime())
+	config, err := s.LoadConfig()
+	assert.NoError(t, err)
+	assert.Len(t, config.PausedFeeds, 20)
+}
+
+func Test_UpdateConfig_Keeps_Other_Changes(t *testing.T) {
+	t.Setenv(dataPathOverrideName, t.TempDir())
+	newTestStorage(t)
+
+	// both load the config before either saves it
+	a := NewLocalStorage("cleed_test", utils.NewTime())
+	configA, err := a.LoadConfig()
+	assert.NoError(t, err)
+	b := NewLocalStorage("cleed_test", utils.NewTime())
+	_, err = b.LoadConfig()
+	assert.NoError(t, err)
+
+	err = b.UpdateConfig(func(config *Config) error {
+		config.Pager = "less"
+		return nil
+	})
+	assert.NoError(t, err)
+	err = a.UpdateConfig(func(config *Config) error {
+		config.LastRun = time.Unix(100, 0)
+		return nil
+	})
+	assert.NoError(t, err)
+	assert.Equal(t, "less", configA.Pager)
+
+	err = a.UpdateConfig(func(config *Config) error {
+		config.Pager = "more"
+		return fmt.Errorf("invalid")
+	})
+	assert.EqualError(t, err, "invalid")
+
+	s := NewLocalStorage("cleed_test", utils.NewTime())
+	config, err := s.LoadConfig()
+	assert.NoError(t, err)
+	assert.Equal(t, "less", config.Pager)
+	assert.True(t, config.LastRun.Equal(time.Unix(100, 0)))
+}
+
+func Test_UpdateCacheInfo_Keeps_Other_Changes(t *testing.T) {
+	t.Setenv(dataPathOverrideName, t.TempDir())
+	newTestStorage(t)
+
+	wg := sync.WaitGroup{}
+	for i := 0; i < 20; i++ {
+		wg.Add(1)
+		go func(i int) {
+			defer wg.Done()
+			s := NewLocalStorage("cleed_test", utils.NewTime())
+			url := fmt.Sprintf("https://example.com/%d", i)
+			err := s.UpdateCacheInfo(func(cacheinfo map[string]*CacheInfoItem) error {
+				cacheinfo[url] = &CacheInfoItem{
+					URL:        url,
+					LastFetch:  time.Unix(int64(i), 0),
+					FetchAfter: time.Unix(int64(i), 0),
+					ETag:       "etag",
+				}
+				return nil
+			})
+			assert.NoError(t, err)
+		}(i)
+	}
+	wg.Wait()
+
+	s := NewLocalStorage("cleed_test", utils.NewTime())
+	cacheInfo, err := s.LoadCacheInfo()
+	assert.NoError(t, err)
+	assert.Len(t, cacheInfo, 20)
+	assert.Equal(t, "etag", cacheInfo["https://example.com/7"].ETag)
+}
+
+func Test_Migrate_File_To_Bolt(t *testing.T) {
+	t.Setenv(dataPathOverrideName, t.TempDir())
+	s := newTestStorage(t)
+
+	err := s.SaveCacheInfo(map[string]*CacheInfoItem{
+		"https://example.com": {
+			URL:        "https://example.com",
+			LastFetch:  time.Unix(100, 0),
+			FetchAfter: time.Unix(200, 0),
+			ETag:       "etag",
+		},
+	})
+	assert.NoError(t, err)
+	err = s.SaveFeedCache(bytes.NewBufferString("raw"), "https://example.com")
+	assert.NoError(t, err)
+	err = s.SaveParsedFeedCache(&gofeed.Feed{Title: "Example"}, "https://example.com")
+	assert.NoError(t, err)
+	err = s.MarkRead([]string{"item-1"}, "https://example.com")
+	assert.NoError(t, err)
+	err = s.MarkDownloaded([]string{"item-2"}, "https://example.com")
+	assert.NoError(t, err)
+	err = s.AddFetchHistory(&FetchHistoryItem{Time: time.Unix(100, 0), Status: 200, Items: 3}, "https://example.com")
+	assert.NoError(t, err)
+
+	err = s.SetCacheBackend(CacheBackendBolt)
+	assert.NoError(t, err)
+
+	cacheDir, err := s.JoinCacheDir("")
+	assert.NoError(t, err)
+	files, err := os.ReadDir(cacheDir)
+	assert.NoError(t, err)
+	assert.Len(t, files, 1)
+	assert.Equal(t, cacheDBFile, files[0].Name())
+
+	s = NewLocalStorage("cleed_test", utils.NewTime())
+	cacheInfo, err := s.LoadCacheInfo()
+	assert.NoError(t, err)
+	assert.Equal(t, map[string]*CacheInfoItem{
+		"https://example.com": {
+			URL:        "https://example.com",
+			LastFetch:  time.Unix(100, 0),
+			FetchAfter: time.Unix(200, 0),
+			ETag:       "etag",
+		},
+	}, cacheInfo)
+	r, err := s.OpenFeedCache("https://example.com")
+	assert.NoError(t, err)
+	raw, err := io.ReadAll(r)
+	assert.NoError(t, err)
+	assert.Equal(t, "raw", string(raw))
+	feed, err := s.LoadParsedFeedCache("https://example.com")
+	assert.NoError(t, err)
+	assert.Equal(t, "Example", feed.Title)
+	state, err := s.LoadReadState("https://example.com")
+	assert.NoError(t, err)
+	assert.Contains(t, state, "item-1")
+	downloads, err := s.LoadDownloads("https://example.com")
+	assert.NoError(t, err)
+	assert.Contains(t, downloads, "item-2")
+	history, err := s.LoadFetchHistory("https://example.com")
+	assert.NoError(t, err)
+	assert.Equal(t, []*FetchHistoryItem{{Time: time.Unix(100, 0), Status: 200, Items: 3}}, history)
+
+	err = s.RemoveFeedCaches([]string{"https://example.com"})
+	assert.NoError(t, err)
+	assert.False(t, s.HasParsedFeedCache("https://example.com"))
+
+	err = s.SetCacheBackend(CacheBackendFile)
+	assert.NoError(t, err)
+	files, err = os.ReadDir(cacheDir)
+	assert.NoError(t, err)
+	assert.Len(t, files, 1)
+	assert.Equal(t, cacheInfoFile, files[0].Name())
+}
+
+func Test_Bolt_Shared_Between_Storages(t *testing.T) {
+	t.Setenv(dataPathOverrideName, t.TempDir())
+	a := newTestStorage(t)
+	err := a.SetCacheBackend(CacheBackendBolt)
+	assert.NoError(t, err)
+	b := NewLocalStorage("cleed_test", utils.NewTime())
+
+	// neither storage keeps the database locked between operations
+	done := make(chan struct{})
+	go func() {
+		defer close(done)
+		err := a.SaveFeedCache(bytes.NewBufferString("raw"), "https://example.com")
+		assert.NoError(t, err)
+		err = b.MarkRead([]string{"item-1"}, "https://example.com")
+		assert.NoError(t, err)
+		state, err := a.LoadReadState("https://example.com")
+		assert.NoError(t, err)
+		assert.Contains(t, state, "item-1")
+		err = a.UpdateCacheInfo(func(cacheinfo map[string]*CacheInfoItem) error {
+			cacheinfo["https://example.com"] = &CacheInfoItem{URL: "https://example.com", ETag: "a"}
+			return nil
+		})
+		assert.NoError(t, err)
+		err = b.RemoveFeedCaches([]string{"https://example.com"})
+		assert.NoError(t, err)
+		r, err := a.OpenFeedCache("https://example.com")
+		assert.ErrorIs(t, err, os.ErrNotExist)
+		assert.Nil(t, r)
+	}()
+	select {
+	case <-done:
+	case <-time.After(5 * time.Second):
+		t.Fatal("storages blocked each other")
+	}
+}
+
+func Test_Archive_Append_And_Compact(t *testing.T) {
+	for _, backend := range []string{CacheBackendFile, CacheBackendBolt} {
+		t.Run(backend, func(t *testing.T) {
+			t.Setenv(dataPathOverrideName, t.TempDir())
+			s := newTestStorage(t)
+			err := s.SetCacheBackend(backend)
+			assert.NoError(t, err)
+
+			retention := &ArchiveRetention{MaxCount: 3}
+			for i := 0; i < 5; i++ {
+				published := time.Unix(int64(i), 0)
+				archive, err := s.AddToArchive([]*gofeed.Item{
+					{GUID: fmt.Sprintf("item-%d", i), PublishedParsed: &published},
+				}, "https://example.com", retention)
+				assert.NoError(t, err)
+				assert.Len(t, archive, min(i+1, 3))
+			}
+
+			// items beyond the retention stay in the cache until they
+			// outnumber the kept ones
+			stored, err := s.cacheStore().LoadArchive("https://example.com")
+			assert.NoError(t, err)
+			assert.Len(t, stored, 5)
+
+			published := time.Unix(5, 0)
+			archive, err := s.AddToArchive([]*gofeed.Item{
+				{GUID: "item-5", PublishedParsed: &published},
+			}, "https://example.com", retention)
+			assert.NoError(t, err)
+			assert.Len(t, archive, 3)
+			stored, err = s.cacheStore().LoadArchive("https://example.com")
+			assert.NoError(t, err)
+			ids := make([]string, len(stored))
+			for i := range stored {
+				ids[i] = stored[i].ID
+			}
+			assert.ElementsMatch(t, []string{"item-3", "item-4", "item-5"}, ids)
+		})
+	}
+}
@@ -0,0 +1,51 @@
+package storage
+
+import (
+	"bytes"
+	"encoding/json"
+	"os"
+)
+
+const timelineFile = "timeline"
+
+// TimelineItem is an item of the last displayed timeline. The timeline is
+// saved newest first, so that cleed open finds an item by its index.
+type TimelineItem struct {
+	FeedURL    string          `json:"feedUrl"`
+	ID         string          `json:"id"`
+	Duplicates []*TimelineItem `json:"duplicates,omitempty"` // the same item from other feeds
+}
+
+// LoadTimeline returns the last displayed timeline, or nil if no timeline
+// was displayed yet.
+func (s *LocalStorage) LoadTimeline() ([]*TimelineItem, error) {
+	timelinePath, err := s.JoinCacheDir(timelineFile)
+	if err != nil {
+		return nil, err
+	}
+	b, err := os.ReadFile(timelinePath)
+	if err != nil {
+		if os.IsNotExist(err) {
+			return nil, nil
+		}
+		return nil, err
+	}
+	timeline := make([]*TimelineItem, 0)
+	err = json.Unmarshal(b, &timeline)
+	if err != nil {
+		return nil, err
+	}
+	return timeline, nil
+}
+
+func (s *LocalStorage) SaveTimeline(timeline []*TimelineItem) error {
+	timelinePath, err := s.JoinCacheDir(timelineFile)
+	if err != nil {
+		return err
+	}
+	b, err := json.Marshal(timeline)
+	if err != nil {
+		return err
+	}
+	return writeFileAtomic(timelinePath, bytes.NewReader(b), 0600)
+}
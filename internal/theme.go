@@ -0,0 +1,246 @@
+package internal
+
+import (
+	"fmt"
+	"slices"
+
+	"github.com/radulucut/cleed/internal/storage"
+)
+
+const (
+	ThemeDefault      = "default"
+	ThemeLight        = "light"
+	ThemeDark         = "dark"
+	ThemeSolarized    = "solarized"
+	ThemeHighContrast = "high-contrast"
+	ThemeNoColor      = "no-color"
+)
+
+var Themes = []string{ThemeDefault, ThemeLight, ThemeDark, ThemeSolarized, ThemeHighContrast, ThemeNoColor}
+
+type colorKind uint8
+
+const (
+	colorNone colorKind = iota
+	colorIndex
+	colorRGB
+)
+
+// Color is a color of the 256-color palette or a true color. The zero value
+// keeps the default color of the terminal.
+type Color struct {
+	kind  colorKind
+	index uint8
+	rgb   uint32
+}
+
+func IndexColor(index uint8) Color {
+	return Color{kind: colorIndex, index: index}
+}
+
+func RGBColor(rgb uint32) Color {
+	return Color{kind: colorRGB, rgb: rgb & 0xffffff}
+}
+
+// sequence returns the escape sequence that sets the color as foreground.
+func (c Color) sequence(trueColor bool) string {
+	switch c.kind {
+	case colorIndex:
+		return fmt.Sprintf("\033[38;5;%dm", c.index)
+	case colorRGB:
+		if trueColor {
+			return fmt.Sprintf("\033[38;2;%d;%d;%dm", c.rgb>>16, c.rgb>>8&0xff, c.rgb&0xff)
+		}
+		return fmt.Sprintf("\033[38;5;%dm", rgbToIndex(c.rgb))
+	}
+	return ""
+}
+
+// rgbToIndex returns the nearest color of the 6x6x6 cube or of the grayscale
+// ramp of the 256-color palette.
+func rgbToIndex(rgb uint32) uint8 {
+	r, g, b := int(rgb>>16), int(rgb>>8&0xff), int(rgb&0xff)
+	levels := []int{0, 95, 135, 175, 215, 255}
+	nearest := func(v int) int {
+		i := 0
+		for j, level := range levels {
+			if abs(v-level) < abs(v-levels[i]) {
+				i = j
+			}
+		}
+		return i
+	}
+	ri, gi, bi := nearest(r), nearest(g), nearest(b)
+	cube := 16 + 36*ri + 6*gi + bi
+	cubeDistance := square(r-levels[ri]) + square(g-levels[gi]) + square(b-levels[bi])
+	gray := min(max((r+g+b)/3-8+5, 0)/10, 23)
+	level := 8 + 10*gray
+	grayDistance := square(r-level) + square(g-level) + square(b-level)
+	if grayDistance < cubeDistance {
+		return uint8(232 + gray)
+	}
+	return uint8(cube)
+}
+
+func square(n int) int {
+	return n * n
+}
+
+// Theme defines the colors of the semantic roles of the output.
+type Theme struct {
+	Name      string
+	Feeds     []Color // feed titles and explore lists, assigned in order
+	ItemTitle Color
+	Date      Color
+	Link      Color
+	New       Color // new item marker
+	Highlight Color // search terms and group headers
+	Secondary Color // lists, authors and counts
+	Error     Color // warnings
+}
+
+// ThemeRoles are the names of the roles that can be used in templates.
+var ThemeRoles = []string{"title", "date", "link", "new", "highlight", "secondary", "error"}
+
+// Role returns the color of a role by name.
+func (t *Theme) Role(name string) (Color, bool) {
+	switch name {
+	case "title":
+		return t.ItemTitle, true
+	case "date":
+		return t.Date, true
+	case "link":
+		return t.Link, true
+	case "new":
+		return t.New, true
+	case "highlight":
+		return t.Highlight, true
+	case "secondary":
+		return t.Secondary, true
+	case "error":
+		return t.Error, true
+	}
+	return Color{}, false
+}
+
+// FeedColor returns the color of the n-th feed.
+func (t *Theme) FeedColor(n int) Color {
+	return t.Feeds[n%len(t.Feeds)]
+}
+
+func indexColors(indexes ...uint8) []Color {
+	colors := make([]Color, len(indexes))
+	for i, index := range indexes {
+		colors[i] = IndexColor(index)
+	}
+	return colors
+}
+
+func rgbColors(rgbs ...uint32) []Color {
+	colors := make([]Color, len(rgbs))
+	for i, rgb := range rgbs {
+		colors[i] = RGBColor(rgb)
+	}
+	return colors
+}
+
+var themes = map[string]*Theme{
+	ThemeDefault: {
+		// feeds use the palette in order, as before themes
+		Feeds: func() []Color {
+			colors := make([]Color, 256)
+			for i := range colors {
+				colors[i] = IndexColor(uint8(i))
+			}
+			return colors
+		}(),
+		Date:      IndexColor(7),
+		Link:      IndexColor(7),
+		New:       IndexColor(10),
+		Highlight: IndexColor(10),
+		Secondary: IndexColor(7),
+		Error:     IndexColor(11),
+	},
+	ThemeLight: {
+		Feeds:     rgbColors(0x0550ae, 0x116329, 0x953800, 0x8250df, 0x0a7b83, 0xbf3989, 0x7d4e00, 0x1b7c83),
+		ItemTitle: RGBColor(0x1f2328),
+		Date:      RGBColor(0x656d76),
+		Link:      RGBColor(0x0969da),
+		New:       RGBColor(0x1a7f37),
+		Highlight: RGBColor(0xbc4c00),
+		Secondary: RGBColor(0x656d76),
+		Error:     RGBColor(0xcf222e),
+	},
+	ThemeDark: {
+		Feeds:     rgbColors(0x61afef, 0x98c379, 0xe5c07b, 0xc678dd, 0x56b6c2, 0xd19a66, 0xe06c75, 0xabb2bf),
+		ItemTitle: RGBColor(0xdcdfe4),
+		Date:      RGBColor(0x7f848e),
+		Link:      RGBColor(0x7f848e),
+		New:       RGBColor(0x98c379),
+		Highlight: RGBColor(0xe5c07b),
+		Secondary: RGBColor(0x7f848e),
+		Error:     RGBColor(0xe06c75),
+	},
+	ThemeSolarized: {
+		Feeds:     rgbColors(0x268bd2, 0x2aa198, 0x859900, 0xb58900, 0xcb4b16, 0xd33682, 0x6c71c4, 0xdc322f),
+		Date:      RGBColor(0x93a1a1),
+		Link:      RGBColor(0x839496),
+		New:       RGBColor(0x859900),
+		Highlight: RGBColor(0xb58900),
+		Secondary: RGBColor(0x93a1a1),
+		Error:     RGBColor(0xdc322f),
+	},
+	ThemeHighContrast: {
+		// the bright colors of the 16-color palette, which follow the
+		// terminal's own contrast settings
+		Feeds:     indexColors(14, 11, 13, 10, 12, 9),
+		ItemTitle: IndexColor(15),
+		Date:      IndexColor(15),
+		Link:      IndexColor(14),
+		New:       IndexColor(10),
+		Highlight: IndexColor(11),
+		Secondary: IndexColor(15),
+		Error:     IndexColor(9),
+	},
+	ThemeNoColor: {
+		Feeds: []Color{{}},
+	},
+}
+
+func init() {
+	for name, theme := range themes {
+		theme.Name = name
+	}
+}
+
+// configTheme returns the configured theme with the color map of the config
+// applied to its palette colors.
+func configTheme(config *storage.Config) *Theme {
+	t, ok := themes[config.Theme]
+	if !ok {
+		t = themes[ThemeDefault]
+	}
+	if len(config.ColorMap) == 0 {
+		return t
+	}
+	mapped := *t
+	mapped.Feeds = slices.Clone(t.Feeds)
+	for i := range mapped.Feeds {
+		mapped.Feeds[i] = mapColor(mapped.Feeds[i], config)
+	}
+	for _, c := range []*Color{&mapped.ItemTitle, &mapped.Date, &mapped.Link, &mapped.New, &mapped.Highlight, &mapped.Secondary, &mapped.Error} {
+		*c = mapColor(*c, config)
+	}
+	return &mapped
+}
+
+// mapColor maps palette colors with the color map of the config.
+func mapColor(color Color, config *storage.Config) Color {
+	if color.kind != colorIndex {
+		return color
+	}
+	if c, ok := config.ColorMap[color.index]; ok {
+		return IndexColor(c)
+	}
+	return color
+}
@@ -0,0 +1,109 @@
+package internal
+
+import (
+	"maps"
+	"slices"
+
+	"github.com/radulucut/cleed/internal/storage"
+	"github.com/radulucut/cleed/internal/utils"
+)
+
+// Undo restores the lists as they were before the last change.
+func (f *TerminalFeed) Undo(dryRun bool) error {
+	if dryRun {
+		journal, err := f.storage.LoadJournal()
+		if err != nil {
+			return utils.NewInternalError("failed to load journal: " + err.Error())
+		}
+		if len(journal) == 0 {
+			f.printer.Println("nothing to undo")
+			return nil
+		}
+		entry := journal[len(journal)-1]
+		lists, err := f.storage.LoadLists()
+		if err != nil {
+			return utils.NewInternalError("failed to load lists: " + err.Error())
+		}
+		f.printer.Printf("would undo: %s (%s)\n", entry.Description, entry.Time.Format("2006-01-02 15:04:05"))
+		for _, list := range slices.Sorted(maps.Keys(entry.Lists)) {
+			items := entry.Lists[list]
+			if items == nil {
+				f.printer.Printf("  list %s would be removed\n", list)
+			} else if slices.Contains(entry.Removed, list) && slices.Contains(lists, list) {
+				f.printer.Printf("  list %s would be merged with %s\n", list, utils.Pluralize(int64(len(items)), "feed"))
+			} else {
+				f.printer.Printf("  list %s would be restored with %s\n", list, utils.Pluralize(int64(len(items)), "feed"))
+			}
+		}
+		return nil
+	}
+	entry, err := f.storage.Undo()
+	if err != nil {
+		return utils.NewInternalError("failed to undo: " + err.Error())
+	}
+	if entry == nil {
+		f.printer.Println("nothing to undo")
+		return nil
+	}
+	f.printer.Printf("undone: %s\n", entry.Description)
+	return nil
+}
+
+func (f *TerminalFeed) PreviewUnfollow(urls []string, list string) error {
+	config, err := f.storage.LoadConfig()
+	if err != nil {
+		return utils.NewInternalError("failed to load config: " + err.Error())
+	}
+	feeds, err := f.storage.GetFeedsFromList(list)
+	if err != nil {
+		return utils.NewInternalError("failed to list feeds: " + err.Error())
+	}
+	if len(feeds) == 0 {
+		return utils.NewInternalError("no items in list: " + list)
+	}
+	addresses := followedAddresses(urls, feeds)
+	for i := range urls {
+		if slices.ContainsFunc(feeds, func(item *storage.ListItem) bool { return item.Address == addresses[i] }) {
+			f.printer.Print(urls[i] + " would be removed from the list\n")
+		} else {
+			f.printer.Print(f.printer.Color(urls[i]+" was not found in the list", configTheme(config).Error), "\n")
+		}
+	}
+	return nil
+}
+
+func (f *TerminalFeed) PreviewMergeLists(list, otherList string) error {
+	items := make(map[string]*storage.ListItem)
+	err := f.storage.LoadFeedsFromList(items, list)
+	if err != nil {
+		return utils.NewInternalError("failed to list feeds: " + err.Error())
+	}
+	other, err := f.storage.GetFeedsFromList(otherList)
+	if err != nil {
+		return utils.NewInternalError("failed to list feeds: " + err.Error())
+	}
+	if other == nil {
+		return utils.NewInternalError("list not found: " + otherList)
+	}
+	added := 0
+	for _, item := range other {
+		if _, ok := items[item.Address]; !ok {
+			added++
+		}
+	}
+	f.printer.Printf("list %s would be merged with %s (%s added). %s would be removed\n",
+		list, otherList, utils.Pluralize(int64(added), "feed"), otherList)
+	return nil
+}
+
+func (f *TerminalFeed) PreviewRemoveList(list string) error {
+	feeds, err := f.storage.GetFeedsFromList(list)
+	if err != nil {
+		return utils.NewInternalError("failed to list feeds: " + err.Error())
+	}
+	if feeds == nil {
+		return utils.NewInternalError("list not found: " + list)
+	}
+	f.printer.Printf("list %s would be removed with %s\n", list, utils.Pluralize(int64(len(feeds)), "feed"))
+	return nil
+}
@@ -0,0 +1,194 @@
+package utils
+
+import (
+	"fmt"
+	"maps"
+	"slices"
+	"strings"
+	"unicode"
+	"unicode/utf8"
+
+	"golang.org/x/net/html"
+	"golang.org/x/text/cases"
+	"golang.org/x/text/unicode/norm"
+)
+
+type Token struct {
+	Term     string
+	Start    int // byte offset in the analyzed text
+	End      int
+	Position int
+	Stop     bool // the term is a stop word of the analyzer language
+}
+
+type language struct {
+	stem      func(string) string
+	stopWords map[string]struct{}
+}
+
+var languages = map[string]*language{
+	"english": {
+		stem:      StemEnglish,
+		stopWords: englishStopWords,
+	},
+}
+
+// Languages returns the languages supported by NewAnalyzer.
+func Languages() []string {
+	return slices.Sorted(maps.Keys(languages))
+}
+
+// Analyzer turns text into normalized terms: case folded, without
+// diacritics and, if a language is set, stemmed.
+type Analyzer struct {
+	name     string
+	language *language
+}
+
+// NewAnalyzer returns an analyzer for a language. An empty language only
+// normalizes terms.
+func NewAnalyzer(name string) (*Analyzer, error) {
+	a := &Analyzer{name: name}
+	if name == "" {
+		return a, nil
+	}
+	a.language = languages[strings.ToLower(name)]
+	if a.language == nil {
+		return nil, fmt.Errorf("unsupported language: %s, supported languages: %s", name, strings.Join(Languages(), ", "))
+	}
+	a.name = strings.ToLower(name)
+	return a, nil
+}
+
+func (a *Analyzer) Language() string {
+	return a.name
+}
+
+// Tokens splits s into terms on anything that is not part of a word,
+// keeping the position of each term in s. Scripts that are written without
+// spaces (Han, Hiragana, Katakana) are split into single characters.
+func (a *Analyzer) Tokens(s string) []Token {
+	tokens := make([]Token, 0)
+	start := -1
+	for i, r := range s {
+		if isIdeograph(r) {
+			if start != -1 {
+				tokens = a.appendToken(tokens, s, start, i)
+				start = -1
+			}
+			tokens = a.appendToken(tokens, s, i, i+utf8.RuneLen(r))
+			continue
+		}
+		if isWordRune(r) || (start != -1 && (unicode.IsMark(r) || isInnerApostrophe(s, i, r))) {
+			if start == -1 {
+				start = i
+			}
+			continue
+		}
+		if start != -1 {
+			tokens = a.appendToken(tokens, s, start, i)
+			start = -1
+		}
+	}
+	if start != -1 {
+		tokens = a.appendToken(tokens, s, start, len(s))
+	}
+	return tokens
+}
+
+// Terms returns the terms of s. See Tokens.
+func (a *Analyzer) Terms(s string) []string {
+	tokens := a.Tokens(s)
+	terms := make([]string, len(tokens))
+	for i := range tokens {
+		terms[i] = tokens[i].Term
+	}
+	return terms
+}
+
+func (a *Analyzer) appendToken(tokens []Token, s string, start, end int) []Token {
+	term := Normalize(s[start:end])
+	token := Token{
+		Term:     term,
+		Start:    start,
+		End:      end,
+		Position: len(tokens),
+	}
+	if a.language != nil {
+		_, token.Stop = a.language.stopWords[term]
+		token.Term = a.language.stem(term)
+	}
+	return append(tokens, token)
+}
+
+var folder = cases.Fold()
+
+// Normalize case folds a word and removes the diacritics of Latin, Greek and
+// Cyrillic letters. Possessive endings and apostrophes are removed.
+func Normalize(s string) string {
+	s = folder.String(norm.NFD.String(s))
+	b := strings.Builder{}
+	b.Grow(len(s))
+	var base rune
+	for _, r := range s {
+		if unicode.Is(unicode.Mn, r) && hasRemovableMarks(base) {
+			continue
+		}
+		if !unicode.IsMark(r) {
+			base = r
+		}
+		b.WriteRune(r)
+	}
+	s = norm.NFC.String(b.String())
+	if !strings.ContainsAny(s, "'’") {
+		return s
+	}
+	s = strings.TrimSuffix(strings.TrimSuffix(s, "'s"), "’s")
+	return strings.NewReplacer("'", "", "’", "").Replace(s)
+}
+
+func hasRemovableMarks(r rune) bool {
+	return unicode.In(r, unicode.Latin, unicode.Greek, unicode.Cyrillic)
+}
+
+func isWordRune(r rune) bool {
+	return unicode.IsLetter(r) || unicode.IsDigit(r)
+}
+
+func isIdeograph(r rune) bool {
+	return unicode.In(r, unicode.Han, unicode.Hiragana, unicode.Katakana)
+}
+
+// isInnerApostrophe reports whether the apostrophe at i is followed by a
+// letter, as in "don't" or "Go's".
+func isInnerApostrophe(s string, i int, r rune) bool {
+	if r != '\'' && r != '’' {
+		return false
+	}
+	next, _ := utf8.DecodeRuneInString(s[i+utf8.RuneLen(r):])
+	return unicode.IsLetter(next) && !isIdeograph(next)
+}
+
+// StripHTML returns the text content of an HTML fragment.
+func StripHTML(s string) string {
+	if !strings.ContainsAny(s, "<&") {
+		return s
+	}
+	b := strings.Builder{}
+	z := html.NewTokenizer(strings.NewReader(s))
+	for {
+		switch z.Next() {
+		case html.ErrorToken:
+			return b.String()
+		case html.TextToken:
+			text := z.Text()
+			if !utf8.Valid(text) {
+				continue
+			}
+			if b.Len() > 0 {
+				b.WriteByte(' ')
+			}
+			b.Write(text)
+		}
+	}
+}
@@ -0,0 +1,93 @@
+package utils
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func Test_StemEnglish(t *testing.T) {
+	words := map[string]string{
+		"caresses":       "caress",
+		"ponies":         "poni",
+		"ties":           "ti",
+		"cats":           "cat",
+		"feed":           "feed",
+		"agreed":         "agre",
+		"plastered":      "plaster",
+		"motoring":       "motor",
+		"sing":           "sing",
+		"conflated":      "conflat",
+		"troubled":       "troubl",
+		"sized":          "size",
+		"hopping":        "hop",
+		"falling":        "fall",
+		"hissing":        "hiss",
+		"filing":         "file",
+		"happy":          "happi",
+		"relational":     "relat",
+		"conditional":    "condit",
+		"generalization": "gener",
+		"hopefulness":    "hope",
+		"electrical":     "electr",
+		"adjustment":     "adjust",
+		"adoption":       "adopt",
+		"controlling":    "control",
+		"roll":           "roll",
+		"releases":       "releas",
+		"released":       "releas",
+		"kubernetes":     "kubernet",
+		"go":             "go",
+		"café":           "café",
+	}
+	for word, stem := range words {
+		assert.Equal(t, stem, StemEnglish(word), word)
+	}
+}
+
+func Test_Analyzer_Normalize(t *testing.T) {
+	a, err := NewAnalyzer("")
+	assert.NoError(t, err)
+
+	assert.Equal(t, []string{"cafe", "creme", "brulee"}, a.Terms("Café, Crème BRÛLÉE!"))
+	assert.Equal(t, []string{"kubernetes", "and", "go"}, a.Terms("Kubernetes, and Go's"))
+	assert.Equal(t, []string{"dont", "oreilly"}, a.Terms("don't O’Reilly"))
+	assert.Equal(t, []string{"strasse"}, a.Terms("Straße"))
+	assert.Equal(t, []string{"node", "js", "2024"}, a.Terms("node.js (2024)"))
+	assert.Equal(t, []string{"cafe"}, a.Terms("café"))
+}
+
+func Test_Analyzer_Non_Latin_Scripts(t *testing.T) {
+	a, err := NewAnalyzer("english")
+	assert.NoError(t, err)
+
+	// Greek: case folding, final sigma and tonos
+	assert.Equal(t, a.Terms("σίσυφος"), a.Terms("ΣΊΣΥΦΟΣ"))
+	assert.Equal(t, []string{"σισυφοσ"}, a.Terms("Σίσυφος"))
+	// Cyrillic: case folding and diacritics
+	assert.Equal(t, []string{"привет", "мир", "еж"}, a.Terms("Привет, МИР! Ёж"))
+	// Han, Hiragana and Katakana are split into characters
+	assert.Equal(t, []string{"東", "京", "タ", "ワ", "ー", "tokyo"}, a.Terms("東京タワー tokyo"))
+	// Hangul syllables are kept composed
+	assert.Equal(t, []string{"한국어", "뉴스"}, a.Terms("한국어 뉴스"))
+	// Devanagari keeps its vowel signs
+	assert.Equal(t, []string{"हिन्दी", "समाचार"}, a.Terms("हिन्दी समाचार"))
+	// Arabic
+	assert.Equal(t, []string{"أخبار", "اليوم"}, a.Terms("أخبار اليوم"))
+}
+
+func Test_Analyzer_Tokens(t *testing.T) {
+	a, err := NewAnalyzer("English")
+	assert.NoError(t, err)
+	assert.Equal(t, "english", a.Language())
+
+	assert.Equal(t, []Token{
+		{Term: "the", Start: 0, End: 3, Position: 0, Stop: true},
+		{Term: "cafe", Start: 4, End: 10, Position: 1},
+		{Term: "open", Start: 11, End: 18, Position: 2},
+		{Term: "東", Start: 19, End: 22, Position: 3},
+	}, a.Tokens("The Cafés opening 東"))
+
+	_, err = NewAnalyzer("klingon")
+	assert.EqualError(t, err, "unsupported language: klingon, supported languages: english")
+}
@@ -2,10 +2,17 @@ package utils
 
 import (
 	"fmt"
-	"math"
-	"unicode"
+	"regexp"
+	"strings"
+	"unicode/utf8"
+
+	"github.com/mattn/go-runewidth"
 )
 
+// escapeSequence matches the color and hyperlink escape sequences, which take
+// no space in a terminal.
+var escapeSequence = regexp.MustCompile(`\x1b\[[0-9;]*m|\x1b\]8;;[^\x1b]*\x1b\\`)
+
 func Pluralize(count int64, singular string) string {
 	if count == 1 {
 		return fmt.Sprintf("%d %s", count, singular)
@@ -13,41 +20,6 @@ func Pluralize(count int64, singular string) string {
 	return fmt.Sprintf("%d %ss", count, singular)
 }
 
-func Tokenize(s string, tokens [][]rune) [][]rune {
-	var token []rune
-	for _, r := range s {
-		if !unicode.IsSpace(r) {
-			token = append(token, r)
-		} else if len(token) > 0 {
-			tokens = append(tokens, token)
-			token = nil
-		}
-	}
-	if len(token) > 0 {
-		tokens = append(tokens, token)
-	}
-	return tokens
-}
-
-func Score(q, b [][]rune) int {
-	var score int
-	skip := true
-	for i := range q {
-		best := math.MaxInt
-		for j := range b {
-			best = min(best, LevenshteinDistance(q[i], b[j]))
-		}
-		if best <= 2 {
-			skip = false
-		}
-		score += best
-	}
-	if skip {
-		return -1
-	}
-	return score
-}
-
 func LevenshteinDistance(a, b []rune) int {
 	if len(a) == 0 {
 		return len(b)
@@ -77,3 +49,76 @@ func LevenshteinDistance(a, b []rune) int {
 	}
 	return row[la]
 }
+
+// DisplayRows returns the number of rows s takes in a terminal that is width
+// cells wide, counting the lines that wrap.
+func DisplayRows(s string, width int) int {
+	rows := 0
+	for line := range strings.Lines(s) {
+		w := runewidth.StringWidth(escapeSequence.ReplaceAllString(strings.TrimSuffix(line, "\n"), ""))
+		if w > 0 && width > 0 {
+			rows += (w-1)/width + 1
+		} else {
+			rows++
+		}
+	}
+	return rows
+}
+
+// Wrap splits s into lines of at most width cells, breaking at spaces when
+// possible. Spaces are collapsed when the text is wrapped.
+func Wrap(s string, width int) []string {
+	if width <= 0 || runewidth.StringWidth(s) <= width {
+		return []string{s}
+	}
+	lines := make([]string, 0)
+	line := ""
+	for _, word := range strings.Fields(s) {
+		for runewidth.StringWidth(word) > width {
+			if line != "" {
+				lines = append(lines, line)
+				line = ""
+			}
+			part := runewidth.Truncate(word, width, "")
+			if part == "" {
+				// the first rune is wider than the line, use a line for it
+				_, size := utf8.DecodeRuneInString(word)
+				part = word[:size]
+			}
+			lines = append(lines, part)
+			word = word[len(part):]
+		}
+		if word == "" {
+			continue
+		}
+		if line == "" {
+			line = word
+		} else if runewidth.StringWidth(line)+1+runewidth.StringWidth(word) > width {
+			lines = append(lines, line)
+			line = word
+		} else {
+			line += " " + word
+		}
+	}
+	if line != "" || len(lines) == 0 {
+		lines = append(lines, line)
+	}
+	return lines
+}
+
+// FormatSize formats a number of bytes with a binary unit, e.g. 24.5 MB.
+func FormatSize(bytes int64) string {
+	if bytes < 1024 {
+		return fmt.Sprintf("%d B", bytes)
+	}
+	size := float64(bytes)
+	units := []string{"KB", "MB", "GB", "TB"}
+	unit := ""
+	for _, unit = range units {
+		size /= 1024
+		if size < 1024 {
+			break
+		}
+	}
+	return fmt.Sprintf("%.1f %s", size, unit)
+}
@@ -0,0 +1,33 @@
+package utils
+
+import (
+	"math"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func Test_Wrap(t *testing.T) {
+	assert.Equal(t, []string{"short title"}, Wrap("short title", 20))
+	assert.Equal(t, []string{"a longer title", "that needs to be", "wrapped"}, Wrap("a longer title that needs to be wrapped", 16))
+	assert.Equal(t, []string{"abcdefgh", "ijkl end"}, Wrap("abcdefghijkl end", 8))
+	assert.Equal(t, []string{"日本語", "のタイ", "トル"}, Wrap("日本語のタイトル", 7))
+	assert.Equal(t, []string{"no limit"}, Wrap("no limit", 0))
+	assert.Equal(t, []string{"中", "文", "字", "a", "b", "c"}, Wrap("中文字 abc", 1))
+}
+
+func Test_DisplayRows(t *testing.T) {
+	assert.Equal(t, 0, DisplayRows("", 10))
+	assert.Equal(t, 2, DisplayRows("short\n\n", 10))
+	assert.Equal(t, 3, DisplayRows("a line that wraps\nend\n", 10))
+	assert.Equal(t, 2, DisplayRows("日本語のタイトル", 10))
+	assert.Equal(t, 1, DisplayRows("\033[38;5;32mcolored\033[0m \033]8;;https://example.com\033\\link\033]8;;\033\\\n", 20))
+	assert.Equal(t, 1, DisplayRows("no limit", math.MaxInt))
+}
+
+func Test_FormatSize(t *testing.T) {
+	assert.Equal(t, "512 B", FormatSize(512))
+	assert.Equal(t, "1.5 KB", FormatSize(1536))
+	assert.Equal(t, "24.5 MB", FormatSize(25690112))
+	assert.Equal(t, "1.0 GB", FormatSize(1<<30))
+}
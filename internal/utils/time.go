@@ -4,6 +4,7 @@ import (
 	"fmt"
 	"math"
 	"strconv"
+	"strings"
 	_time "time"
 )
 
@@ -95,3 +96,30 @@ func ParseDateTime(s string) (_time.Time, error) {
 	}
 	return _time.Time{}, fmt.Errorf("invalid datetime: %s", s)
 }
+
+// ParseClock parses durations written as seconds, MM:SS or HH:MM:SS, as used
+// by podcast feeds.
+func ParseClock(s string) (_time.Duration, error) {
+	parts := strings.Split(strings.TrimSpace(s), ":")
+	if len(parts) > 3 {
+		return 0, fmt.Errorf("invalid duration: %s", s)
+	}
+	var seconds int64
+	for _, part := range parts {
+		n, err := strconv.ParseInt(part, 10, 64)
+		if err != nil || n < 0 {
+			return 0, fmt.Errorf("invalid duration: %s", s)
+		}
+		seconds = seconds*60 + n
+	}
+	return _time.Duration(seconds) * _time.Second, nil
+}
+
+// FormatClock formats a duration as MM:SS or, from an hour, H:MM:SS.
+func FormatClock(d _time.Duration) string {
+	seconds := int64(d.Round(_time.Second) / _time.Second)
+	if seconds >= 3600 {
+		return fmt.Sprintf("%d:%02d:%02d", seconds/3600, seconds/60%60, seconds%60)
+	}
+	return fmt.Sprintf("%d:%02d", seconds/60, seconds%60)
+}
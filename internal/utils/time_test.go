@@ -86,3 +86,29 @@ func Test_Relative(t *testing.T) {
 	s = Relative(-30)
 	assert.Equal(t, "in 30 seconds", s)
 }
+
+func Test_ParseClock(t *testing.T) {
+	d, err := ParseClock("1:02:03")
+	assert.NoError(t, err)
+	assert.Equal(t, _time.Hour+2*_time.Minute+3*_time.Second, d)
+
+	d, err = ParseClock("45:12")
+	assert.NoError(t, err)
+	assert.Equal(t, 45*_time.Minute+12*_time.Second, d)
+
+	d, err = ParseClock("3600")
+	assert.NoError(t, err)
+	assert.Equal(t, _time.Hour, d)
+
+	_, err = ParseClock("1:2:3:4")
+	assert.EqualError(t, err, "invalid duration: 1:2:3:4")
+
+	_, err = ParseClock("ten minutes")
+	assert.EqualError(t, err, "invalid duration: ten minutes")
+}
+
+func Test_FormatClock(t *testing.T) {
+	assert.Equal(t, "0:59", FormatClock(59*_time.Second))
+	assert.Equal(t, "45:12", FormatClock(45*_time.Minute+12*_time.Second))
+	assert.Equal(t, "1:02:03", FormatClock(_time.Hour+2*_time.Minute+3*_time.Second))
+}
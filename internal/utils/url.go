@@ -0,0 +1,82 @@
+package utils
+
+import (
+	"net/url"
+	"slices"
+	"strings"
+)
+
+// trackingParams are query parameters that only track where a visitor came
+// from. Parameters starting with utm_ are removed as well.
+var trackingParams = []string{
+	"fbclid",
+	"gclid",
+	"dclid",
+	"msclkid",
+	"yclid",
+	"igshid",
+	"mc_cid",
+	"mc_eid",
+	"_hsenc",
+	"_hsmi",
+	"ref_src",
+}
+
+// CanonicalURL normalizes a URL without changing the resource it points to.
+// The scheme and host are lowercased, default ports, fragments and tracking
+// parameters are removed and the remaining query parameters are sorted.
+// Invalid URLs are returned unchanged.
+func CanonicalURL(s string) string {
+	u := canonicalURL(s)
+	if u == nil {
+		return strings.TrimSpace(s)
+	}
+	return u.String()
+}
+
+// URLKey returns a key that is the same for URLs that most likely point to the
+// same resource. On top of CanonicalURL it ignores the scheme, a leading www.
+// and trailing slashes, e.g. http://www.example.com/feed/ and
+// https://example.com/feed have the same key.
+func URLKey(s string) string {
+	u := canonicalURL(s)
+	if u == nil {
+		return strings.TrimSpace(s)
+	}
+	u.Scheme = ""
+	u.Host = strings.TrimPrefix(u.Host, "www.")
+	u.Path = strings.TrimRight(u.Path, "/")
+	return strings.TrimPrefix(u.String(), "//")
+}
+
+func canonicalURL(s string) *url.URL {
+	u, err := url.Parse(strings.TrimSpace(s))
+	if err != nil || u.Host == "" {
+		return nil
+	}
+	u.Scheme = strings.ToLower(u.Scheme)
+	host := strings.ToLower(u.Hostname())
+	if strings.Contains(host, ":") {
+		host = "[" + host + "]"
+	}
+	port := u.Port()
+	if port != "" && !(u.Scheme == "http" && port == "80") && !(u.Scheme == "https" && port == "443") {
+		host += ":" + port
+	}
+	u.Host = host
+	u.Fragment = ""
+	u.RawFragment = ""
+	if u.RawQuery != "" {
+		query := u.Query()
+		for key := range query {
+			lower := strings.ToLower(key)
+			if strings.HasPrefix(lower, "utm_") || slices.Contains(trackingParams, lower) {
+				query.Del(key)
+			}
+		}
+		u.RawQuery = query.Encode()
+	}
+	u.ForceQuery = false
+	u.RawPath = ""
+	return u
+}
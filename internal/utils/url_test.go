@@ -0,0 +1,26 @@
+package utils
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func Test_CanonicalURL(t *testing.T) {
+	assert.Equal(t, "https://example.com/post/", CanonicalURL("https://example.com/post/"))
+	assert.Equal(t, "https://example.com/post", CanonicalURL("HTTPS://Example.COM:443/post#comments"))
+	assert.Equal(t, "http://example.com:8080/post", CanonicalURL("http://example.com:8080/post"))
+	assert.Equal(t, "https://example.com/post?a=1&b=2", CanonicalURL("https://example.com/post?b=2&utm_source=rss&a=1&UTM_Medium=feed"))
+	assert.Equal(t, "https://example.com/post", CanonicalURL("https://example.com/post?utm_source=rss&fbclid=abc"))
+	assert.Equal(t, "https://example.com/", CanonicalURL(" https://example.com/ "))
+	assert.Equal(t, "https://[::1]:8443/post", CanonicalURL("https://[::1]:8443/post"))
+	assert.Equal(t, "/relative/post/", CanonicalURL("/relative/post/"))
+}
+
+func Test_URLKey(t *testing.T) {
+	assert.Equal(t, "example.com/feed", URLKey("https://example.com/feed"))
+	assert.Equal(t, "example.com/feed", URLKey("http://www.example.com/feed/"))
+	assert.Equal(t, "example.com/feed?a=1", URLKey("HTTP://Example.com:80/feed/?a=1&utm_campaign=x#top"))
+	assert.Equal(t, "example.com", URLKey("https://example.com/"))
+	assert.Equal(t, "example.com:8080/feed", URLKey("https://example.com:8080/feed"))
+}
@@ -1,3 +1,4 @@
 rm -rf mocks/mock_*.go
 
 bin/mockgen -source internal/utils/time.go -destination mocks/mock_time.go -package mocks
+bin/mockgen -source internal/storage/interfaces.go -destination mocks/mock_storage.go -package mocks
@@ -0,0 +1,1279 @@
+// Code generated by MockGen. DO NOT EDIT.
+// Source: internal/storage/interfaces.go
+//
+// Generated by this command:
+//
+//	mockgen -source internal/storage/interfaces.go -destination mocks/mock_storage.go -package mocks
+//
+
+// Package mocks is a generated GoMock package.
+package mocks
+
+import (
+	io "io"
+	reflect "reflect"
+	time "time"
+
+	gofeed "github.com/mmcdole/gofeed"
+	storage "github.com/radulucut/cleed/internal/storage"
+	gomock "go.uber.org/mock/gomock"
+)
+
+// MockListStorage is a mock of ListStorage interface.
+type MockListStorage struct {
+	ctrl     *gomock.Controller
+	recorder *MockListStorageMockRecorder
+}
+
+// MockListStorageMockRecorder is the mock recorder for MockListStorage.
+type MockListStorageMockRecorder struct {
+	mock *MockListStorage
+}
+
+// NewMockListStorage creates a new mock instance.
+func NewMockListStorage(ctrl *gomock.Controller) *MockListStorage {
+	mock := &MockListStorage{ctrl: ctrl}
+	mock.recorder = &MockListStorageMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockListStorage) EXPECT() *MockListStorageMockRecorder {
+	return m.recorder
+}
+
+// AddToList mocks base method.
+func (m *MockListStorage) AddToList(urls []string, list string) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "AddToList", urls, list)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// AddToList indicates an expected call of AddToList.
+func (mr *MockListStorageMockRecorder) AddToList(urls, list any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "AddToList", reflect.TypeOf((*MockListStorage)(nil).AddToList), urls, list)
+}
+
+// CopyFeeds mocks base method.
+func (m *MockListStorage) CopyFeeds(urls []string, from, to string) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "CopyFeeds", urls, from, to)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// CopyFeeds indicates an expected call of CopyFeeds.
+func (mr *MockListStorageMockRecorder) CopyFeeds(urls, from, to any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CopyFeeds", reflect.TypeOf((*MockListStorage)(nil).CopyFeeds), urls, from, to)
+}
+
+// GetFeedsFromList mocks base method.
+func (m *MockListStorage) GetFeedsFromList(list string) ([]*storage.ListItem, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetFeedsFromList", list)
+	ret0, _ := ret[0].([]*storage.ListItem)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetFeedsFromList indicates an expected call of GetFeedsFromList.
+func (mr *MockListStorageMockRecorder) GetFeedsFromList(list any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetFeedsFromList", reflect.TypeOf((*MockListStorage)(nil).GetFeedsFromList), list)
+}
+
+// LoadFeedsFromList mocks base method.
+func (m_2 *MockListStorage) LoadFeedsFromList(m map[string]*storage.ListItem, list string) error {
+	m_2.ctrl.T.Helper()
+	ret := m_2.ctrl.Call(m_2, "LoadFeedsFromList", m, list)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// LoadFeedsFromList indicates an expected call of LoadFeedsFromList.
+func (mr *MockListStorageMockRecorder) LoadFeedsFromList(m, list any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "LoadFeedsFromList", reflect.TypeOf((*MockListStorage)(nil).LoadFeedsFromList), m, list)
+}
+
+// LoadJournal mocks base method.
+func (m *MockListStorage) LoadJournal() ([]*storage.JournalEntry, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "LoadJournal")
+	ret0, _ := ret[0].([]*storage.JournalEntry)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// LoadJournal indicates an expected call of LoadJournal.
+func (mr *MockListStorageMockRecorder) LoadJournal() *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "LoadJournal", reflect.TypeOf((*MockListStorage)(nil).LoadJournal))
+}
+
+// LoadLists mocks base method.
+func (m *MockListStorage) LoadLists() ([]string, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "LoadLists")
+	ret0, _ := ret[0].([]string)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// LoadLists indicates an expected call of LoadLists.
+func (mr *MockListStorageMockRecorder) LoadLists() *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "LoadLists", reflect.TypeOf((*MockListStorage)(nil).LoadLists))
+}
+
+// MergeLists mocks base method.
+func (m *MockListStorage) MergeLists(list, otherList string) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "MergeLists", list, otherList)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// MergeLists indicates an expected call of MergeLists.
+func (mr *MockListStorageMockRecorder) MergeLists(list, otherList any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "MergeLists", reflect.TypeOf((*MockListStorage)(nil).MergeLists), list, otherList)
+}
+
+// MoveFeeds mocks base method.
+func (m *MockListStorage) MoveFeeds(urls []string, from, to string) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "MoveFeeds", urls, from, to)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// MoveFeeds indicates an expected call of MoveFeeds.
+func (mr *MockListStorageMockRecorder) MoveFeeds(urls, from, to any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "MoveFeeds", reflect.TypeOf((*MockListStorage)(nil).MoveFeeds), urls, from, to)
+}
+
+// RemoveFromList mocks base method.
+func (m *MockListStorage) RemoveFromList(urls []string, list string) ([]bool, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "RemoveFromList", urls, list)
+	ret0, _ := ret[0].([]bool)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// RemoveFromList indicates an expected call of RemoveFromList.
+func (mr *MockListStorageMockRecorder) RemoveFromList(urls, list any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "RemoveFromList", reflect.TypeOf((*MockListStorage)(nil).RemoveFromList), urls, list)
+}
+
+// RemoveList mocks base method.
+func (m *MockListStorage) RemoveList(list string) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "RemoveList", list)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// RemoveList indicates an expected call of RemoveList.
+func (mr *MockListStorageMockRecorder) RemoveList(list any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "RemoveList", reflect.TypeOf((*MockListStorage)(nil).RemoveList), list)
+}
+
+// RenameList mocks base method.
+func (m *MockListStorage) RenameList(oldName, newName string) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "RenameList", oldName, newName)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// RenameList indicates an expected call of RenameList.
+func (mr *MockListStorageMockRecorder) RenameList(oldName, newName any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "RenameList", reflect.TypeOf((*MockListStorage)(nil).RenameList), oldName, newName)
+}
+
+// Undo mocks base method.
+func (m *MockListStorage) Undo() (*storage.JournalEntry, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Undo")
+	ret0, _ := ret[0].(*storage.JournalEntry)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// Undo indicates an expected call of Undo.
+func (mr *MockListStorageMockRecorder) Undo() *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Undo", reflect.TypeOf((*MockListStorage)(nil).Undo))
+}
+
+// MockCacheStorage is a mock of CacheStorage interface.
+type MockCacheStorage struct {
+	ctrl     *gomock.Controller
+	recorder *MockCacheStorageMockRecorder
+}
+
+// MockCacheStorageMockRecorder is the mock recorder for MockCacheStorage.
+type MockCacheStorageMockRecorder struct {
+	mock *MockCacheStorage
+}
+
+// NewMockCacheStorage creates a new mock instance.
+func NewMockCacheStorage(ctrl *gomock.Controller) *MockCacheStorage {
+	mock := &MockCacheStorage{ctrl: ctrl}
+	mock.recorder = &MockCacheStorageMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockCacheStorage) EXPECT() *MockCacheStorageMockRecorder {
+	return m.recorder
+}
+
+// AddFetchHistory mocks base method.
+func (m *MockCacheStorage) AddFetchHistory(item *storage.FetchHistoryItem, name string) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "AddFetchHistory", item, name)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// AddFetchHistory indicates an expected call of AddFetchHistory.
+func (mr *MockCacheStorageMockRecorder) AddFetchHistory(item, name any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "AddFetchHistory", reflect.TypeOf((*MockCacheStorage)(nil).AddFetchHistory), item, name)
+}
+
+// AddToArchive mocks base method.
+func (m *MockCacheStorage) AddToArchive(items []*gofeed.Item, name string, retention *storage.ArchiveRetention) ([]*storage.ArchiveItem, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "AddToArchive", items, name, retention)
+	ret0, _ := ret[0].([]*storage.ArchiveItem)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// AddToArchive indicates an expected call of AddToArchive.
+func (mr *MockCacheStorageMockRecorder) AddToArchive(items, name, retention any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "AddToArchive", reflect.TypeOf((*MockCacheStorage)(nil).AddToArchive), items, name, retention)
+}
+
+// HasParsedFeedCache mocks base method.
+func (m *MockCacheStorage) HasParsedFeedCache(name string) bool {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "HasParsedFeedCache", name)
+	ret0, _ := ret[0].(bool)
+	return ret0
+}
+
+// HasParsedFeedCache indicates an expected call of HasParsedFeedCache.
+func (mr *MockCacheStorageMockRecorder) HasParsedFeedCache(name any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "HasParsedFeedCache", reflect.TypeOf((*MockCacheStorage)(nil).HasParsedFeedCache), name)
+}
+
+// LoadArchive mocks base method.
+func (m *MockCacheStorage) LoadArchive(name string) ([]*storage.ArchiveItem, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "LoadArchive", name)
+	ret0, _ := ret[0].([]*storage.ArchiveItem)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// LoadArchive indicates an expected call of LoadArchive.
+func (mr *MockCacheStorageMockRecorder) LoadArchive(name any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "LoadArchive", reflect.TypeOf((*MockCacheStorage)(nil).LoadArchive), name)
+}
+
+// LoadCacheInfo mocks base method.
+func (m *MockCacheStorage) LoadCacheInfo() (map[string]*storage.CacheInfoItem, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "LoadCacheInfo")
+	ret0, _ := ret[0].(map[string]*storage.CacheInfoItem)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// LoadCacheInfo indicates an expected call of LoadCacheInfo.
+func (mr *MockCacheStorageMockRecorder) LoadCacheInfo() *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "LoadCacheInfo", reflect.TypeOf((*MockCacheStorage)(nil).LoadCacheInfo))
+}
+
+// LoadDownloads mocks base method.
+func (m *MockCacheStorage) LoadDownloads(name string) (map[string]time.Time, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "LoadDownloads", name)
+	ret0, _ := ret[0].(map[string]time.Time)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// LoadDownloads indicates an expected call of LoadDownloads.
+func (mr *MockCacheStorageMockRecorder) LoadDownloads(name any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "LoadDownloads", reflect.TypeOf((*MockCacheStorage)(nil).LoadDownloads), name)
+}
+
+// LoadFetchHistory mocks base method.
+func (m *MockCacheStorage) LoadFetchHistory(name string) ([]*storage.FetchHistoryItem, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "LoadFetchHistory", name)
+	ret0, _ := ret[0].([]*storage.FetchHistoryItem)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// LoadFetchHistory indicates an expected call of LoadFetchHistory.
+func (mr *MockCacheStorageMockRecorder) LoadFetchHistory(name any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "LoadFetchHistory", reflect.TypeOf((*MockCacheStorage)(nil).LoadFetchHistory), name)
+}
+
+// LoadParsedFeedCache mocks base method.
+func (m *MockCacheStorage) LoadParsedFeedCache(name string) (*gofeed.Feed, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "LoadParsedFeedCache", name)
+	ret0, _ := ret[0].(*gofeed.Feed)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// LoadParsedFeedCache indicates an expected call of LoadParsedFeedCache.
+func (mr *MockCacheStorageMockRecorder) LoadParsedFeedCache(name any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "LoadParsedFeedCache", reflect.TypeOf((*MockCacheStorage)(nil).LoadParsedFeedCache), name)
+}
+
+// LoadReadState mocks base method.
+func (m *MockCacheStorage) LoadReadState(name string) (map[string]time.Time, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "LoadReadState", name)
+	ret0, _ := ret[0].(map[string]time.Time)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// LoadReadState indicates an expected call of LoadReadState.
+func (mr *MockCacheStorageMockRecorder) LoadReadState(name any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "LoadReadState", reflect.TypeOf((*MockCacheStorage)(nil).LoadReadState), name)
+}
+
+// LoadTimeline mocks base method.
+func (m *MockCacheStorage) LoadTimeline() ([]*storage.TimelineItem, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "LoadTimeline")
+	ret0, _ := ret[0].([]*storage.TimelineItem)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// LoadTimeline indicates an expected call of LoadTimeline.
+func (mr *MockCacheStorageMockRecorder) LoadTimeline() *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "LoadTimeline", reflect.TypeOf((*MockCacheStorage)(nil).LoadTimeline))
+}
+
+// MarkDownloaded mocks base method.
+func (m *MockCacheStorage) MarkDownloaded(ids []string, name string) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "MarkDownloaded", ids, name)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// MarkDownloaded indicates an expected call of MarkDownloaded.
+func (mr *MockCacheStorageMockRecorder) MarkDownloaded(ids, name any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "MarkDownloaded", reflect.TypeOf((*MockCacheStorage)(nil).MarkDownloaded), ids, name)
+}
+
+// MarkRead mocks base method.
+func (m *MockCacheStorage) MarkRead(ids []string, name string) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "MarkRead", ids, name)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// MarkRead indicates an expected call of MarkRead.
+func (mr *MockCacheStorageMockRecorder) MarkRead(ids, name any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "MarkRead", reflect.TypeOf((*MockCacheStorage)(nil).MarkRead), ids, name)
+}
+
+// OpenFeedCache mocks base method.
+func (m *MockCacheStorage) OpenFeedCache(name string) (io.ReadCloser, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "OpenFeedCache", name)
+	ret0, _ := ret[0].(io.ReadCloser)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// OpenFeedCache indicates an expected call of OpenFeedCache.
+func (mr *MockCacheStorageMockRecorder) OpenFeedCache(name any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "OpenFeedCache", reflect.TypeOf((*MockCacheStorage)(nil).OpenFeedCache), name)
+}
+
+// OpenSearchIndex mocks base method.
+func (m *MockCacheStorage) OpenSearchIndex() (io.ReadCloser, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "OpenSearchIndex")
+	ret0, _ := ret[0].(io.ReadCloser)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// OpenSearchIndex indicates an expected call of OpenSearchIndex.
+func (mr *MockCacheStorageMockRecorder) OpenSearchIndex() *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "OpenSearchIndex", reflect.TypeOf((*MockCacheStorage)(nil).OpenSearchIndex))
+}
+
+// PruneArchive mocks base method.
+func (m *MockCacheStorage) PruneArchive(name string, retention *storage.ArchiveRetention) (int, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "PruneArchive", name, retention)
+	ret0, _ := ret[0].(int)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// PruneArchive indicates an expected call of PruneArchive.
+func (mr *MockCacheStorageMockRecorder) PruneArchive(name, retention any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "PruneArchive", reflect.TypeOf((*MockCacheStorage)(nil).PruneArchive), name, retention)
+}
+
+// RemoveFeedCaches mocks base method.
+func (m *MockCacheStorage) RemoveFeedCaches(names []string) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "RemoveFeedCaches", names)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// RemoveFeedCaches indicates an expected call of RemoveFeedCaches.
+func (mr *MockCacheStorageMockRecorder) RemoveFeedCaches(names any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "RemoveFeedCaches", reflect.TypeOf((*MockCacheStorage)(nil).RemoveFeedCaches), names)
+}
+
+// SaveFeedCache mocks base method.
+func (m *MockCacheStorage) SaveFeedCache(r io.Reader, name string) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "SaveFeedCache", r, name)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// SaveFeedCache indicates an expected call of SaveFeedCache.
+func (mr *MockCacheStorageMockRecorder) SaveFeedCache(r, name any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "SaveFeedCache", reflect.TypeOf((*MockCacheStorage)(nil).SaveFeedCache), r, name)
+}
+
+// SaveParsedFeedCache mocks base method.
+func (m *MockCacheStorage) SaveParsedFeedCache(feed *gofeed.Feed, name string) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "SaveParsedFeedCache", feed, name)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// SaveParsedFeedCache indicates an expected call of SaveParsedFeedCache.
+func (mr *MockCacheStorageMockRecorder) SaveParsedFeedCache(feed, name any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "SaveParsedFeedCache", reflect.TypeOf((*MockCacheStorage)(nil).SaveParsedFeedCache), feed, name)
+}
+
+// SaveSearchIndex mocks base method.
+func (m *MockCacheStorage) SaveSearchIndex(r io.Reader) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "SaveSearchIndex", r)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// SaveSearchIndex indicates an expected call of SaveSearchIndex.
+func (mr *MockCacheStorageMockRecorder) SaveSearchIndex(r any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "SaveSearchIndex", reflect.TypeOf((*MockCacheStorage)(nil).SaveSearchIndex), r)
+}
+
+// SaveTimeline mocks base method.
+func (m *MockCacheStorage) SaveTimeline(timeline []*storage.TimelineItem) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "SaveTimeline", timeline)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// SaveTimeline indicates an expected call of SaveTimeline.
+func (mr *MockCacheStorageMockRecorder) SaveTimeline(timeline any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "SaveTimeline", reflect.TypeOf((*MockCacheStorage)(nil).SaveTimeline), timeline)
+}
+
+// UpdateCacheInfo mocks base method.
+func (m *MockCacheStorage) UpdateCacheInfo(update func(map[string]*storage.CacheInfoItem) error) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "UpdateCacheInfo", update)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// UpdateCacheInfo indicates an expected call of UpdateCacheInfo.
+func (mr *MockCacheStorageMockRecorder) UpdateCacheInfo(update any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UpdateCacheInfo", reflect.TypeOf((*MockCacheStorage)(nil).UpdateCacheInfo), update)
+}
+
+// MockConfigStorage is a mock of ConfigStorage interface.
+type MockConfigStorage struct {
+	ctrl     *gomock.Controller
+	recorder *MockConfigStorageMockRecorder
+}
+
+// MockConfigStorageMockRecorder is the mock recorder for MockConfigStorage.
+type MockConfigStorageMockRecorder struct {
+	mock *MockConfigStorage
+}
+
+// NewMockConfigStorage creates a new mock instance.
+func NewMockConfigStorage(ctrl *gomock.Controller) *MockConfigStorage {
+	mock := &MockConfigStorage{ctrl: ctrl}
+	mock.recorder = &MockConfigStorageMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockConfigStorage) EXPECT() *MockConfigStorageMockRecorder {
+	return m.recorder
+}
+
+// LoadConfig mocks base method.
+func (m *MockConfigStorage) LoadConfig() (*storage.Config, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "LoadConfig")
+	ret0, _ := ret[0].(*storage.Config)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// LoadConfig indicates an expected call of LoadConfig.
+func (mr *MockConfigStorageMockRecorder) LoadConfig() *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "LoadConfig", reflect.TypeOf((*MockConfigStorage)(nil).LoadConfig))
+}
+
+// SetCacheBackend mocks base method.
+func (m *MockConfigStorage) SetCacheBackend(backend string) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "SetCacheBackend", backend)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// SetCacheBackend indicates an expected call of SetCacheBackend.
+func (mr *MockConfigStorageMockRecorder) SetCacheBackend(backend any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "SetCacheBackend", reflect.TypeOf((*MockConfigStorage)(nil).SetCacheBackend), backend)
+}
+
+// UpdateConfig mocks base method.
+func (m *MockConfigStorage) UpdateConfig(update func(*storage.Config) error) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "UpdateConfig", update)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// UpdateConfig indicates an expected call of UpdateConfig.
+func (mr *MockConfigStorageMockRecorder) UpdateConfig(update any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UpdateConfig", reflect.TypeOf((*MockConfigStorage)(nil).UpdateConfig), update)
+}
+
+// MockExploreStorage is a mock of ExploreStorage interface.
+type MockExploreStorage struct {
+	ctrl     *gomock.Controller
+	recorder *MockExploreStorageMockRecorder
+}
+
+// MockExploreStorageMockRecorder is the mock recorder for MockExploreStorage.
+type MockExploreStorageMockRecorder struct {
+	mock *MockExploreStorage
+}
+
+// NewMockExploreStorage creates a new mock instance.
+func NewMockExploreStorage(ctrl *gomock.Controller) *MockExploreStorage {
+	mock := &MockExploreStorage{ctrl: ctrl}
+	mock.recorder = &MockExploreStorageMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockExploreStorage) EXPECT() *MockExploreStorageMockRecorder {
+	return m.recorder
+}
+
+// GetExploreRepositoryPath mocks base method.
+func (m *MockExploreStorage) GetExploreRepositoryPath(name string, update bool) (string, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetExploreRepositoryPath", name, update)
+	ret0, _ := ret[0].(string)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetExploreRepositoryPath indicates an expected call of GetExploreRepositoryPath.
+func (mr *MockExploreStorageMockRecorder) GetExploreRepositoryPath(name, update any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetExploreRepositoryPath", reflect.TypeOf((*MockExploreStorage)(nil).GetExploreRepositoryPath), name, update)
+}
+
+// RemoveExploreRepository mocks base method.
+func (m *MockExploreStorage) RemoveExploreRepository(name string) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "RemoveExploreRepository", name)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// RemoveExploreRepository indicates an expected call of RemoveExploreRepository.
+func (mr *MockExploreStorageMockRecorder) RemoveExploreRepository(name any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "RemoveExploreRepository", reflect.TypeOf((*MockExploreStorage)(nil).RemoveExploreRepository), name)
+}
+
+// MockStorage is a mock of Storage interface.
+type MockStorage struct {
+	ctrl     *gomock.Controller
+	recorder *MockStorageMockRecorder
+}
+
+// MockStorageMockRecorder is the mock recorder for MockStorage.
+type MockStorageMockRecorder struct {
+	mock *MockStorage
+}
+
+// NewMockStorage creates a new mock instance.
+func NewMockStorage(ctrl *gomock.Controller) *MockStorage {
+	mock := &MockStorage{ctrl: ctrl}
+	mock.recorder = &MockStorageMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockStorage) EXPECT() *MockStorageMockRecorder {
+	return m.recorder
+}
+
+// AddFetchHistory mocks base method.
+func (m *MockStorage) AddFetchHistory(item *storage.FetchHistoryItem, name string) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "AddFetchHistory", item, name)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// AddFetchHistory indicates an expected call of AddFetchHistory.
+func (mr *MockStorageMockRecorder) AddFetchHistory(item, name any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "AddFetchHistory", reflect.TypeOf((*MockStorage)(nil).AddFetchHistory), item, name)
+}
+
+// AddToArchive mocks base method.
+func (m *MockStorage) AddToArchive(items []*gofeed.Item, name string, retention *storage.ArchiveRetention) ([]*storage.ArchiveItem, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "AddToArchive", items, name, retention)
+	ret0, _ := ret[0].([]*storage.ArchiveItem)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// AddToArchive indicates an expected call of AddToArchive.
+func (mr *MockStorageMockRecorder) AddToArchive(items, name, retention any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "AddToArchive", reflect.TypeOf((*MockStorage)(nil).AddToArchive), items, name, retention)
+}
+
+// AddToList mocks base method.
+func (m *MockStorage) AddToList(urls []string, list string) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "AddToList", urls, list)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// AddToList indicates an expected call of AddToList.
+func (mr *MockStorageMockRecorder) AddToList(urls, list any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "AddToList", reflect.TypeOf((*MockStorage)(nil).AddToList), urls, list)
+}
+
+// CopyFeeds mocks base method.
+func (m *MockStorage) CopyFeeds(urls []string, from, to string) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "CopyFeeds", urls, from, to)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// CopyFeeds indicates an expected call of CopyFeeds.
+func (mr *MockStorageMockRecorder) CopyFeeds(urls, from, to any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CopyFeeds", reflect.TypeOf((*MockStorage)(nil).CopyFeeds), urls, from, to)
+}
+
+// GetExploreRepositoryPath mocks base method.
+func (m *MockStorage) GetExploreRepositoryPath(name string, update bool) (string, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetExploreRepositoryPath", name, update)
+	ret0, _ := ret[0].(string)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetExploreRepositoryPath indicates an expected call of GetExploreRepositoryPath.
+func (mr *MockStorageMockRecorder) GetExploreRepositoryPath(name, update any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetExploreRepositoryPath", reflect.TypeOf((*MockStorage)(nil).GetExploreRepositoryPath), name, update)
+}
+
+// GetFeedsFromList mocks base method.
+func (m *MockStorage) GetFeedsFromList(list string) ([]*storage.ListItem, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetFeedsFromList", list)
+	ret0, _ := ret[0].([]*storage.ListItem)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetFeedsFromList indicates an expected call of GetFeedsFromList.
+func (mr *MockStorageMockRecorder) GetFeedsFromList(list any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetFeedsFromList", reflect.TypeOf((*MockStorage)(nil).GetFeedsFromList), list)
+}
+
+// HasParsedFeedCache mocks base method.
+func (m *MockStorage) HasParsedFeedCache(name string) bool {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "HasParsedFeedCache", name)
+	ret0, _ := ret[0].(bool)
+	return ret0
+}
+
+// HasParsedFeedCache indicates an expected call of HasParsedFeedCache.
+func (mr *MockStorageMockRecorder) HasParsedFeedCache(name any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "HasParsedFeedCache", reflect.TypeOf((*MockStorage)(nil).HasParsedFeedCache), name)
+}
+
+// Init mocks base method.
+func (m *MockStorage) Init(version string) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Init", version)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// Init indicates an expected call of Init.
+func (mr *MockStorageMockRecorder) Init(version any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Init", reflect.TypeOf((*MockStorage)(nil).Init), version)
+}
+
+// JoinCacheDir mocks base method.
+func (m *MockStorage) JoinCacheDir(file string) (string, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "JoinCacheDir", file)
+	ret0, _ := ret[0].(string)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// JoinCacheDir indicates an expected call of JoinCacheDir.
+func (mr *MockStorageMockRecorder) JoinCacheDir(file any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "JoinCacheDir", reflect.TypeOf((*MockStorage)(nil).JoinCacheDir), file)
+}
+
+// JoinConfigDir mocks base method.
+func (m *MockStorage) JoinConfigDir(file string) (string, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "JoinConfigDir", file)
+	ret0, _ := ret[0].(string)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// JoinConfigDir indicates an expected call of JoinConfigDir.
+func (mr *MockStorageMockRecorder) JoinConfigDir(file any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "JoinConfigDir", reflect.TypeOf((*MockStorage)(nil).JoinConfigDir), file)
+}
+
+// LoadArchive mocks base method.
+func (m *MockStorage) LoadArchive(name string) ([]*storage.ArchiveItem, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "LoadArchive", name)
+	ret0, _ := ret[0].([]*storage.ArchiveItem)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// LoadArchive indicates an expected call of LoadArchive.
+func (mr *MockStorageMockRecorder) LoadArchive(name any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "LoadArchive", reflect.TypeOf((*MockStorage)(nil).LoadArchive), name)
+}
+
+// LoadCacheInfo mocks base method.
+func (m *MockStorage) LoadCacheInfo() (map[string]*storage.CacheInfoItem, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "LoadCacheInfo")
+	ret0, _ := ret[0].(map[string]*storage.CacheInfoItem)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// LoadCacheInfo indicates an expected call of LoadCacheInfo.
+func (mr *MockStorageMockRecorder) LoadCacheInfo() *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "LoadCacheInfo", reflect.TypeOf((*MockStorage)(nil).LoadCacheInfo))
+}
+
+// LoadConfig mocks base method.
+func (m *MockStorage) LoadConfig() (*storage.Config, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "LoadConfig")
+	ret0, _ := ret[0].(*storage.Config)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// LoadConfig indicates an expected call of LoadConfig.
+func (mr *MockStorageMockRecorder) LoadConfig() *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "LoadConfig", reflect.TypeOf((*MockStorage)(nil).LoadConfig))
+}
+
+// LoadDownloads mocks base method.
+func (m *MockStorage) LoadDownloads(name string) (map[string]time.Time, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "LoadDownloads", name)
+	ret0, _ := ret[0].(map[string]time.Time)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// LoadDownloads indicates an expected call of LoadDownloads.
+func (mr *MockStorageMockRecorder) LoadDownloads(name any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "LoadDownloads", reflect.TypeOf((*MockStorage)(nil).LoadDownloads), name)
+}
+
+// LoadFeedsFromList mocks base method.
+func (m_2 *MockStorage) LoadFeedsFromList(m map[string]*storage.ListItem, list string) error {
+	m_2.ctrl.T.Helper()
+	ret := m_2.ctrl.Call(m_2, "LoadFeedsFromList", m, list)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// LoadFeedsFromList indicates an expected call of LoadFeedsFromList.
+func (mr *MockStorageMockRecorder) LoadFeedsFromList(m, list any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "LoadFeedsFromList", reflect.TypeOf((*MockStorage)(nil).LoadFeedsFromList), m, list)
+}
+
+// LoadFetchHistory mocks base method.
+func (m *MockStorage) LoadFetchHistory(name string) ([]*storage.FetchHistoryItem, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "LoadFetchHistory", name)
+	ret0, _ := ret[0].([]*storage.FetchHistoryItem)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// LoadFetchHistory indicates an expected call of LoadFetchHistory.
+func (mr *MockStorageMockRecorder) LoadFetchHistory(name any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "LoadFetchHistory", reflect.TypeOf((*MockStorage)(nil).LoadFetchHistory), name)
+}
+
+// LoadJournal mocks base method.
+func (m *MockStorage) LoadJournal() ([]*storage.JournalEntry, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "LoadJournal")
+	ret0, _ := ret[0].([]*storage.JournalEntry)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// LoadJournal indicates an expected call of LoadJournal.
+func (mr *MockStorageMockRecorder) LoadJournal() *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "LoadJournal", reflect.TypeOf((*MockStorage)(nil).LoadJournal))
+}
+
+// LoadLists mocks base method.
+func (m *MockStorage) LoadLists() ([]string, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "LoadLists")
+	ret0, _ := ret[0].([]string)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// LoadLists indicates an expected call of LoadLists.
+func (mr *MockStorageMockRecorder) LoadLists() *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "LoadLists", reflect.TypeOf((*MockStorage)(nil).LoadLists))
+}
+
+// LoadParsedFeedCache mocks base method.
+func (m *MockStorage) LoadParsedFeedCache(name string) (*gofeed.Feed, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "LoadParsedFeedCache", name)
+	ret0, _ := ret[0].(*gofeed.Feed)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// LoadParsedFeedCache indicates an expected call of LoadParsedFeedCache.
+func (mr *MockStorageMockRecorder) LoadParsedFeedCache(name any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "LoadParsedFeedCache", reflect.TypeOf((*MockStorage)(nil).LoadParsedFeedCache), name)
+}
+
+// LoadReadState mocks base method.
+func (m *MockStorage) LoadReadState(name string) (map[string]time.Time, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "LoadReadState", name)
+	ret0, _ := ret[0].(map[string]time.Time)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// LoadReadState indicates an expected call of LoadReadState.
+func (mr *MockStorageMockRecorder) LoadReadState(name any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "LoadReadState", reflect.TypeOf((*MockStorage)(nil).LoadReadState), name)
+}
+
+// LoadTimeline mocks base method.
+func (m *MockStorage) LoadTimeline() ([]*storage.TimelineItem, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "LoadTimeline")
+	ret0, _ := ret[0].([]*storage.TimelineItem)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// LoadTimeline indicates an expected call of LoadTimeline.
+func (mr *MockStorageMockRecorder) LoadTimeline() *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "LoadTimeline", reflect.TypeOf((*MockStorage)(nil).LoadTimeline))
+}
+
+// MarkDownloaded mocks base method.
+func (m *MockStorage) MarkDownloaded(ids []string, name string) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "MarkDownloaded", ids, name)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// MarkDownloaded indicates an expected call of MarkDownloaded.
+func (mr *MockStorageMockRecorder) MarkDownloaded(ids, name any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "MarkDownloaded", reflect.TypeOf((*MockStorage)(nil).MarkDownloaded), ids, name)
+}
+
+// MarkRead mocks base method.
+func (m *MockStorage) MarkRead(ids []string, name string) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "MarkRead", ids, name)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// MarkRead indicates an expected call of MarkRead.
+func (mr *MockStorageMockRecorder) MarkRead(ids, name any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "MarkRead", reflect.TypeOf((*MockStorage)(nil).MarkRead), ids, name)
+}
+
+// MergeLists mocks base method.
+func (m *MockStorage) MergeLists(list, otherList string) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "MergeLists", list, otherList)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// MergeLists indicates an expected call of MergeLists.
+func (mr *MockStorageMockRecorder) MergeLists(list, otherList any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "MergeLists", reflect.TypeOf((*MockStorage)(nil).MergeLists), list, otherList)
+}
+
+// MoveFeeds mocks base method.
+func (m *MockStorage) MoveFeeds(urls []string, from, to string) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "MoveFeeds", urls, from, to)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// MoveFeeds indicates an expected call of MoveFeeds.
+func (mr *MockStorageMockRecorder) MoveFeeds(urls, from, to any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "MoveFeeds", reflect.TypeOf((*MockStorage)(nil).MoveFeeds), urls, from, to)
+}
+
+// OpenFeedCache mocks base method.
+func (m *MockStorage) OpenFeedCache(name string) (io.ReadCloser, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "OpenFeedCache", name)
+	ret0, _ := ret[0].(io.ReadCloser)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// OpenFeedCache indicates an expected call of OpenFeedCache.
+func (mr *MockStorageMockRecorder) OpenFeedCache(name any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "OpenFeedCache", reflect.TypeOf((*MockStorage)(nil).OpenFeedCache), name)
+}
+
+// OpenSearchIndex mocks base method.
+func (m *MockStorage) OpenSearchIndex() (io.ReadCloser, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "OpenSearchIndex")
+	ret0, _ := ret[0].(io.ReadCloser)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// OpenSearchIndex indicates an expected call of OpenSearchIndex.
+func (mr *MockStorageMockRecorder) OpenSearchIndex() *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "OpenSearchIndex", reflect.TypeOf((*MockStorage)(nil).OpenSearchIndex))
+}
+
+// PruneArchive mocks base method.
+func (m *MockStorage) PruneArchive(name string, retention *storage.ArchiveRetention) (int, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "PruneArchive", name, retention)
+	ret0, _ := ret[0].(int)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// PruneArchive indicates an expected call of PruneArchive.
+func (mr *MockStorageMockRecorder) PruneArchive(name, retention any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "PruneArchive", reflect.TypeOf((*MockStorage)(nil).PruneArchive), name, retention)
+}
+
+// RemoveExploreRepository mocks base method.
+func (m *MockStorage) RemoveExploreRepository(name string) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "RemoveExploreRepository", name)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// RemoveExploreRepository indicates an expected call of RemoveExploreRepository.
+func (mr *MockStorageMockRecorder) RemoveExploreRepository(name any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "RemoveExploreRepository", reflect.TypeOf((*MockStorage)(nil).RemoveExploreRepository), name)
+}
+
+// RemoveFeedCaches mocks base method.
+func (m *MockStorage) RemoveFeedCaches(names []string) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "RemoveFeedCaches", names)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// RemoveFeedCaches indicates an expected call of RemoveFeedCaches.
+func (mr *MockStorageMockRecorder) RemoveFeedCaches(names any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "RemoveFeedCaches", reflect.TypeOf((*MockStorage)(nil).RemoveFeedCaches), names)
+}
+
+// RemoveFromList mocks base method.
+func (m *MockStorage) RemoveFromList(urls []string, list string) ([]bool, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "RemoveFromList", urls, list)
+	ret0, _ := ret[0].([]bool)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// RemoveFromList indicates an expected call of RemoveFromList.
+func (mr *MockStorageMockRecorder) RemoveFromList(urls, list any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "RemoveFromList", reflect.TypeOf((*MockStorage)(nil).RemoveFromList), urls, list)
+}
+
+// RemoveList mocks base method.
+func (m *MockStorage) RemoveList(list string) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "RemoveList", list)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// RemoveList indicates an expected call of RemoveList.
+func (mr *MockStorageMockRecorder) RemoveList(list any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "RemoveList", reflect.TypeOf((*MockStorage)(nil).RemoveList), list)
+}
+
+// RenameList mocks base method.
+func (m *MockStorage) RenameList(oldName, newName string) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "RenameList", oldName, newName)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// RenameList indicates an expected call of RenameList.
+func (mr *MockStorageMockRecorder) RenameList(oldName, newName any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "RenameList", reflect.TypeOf((*MockStorage)(nil).RenameList), oldName, newName)
+}
+
+// SaveFeedCache mocks base method.
+func (m *MockStorage) SaveFeedCache(r io.Reader, name string) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "SaveFeedCache", r, name)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// SaveFeedCache indicates an expected call of SaveFeedCache.
+func (mr *MockStorageMockRecorder) SaveFeedCache(r, name any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "SaveFeedCache", reflect.TypeOf((*MockStorage)(nil).SaveFeedCache), r, name)
+}
+
+// SaveParsedFeedCache mocks base method.
+func (m *MockStorage) SaveParsedFeedCache(feed *gofeed.Feed, name string) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "SaveParsedFeedCache", feed, name)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// SaveParsedFeedCache indicates an expected call of SaveParsedFeedCache.
+func (mr *MockStorageMockRecorder) SaveParsedFeedCache(feed, name any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "SaveParsedFeedCache", reflect.TypeOf((*MockStorage)(nil).SaveParsedFeedCache), feed, name)
+}
+
+// SaveSearchIndex mocks base method.
+func (m *MockStorage) SaveSearchIndex(r io.Reader) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "SaveSearchIndex", r)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// SaveSearchIndex indicates an expected call of SaveSearchIndex.
+func (mr *MockStorageMockRecorder) SaveSearchIndex(r any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "SaveSearchIndex", reflect.TypeOf((*MockStorage)(nil).SaveSearchIndex), r)
+}
+
+// SaveTimeline mocks base method.
+func (m *MockStorage) SaveTimeline(timeline []*storage.TimelineItem) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "SaveTimeline", timeline)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// SaveTimeline indicates an expected call of SaveTimeline.
+func (mr *MockStorageMockRecorder) SaveTimeline(timeline any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "SaveTimeline", reflect.TypeOf((*MockStorage)(nil).SaveTimeline), timeline)
+}
+
+// SetCacheBackend mocks base method.
+func (m *MockStorage) SetCacheBackend(backend string) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "SetCacheBackend", backend)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// SetCacheBackend indicates an expected call of SetCacheBackend.
+func (mr *MockStorageMockRecorder) SetCacheBackend(backend any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "SetCacheBackend", reflect.TypeOf((*MockStorage)(nil).SetCacheBackend), backend)
+}
+
+// Undo mocks base method.
+func (m *MockStorage) Undo() (*storage.JournalEntry, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Undo")
+	ret0, _ := ret[0].(*storage.JournalEntry)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// Undo indicates an expected call of Undo.
+func (mr *MockStorageMockRecorder) Undo() *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Undo", reflect.TypeOf((*MockStorage)(nil).Undo))
+}
+
+// UpdateCacheInfo mocks base method.
+func (m *MockStorage) UpdateCacheInfo(update func(map[string]*storage.CacheInfoItem) error) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "UpdateCacheInfo", update)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// UpdateCacheInfo indicates an expected call of UpdateCacheInfo.
+func (mr *MockStorageMockRecorder) UpdateCacheInfo(update any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UpdateCacheInfo", reflect.TypeOf((*MockStorage)(nil).UpdateCacheInfo), update)
+}
+
+// UpdateConfig mocks base method.
+func (m *MockStorage) UpdateConfig(update func(*storage.Config) error) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "UpdateConfig", update)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// UpdateConfig indicates an expected call of UpdateConfig.
+func (mr *MockStorageMockRecorder) UpdateConfig(update any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UpdateConfig", reflect.TypeOf((*MockStorage)(nil).UpdateConfig), update)
+}